@@ -1,14 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"os"
+	"prestadores-api/internal/auth"
+	"prestadores-api/internal/database"
+	"prestadores-api/internal/handler/adjuntos"
 	"prestadores-api/internal/handler/afiliados"
 	"prestadores-api/internal/handler/autorizaciones"
+	"prestadores-api/internal/handler/especialidades"
 	"prestadores-api/internal/handler/login"
+	"prestadores-api/internal/handler/nomenclador"
+	"prestadores-api/internal/handler/planes"
 	"prestadores-api/internal/handler/recetas"
 	"prestadores-api/internal/handler/reintegros"
 	"prestadores-api/internal/handler/situaciones"
+	"prestadores-api/internal/handler/usuarios"
+	"prestadores-api/internal/handler/vademecum"
+	"prestadores-api/internal/middleware"
+	"prestadores-api/internal/model"
 	"prestadores-api/internal/repository"
 	"prestadores-api/internal/service"
+	"prestadores-api/internal/storage"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -20,6 +34,14 @@ func main() {
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
 
+	// Subcomando de migraciones: go run cmd/main.go migrate up | down [n] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(logger, os.Args[2:]); err != nil {
+			logger.Fatal("Error al ejecutar migraciones", zap.Error(err))
+		}
+		return
+	}
+
 	r := gin.Default()
 
 	// CORS: debe ir antes de las rutas
@@ -32,30 +54,219 @@ func main() {
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Repository y Service de autorizaciones
-	autorizacionRepo := repository.NewAutorizacionRepository()
-	autorizacionService := service.NewAutorizacionService(autorizacionRepo, logger)
+	// Repositories de solicitudes: en memoria por defecto, SQL si se configura DB_DRIVER
+	var (
+		autorizacionRepo repository.AutorizacionRepository
+		recetaRepo       repository.RecetaRepository
+		reintegroRepo    repository.ReintegroRepository
+		adjuntoRepo      repository.AdjuntoRepository
+		cuentaCobroRepo  repository.CuentaCobroRepository
+		situacionRepo    repository.SituacionRepository
+		usuarioRepo      repository.UsuarioRepository
+		refreshTokenRepo repository.RefreshTokenRepository
+		planRepo         repository.PlanRepository
+		afiliadoRepo     repository.AfiliadoRepository
+		vademecumRepo    repository.VademecumRepository
+		err              error
+	)
+
+	dbConfig := database.ConfigFromEnv()
+	if dbConfig.Driver == database.DriverMemory {
+		autorizacionRepo = repository.NewAutorizacionRepository()
+		recetaRepo = repository.NewRecetaRepository()
+		reintegroRepo = repository.NewReintegroRepository()
+		adjuntoRepo = repository.NewAdjuntoRepository()
+		cuentaCobroRepo = repository.NewCuentaCobroRepository()
+		situacionRepo = repository.NewSituacionRepository()
+		usuarioRepo = repository.NewUsuarioRepository()
+		refreshTokenRepo = repository.NewRefreshTokenRepository()
+		planRepo = repository.NewPlanRepository()
+		afiliadoRepo = repository.NewAfiliadoRepository()
+
+		// Vademécum: el CSV embebido o el indicado en VADEMECUM_CSV; un ADMIN puede reemplazarlo importando otro
+		vademecumRepo, err = repository.NewVademecumRepository(os.Getenv("VADEMECUM_CSV"))
+		if err != nil {
+			logger.Fatal("Error al cargar el vademécum", zap.Error(err))
+		}
+	} else {
+		db, err := database.Open(dbConfig)
+		if err != nil {
+			logger.Fatal("Error al conectar con la base de datos", zap.Error(err))
+		}
+		defer db.Close()
+
+		migrator, err := database.NewMigrator(db, dbConfig.Driver)
+		if err != nil {
+			logger.Fatal("Error al cargar migraciones", zap.Error(err))
+		}
+
+		if os.Getenv("DB_AUTO_MIGRATE") == "true" {
+			aplicadas, err := migrator.Up()
+			if err != nil {
+				logger.Fatal("Error al aplicar migraciones", zap.Error(err))
+			}
+			logger.Info("Migraciones aplicadas al iniciar", zap.Int("cantidad", len(aplicadas)))
+		}
+
+		// No levantamos la API con un esquema atrasado
+		if err := migrator.EnsureUpToDate(); err != nil {
+			logger.Fatal("Esquema de base de datos desactualizado, ejecutar `go run cmd/main.go migrate up`", zap.Error(err))
+		}
+
+		// Las migraciones dejan vacío el texto de búsqueda de las filas que hay que volver a indexar
+		indexadas, err := repository.IndexarBusquedaPendiente(db)
+		if err != nil {
+			logger.Fatal("Error al indexar la búsqueda", zap.Error(err))
+		}
+		if indexadas > 0 {
+			logger.Info("Texto de búsqueda recalculado", zap.Int("filas", indexadas))
+		}
 
-	// Repository y Service de recetas
-	recetaRepo := repository.NewRecetaRepository()
-	recetaService := service.NewRecetaService(recetaRepo, logger)
+		autorizacionRepo = repository.NewAutorizacionSQLRepository(db)
+		recetaRepo = repository.NewRecetaSQLRepository(db)
+		reintegroRepo = repository.NewReintegroSQLRepository(db)
+		adjuntoRepo = repository.NewAdjuntoSQLRepository(db)
+		cuentaCobroRepo = repository.NewCuentaCobroSQLRepository(db)
+		situacionRepo = repository.NewSituacionSQLRepository(db)
+		usuarioRepo = repository.NewUsuarioSQLRepository(db)
+		refreshTokenRepo = repository.NewRefreshTokenSQLRepository(db)
+		planRepo = repository.NewPlanSQLRepository(db)
+		afiliadoRepo = repository.NewAfiliadoSQLRepository(db)
 
-	// Repository y Service de Reintegros
-	reintegroRepo := repository.NewReintegroRepository()
-	reintegroService := service.NewReintegroService(reintegroRepo, logger)
+		// El último vademécum importado queda en la base; hasta la primera importación se usa el CSV
+		vademecumRepo, err = repository.NewVademecumSQLRepository(db, os.Getenv("VADEMECUM_CSV"))
+		if err != nil {
+			logger.Fatal("Error al cargar el vademécum", zap.Error(err))
+		}
+	}
+	logger.Info("Repositorios inicializados", zap.String("driver", dbConfig.Driver))
+
+	// Catálogo de especialidades, referenciado por el nomenclador, las solicitudes y los turnos
+	especialidadRepo := repository.NewEspecialidadRepository()
+
+	// Nomenclador de prestaciones: el CSV embebido o el indicado en NOMENCLADOR_CSV
+	nomencladorRepo, err := repository.NewNomencladorRepository(os.Getenv("NOMENCLADOR_CSV"), especialidadRepo)
+	if err != nil {
+		logger.Fatal("Error al cargar el nomenclador", zap.Error(err))
+	}
+
+	// Interacciones entre drogas que se controlan al crear recetas: el CSV embebido o el indicado en INTERACCIONES_CSV
+	interaccionRepo, err := repository.NewInteraccionRepository(os.Getenv("INTERACCIONES_CSV"))
+	if err != nil {
+		logger.Fatal("Error al cargar las interacciones medicamentosas", zap.Error(err))
+	}
+
+	// Archivos adjuntos a las solicitudes: en disco (STORAGE_DIR) o en un bucket S3 compatible
+	storageAdjuntos, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		logger.Fatal("Error al inicializar el almacenamiento de adjuntos", zap.Error(err))
+	}
+	configAdjuntos, err := service.ConfigAdjuntosFromEnv()
+	if err != nil {
+		logger.Fatal("Configuración de adjuntos inválida", zap.Error(err))
+	}
+
+	// Elegibilidad: estado del afiliado, vigencia del plan, carencias y cobertura por prestación
+	elegibilidadService := service.NewElegibilidadService(afiliadoRepo, planRepo, logger)
+
+	// Service de autorizaciones
+	autorizacionService := service.NewAutorizacionService(autorizacionRepo, afiliadoRepo, nomencladorRepo, especialidadRepo, elegibilidadService, adjuntoRepo, logger)
+
+	// Service de recetas. La vigencia se cuenta desde la aprobación y un job vence las recetas cumplidas.
+	vigenciaRecetas, err := service.VigenciaRecetasFromEnv()
+	if err != nil {
+		logger.Fatal("Configuración de vigencia de recetas inválida", zap.Error(err))
+	}
+	recetaService := service.NewRecetaService(recetaRepo, afiliadoRepo, vademecumRepo, interaccionRepo, elegibilidadService, vigenciaRecetas, logger)
+	service.IniciarVencimientoRecetas(context.Background(), recetaService, vigenciaRecetas.IntervaloVencimiento, logger)
+
+	// Receta electrónica: el QR del PDF apunta a RECETA_VERIFICACION_URL/<código>
+	urlVerificacion := os.Getenv("RECETA_VERIFICACION_URL")
+	if urlVerificacion == "" {
+		urlVerificacion = "http://localhost:8080/v1/prestadores/recetas/verificar"
+	}
+	recetaElectronicaService := service.NewRecetaElectronicaService(recetaRepo, urlVerificacion, logger)
 
-	// Repository y Service de Situaciones terapéuticas
-	situacionRepo := repository.NewSituacionRepository()
-	situacionService := service.NewSituacionService(situacionRepo, logger)
+	// Service de Reintegros
+	reintegroService := service.NewReintegroService(reintegroRepo, afiliadoRepo, nomencladorRepo, especialidadRepo, autorizacionRepo, elegibilidadService, adjuntoRepo, cuentaCobroRepo, logger)
+
+	// Comprobantes de autorizaciones y reintegros
+	adjuntoService := service.NewAdjuntoService(adjuntoRepo, storageAdjuntos, autorizacionRepo, reintegroRepo, configAdjuntos, logger)
+
+	// Autenticación de prestadores
+	authConfig, err := auth.ConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Configuración de autenticación inválida", zap.Error(err))
+	}
+	if authConfig.SecretGenerado {
+		logger.Warn("JWT_SECRET no definido: se usa un secreto aleatorio y los tokens no sobreviven a un reinicio")
+	}
+	tokenManager := auth.NewTokenManager(authConfig.Secret, authConfig.AccessTokenTTL)
+	authService := service.NewAuthService(
+		usuarioRepo,
+		refreshTokenRepo,
+		tokenManager,
+		auth.NewLimitadorIntentos(authConfig.MaxIntentos, authConfig.Bloqueo),
+		authConfig.RefreshTokenTTL,
+		logger,
+	)
+
+	// Alta de usuarios
+	usuarioService := service.NewUsuarioService(usuarioRepo, logger)
+
+	// ADMIN inicial: con base de datos la tabla de usuarios arranca vacía
+	if cuit, password := os.Getenv("ADMIN_CUIT"), os.Getenv("ADMIN_PASSWORD"); cuit != "" && password != "" {
+		_, err := usuarioService.RegistrarUsuario(model.RegistrarUsuarioRequest{
+			CUIT:     cuit,
+			Nombre:   "Administrador",
+			Password: password,
+			Rol:      model.RolAdmin,
+		})
+		switch {
+		case err == nil:
+			logger.Info("Usuario ADMIN inicial creado", zap.String("cuit", cuit))
+		case errors.Is(err, service.ErrUsuarioExistente):
+			// Ya existía de un inicio anterior
+		default:
+			logger.Fatal("Error al crear el usuario ADMIN inicial", zap.Error(err))
+		}
+	}
+
+	afiliadoService := service.NewAfiliadoService(afiliadoRepo, planRepo, logger)
+
+	// Catálogo de planes médicos
+	planService := service.NewPlanService(planRepo, afiliadoRepo, logger)
+
+	nomencladorService := service.NewNomencladorService(nomencladorRepo, especialidadRepo, logger)
+	especialidadService := service.NewEspecialidadService(especialidadRepo, logger)
+	vademecumService := service.NewVademecumService(vademecumRepo, logger)
+
+	// Turnos de la historia clínica
+	historiaClinicaService := service.NewHistoriaClinicaService(repository.NewTurnoRepository(), afiliadoRepo, especialidadRepo, logger)
+
+	// Service de Situaciones terapéuticas
+	situacionService := service.NewSituacionService(situacionRepo, afiliadoRepo, logger)
+
+	// Cuentas CBU/CVU donde los afiliados cobran los reintegros por transferencia
+	cuentaCobroService := service.NewCuentaCobroService(cuentaCobroRepo, afiliadoRepo, logger)
 
 	// Handlers
-	loginHandler := login.NewLoginHandler(logger)
-	afiliadosHandler := afiliados.NewAfiliadoHandler(logger)
-	historiaHandler := afiliados.NewHistoriaClinicaHandler(logger)
+	loginHandler := login.NewLoginHandler(authService, logger)
+	usuarioHandler := usuarios.NewUsuarioHandler(usuarioService, logger)
+	afiliadosHandler := afiliados.NewAfiliadoHandler(afiliadoService, logger)
+	historiaHandler := afiliados.NewHistoriaClinicaHandler(historiaClinicaService, logger)
+	elegibilidadHandler := afiliados.NewElegibilidadHandler(elegibilidadService, nomencladorService, logger)
+	cuentaCobroHandler := afiliados.NewCuentaCobroHandler(cuentaCobroService, logger)
+	planHandler := planes.NewPlanHandler(planService, logger)
+	nomencladorHandler := nomenclador.NewNomencladorHandler(nomencladorService, logger)
+	especialidadHandler := especialidades.NewEspecialidadHandler(especialidadService, logger)
+	vademecumHandler := vademecum.NewVademecumHandler(vademecumService, logger)
 	autorizacionHandler := autorizaciones.NewAutorizacionHandler(autorizacionService, logger)
 	recetaHandler := recetas.NewRecetaHandler(recetaService, logger)
+	recetaElectronicaHandler := recetas.NewRecetaElectronicaHandler(recetaElectronicaService, logger)
 	reintegroHandler := reintegros.NewReintegroHandler(reintegroService, logger)
 	situacionHandler := situaciones.NewSituacionHandler(situacionService, logger)
+	adjuntoHandler := adjuntos.NewAdjuntoHandler(adjuntoService, configAdjuntos.MaxBytes, logger)
 
 	// Rutas /v1/prestadores
 	v1 := r.Group("/v1/prestadores")
@@ -65,58 +276,120 @@ func main() {
 			c.JSON(200, gin.H{"message": "pong"})
 		})
 
-		// Login
+		// Login (públicas: todavía no hay access token)
 		v1.POST("/login", loginHandler.Login)
+		v1.POST("/refresh", loginHandler.Refresh)
+
+		// Verificación de recetas electrónicas (pública: la usan las farmacias con el código o el QR)
+		v1.GET("/recetas/verificar/:codigo", recetaElectronicaHandler.VerificarReceta)
+
+		// Todo lo que sigue exige un access token válido
+		protegidas := v1.Group("", middleware.Auth(tokenManager, logger))
+
+		protegidas.POST("/logout", loginHandler.Logout)
+
+		// Permisos por rol de cada ruta (ver auth.permisosPorRol)
+		permiso := func(p auth.Permiso) gin.HandlerFunc {
+			return middleware.RequierePermiso(p, logger)
+		}
+
+		// Dispensa de recetas electrónicas: el CUIT de la farmacia sale del token
+		protegidas.POST("/recetas/verificar/:codigo/dispensar", permiso(auth.PermisoDispensarRecetas), recetaElectronicaHandler.DispensarReceta)
+
+		// Usuarios
+		protegidas.POST("/usuarios", permiso(auth.PermisoGestionarUsuarios), usuarioHandler.RegistrarUsuario)
+
+		// Planes médicos
+		planesGroup := protegidas.Group("/planes")
+		{
+			planesGroup.GET("", permiso(auth.PermisoVerPlanes), planHandler.GetPlanes)
+			planesGroup.GET("/:planId", permiso(auth.PermisoVerPlanes), planHandler.GetPlanByID)
+			planesGroup.POST("", permiso(auth.PermisoGestionarPlanes), planHandler.CreatePlan)
+			planesGroup.PUT("/:planId", permiso(auth.PermisoGestionarPlanes), planHandler.UpdatePlan)
+			planesGroup.DELETE("/:planId", permiso(auth.PermisoGestionarPlanes), planHandler.DeletePlan)
+		}
+
+		// Nomenclador de prestaciones (typeahead de procedimientos y prestaciones)
+		nomencladorGroup := protegidas.Group("/nomenclador", permiso(auth.PermisoVerNomenclador))
+		{
+			nomencladorGroup.GET("", nomencladorHandler.BuscarPrestaciones) // ?q=&especialidad=&limit=
+			nomencladorGroup.GET("/:codigo", nomencladorHandler.GetPrestacion)
+		}
+
+		// Vademécum (búsqueda de medicamentos para recetas)
+		vademecumGroup := protegidas.Group("/vademecum")
+		{
+			vademecumGroup.GET("", permiso(auth.PermisoVerVademecum), vademecumHandler.BuscarMedicamentos) // ?q=&condicionVenta=&limit=
+			vademecumGroup.GET("/:codigo", permiso(auth.PermisoVerVademecum), vademecumHandler.GetMedicamento)
+			vademecumGroup.PUT("", permiso(auth.PermisoImportarVademecum), vademecumHandler.ImportarVademecum) // body: CSV
+		}
+
+		// Especialidades (catálogo para selects)
+		protegidas.GET("/especialidades", permiso(auth.PermisoVerNomenclador), especialidadHandler.GetEspecialidades)
 
 		// Afiliados
-		afiliadosGroup := v1.Group("/afiliados")
+		afiliadosGroup := protegidas.Group("/afiliados", permiso(auth.PermisoVerAfiliados))
 		{
 			afiliadosGroup.GET("", afiliadosHandler.GetAfiliados)
 			afiliado := afiliadosGroup.Group("/:afiliadoId")
 			{
 				afiliado.GET("", afiliadosHandler.GetAfiliadoDetalle)
-				afiliado.GET("/historia-clinica", historiaHandler.GetHistoriaClinica)
+				afiliado.GET("/historia-clinica", historiaHandler.GetHistoriaClinica) // ?prestadorId=&especialidad=
+				afiliado.POST("/turnos", permiso(auth.PermisoGestionarTurnos), historiaHandler.CreateTurno)
+				afiliado.PATCH("/turnos/:turnoId", permiso(auth.PermisoGestionarTurnos), historiaHandler.UpdateTurno)
+				afiliado.GET("/elegibilidad", elegibilidadHandler.GetElegibilidad) // ?prestacion= o ?codigo=
 				// Situaciones terapéuticas
 
-				afiliado.GET("/situaciones", situacionHandler.GetSituaciones)                               // ?scope=grupo
-				afiliado.POST("/situaciones", situacionHandler.CreateSituacion)                             // alta
-				afiliado.PATCH("/situaciones/:situacionId", situacionHandler.PatchSituacion)                // ej. fechaFin
-				afiliado.PATCH("/situaciones/:situacionId/estado", situacionHandler.CambiarEstadoSituacion) // ALTA/BAJA/ACTIVA
-				afiliado.DELETE("/situaciones/:situacionId", situacionHandler.DeleteSituacion)              // baja física (opcional)
+				afiliado.GET("/situaciones", situacionHandler.GetSituaciones)                                                                          // ?scope=grupo
+				afiliado.POST("/situaciones", permiso(auth.PermisoGestionarSituaciones), situacionHandler.CreateSituacion)                             // alta
+				afiliado.PATCH("/situaciones/:situacionId", permiso(auth.PermisoGestionarSituaciones), situacionHandler.PatchSituacion)                // ej. fechaFin
+				afiliado.PATCH("/situaciones/:situacionId/estado", permiso(auth.PermisoGestionarSituaciones), situacionHandler.CambiarEstadoSituacion) // ALTA/BAJA/ACTIVA
+				afiliado.DELETE("/situaciones/:situacionId", permiso(auth.PermisoGestionarSituaciones), situacionHandler.DeleteSituacion)              // baja física (opcional)
+				// Cuentas de cobro de reintegros
+				afiliado.GET("/cuentas-cobro", cuentaCobroHandler.GetCuentasCobro)
+				afiliado.POST("/cuentas-cobro", permiso(auth.PermisoGestionarCuentas), cuentaCobroHandler.CreateCuentaCobro)
+				afiliado.DELETE("/cuentas-cobro/:cuentaId", permiso(auth.PermisoGestionarCuentas), cuentaCobroHandler.BajaCuentaCobro) // baja lógica
 			}
 		}
 
 		// Solicitudes
-		solicitudes := v1.Group("/solicitudes")
+		solicitudes := protegidas.Group("/solicitudes")
 		{
 			// Autorizaciones
 			autorizacionesGroup := solicitudes.Group("/autorizaciones")
 			{
-				autorizacionesGroup.GET("", autorizacionHandler.GetAutorizaciones)
-				autorizacionesGroup.GET("/:id", autorizacionHandler.GetAutorizacionByID)
-				autorizacionesGroup.POST("", autorizacionHandler.CreateAutorizacion)
-				autorizacionesGroup.PATCH("/:id", autorizacionHandler.UpdateAutorizacion)
-				autorizacionesGroup.PATCH("/:id/estado", autorizacionHandler.CambiarEstadoAutorizacion)
+				autorizacionesGroup.GET("", permiso(auth.PermisoVerSolicitudes), autorizacionHandler.GetAutorizaciones)
+				autorizacionesGroup.GET("/:id", permiso(auth.PermisoVerSolicitudes), autorizacionHandler.GetAutorizacionByID)
+				autorizacionesGroup.POST("", permiso(auth.PermisoCrearSolicitudes), autorizacionHandler.CreateAutorizacion)
+				autorizacionesGroup.PATCH("/:id", permiso(auth.PermisoEditarSolicitudes), autorizacionHandler.UpdateAutorizacion)
+				autorizacionesGroup.PATCH("/:id/estado", permiso(auth.PermisoCambiarEstado), autorizacionHandler.CambiarEstadoAutorizacion)
+				autorizacionesGroup.GET("/:id/adjuntos", permiso(auth.PermisoVerSolicitudes), adjuntoHandler.GetAdjuntos(model.TipoAutorizacion))
+				autorizacionesGroup.GET("/:id/adjuntos/:adjuntoId", permiso(auth.PermisoVerSolicitudes), adjuntoHandler.DescargarAdjunto(model.TipoAutorizacion))
+				autorizacionesGroup.POST("/:id/adjuntos", permiso(auth.PermisoAdjuntarComprobantes), adjuntoHandler.SubirAdjunto(model.TipoAutorizacion)) // multipart: archivo, categoria
 			}
 
 			// Recetas
 			recetasGroup := solicitudes.Group("/recetas")
 			{
-				recetasGroup.GET("", recetaHandler.GetRecetas)
-				recetasGroup.GET("/:id", recetaHandler.GetRecetaByID)
-				recetasGroup.POST("", recetaHandler.CreateReceta)
-				recetasGroup.PUT("/:id", recetaHandler.UpdateReceta)
-				recetasGroup.PATCH("/:id/estado", recetaHandler.CambiarEstadoReceta)
+				recetasGroup.GET("", permiso(auth.PermisoVerSolicitudes), recetaHandler.GetRecetas)
+				recetasGroup.GET("/:id", permiso(auth.PermisoVerSolicitudes), recetaHandler.GetRecetaByID)
+				recetasGroup.GET("/:id/pdf", permiso(auth.PermisoVerSolicitudes), recetaElectronicaHandler.GetRecetaPDF)
+				recetasGroup.POST("", permiso(auth.PermisoCrearSolicitudes), recetaHandler.CreateReceta)
+				recetasGroup.PUT("/:id", permiso(auth.PermisoEditarSolicitudes), recetaHandler.UpdateReceta)
+				recetasGroup.PATCH("/:id/estado", permiso(auth.PermisoCambiarEstado), recetaHandler.CambiarEstadoReceta)
 			}
 
 			// Reintegros
 			reintegrosGroup := solicitudes.Group("/reintegros")
 			{
-				reintegrosGroup.GET("", reintegroHandler.GetReintegros)
-				reintegrosGroup.GET("/:id", reintegroHandler.GetReintegroByID)
-				reintegrosGroup.POST("", reintegroHandler.CreateReintegro)
-				reintegrosGroup.PUT("/:id", reintegroHandler.UpdateReintegro)
-				reintegrosGroup.PATCH("/:id/estado", reintegroHandler.CambiarEstadoReintegro)
+				reintegrosGroup.GET("", permiso(auth.PermisoVerSolicitudes), reintegroHandler.GetReintegros)
+				reintegrosGroup.GET("/:id", permiso(auth.PermisoVerSolicitudes), reintegroHandler.GetReintegroByID)
+				reintegrosGroup.POST("", permiso(auth.PermisoCrearSolicitudes), reintegroHandler.CreateReintegro)
+				reintegrosGroup.PUT("/:id", permiso(auth.PermisoEditarSolicitudes), reintegroHandler.UpdateReintegro)
+				reintegrosGroup.PATCH("/:id/estado", permiso(auth.PermisoCambiarEstado), reintegroHandler.CambiarEstadoReintegro)
+				reintegrosGroup.GET("/:id/adjuntos", permiso(auth.PermisoVerSolicitudes), adjuntoHandler.GetAdjuntos(model.TipoReintegro))
+				reintegrosGroup.GET("/:id/adjuntos/:adjuntoId", permiso(auth.PermisoVerSolicitudes), adjuntoHandler.DescargarAdjunto(model.TipoReintegro))
+				reintegrosGroup.POST("/:id/adjuntos", permiso(auth.PermisoAdjuntarComprobantes), adjuntoHandler.SubirAdjunto(model.TipoReintegro)) // multipart: archivo, categoria
 			}
 		}
 	}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"prestadores-api/internal/database"
+	"prestadores-api/internal/repository"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// runMigrate implementa el subcomando `migrate up | down [n] | status`
+func runMigrate(logger *zap.Logger, args []string) error {
+	dbConfig := database.ConfigFromEnv()
+	if dbConfig.Driver == database.DriverMemory {
+		return fmt.Errorf("migrate requiere DB_DRIVER=sqlite o DB_DRIVER=postgres")
+	}
+
+	db, err := database.Open(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, dbConfig.Driver)
+	if err != nil {
+		return err
+	}
+
+	comando := "up"
+	if len(args) > 0 {
+		comando = args[0]
+	}
+
+	switch comando {
+	case "up":
+		aplicadas, err := migrator.Up()
+		for _, m := range aplicadas {
+			logger.Info("Migración aplicada", zap.Int("version", m.Version), zap.String("nombre", m.Nombre))
+		}
+		if err != nil {
+			return err
+		}
+		if len(aplicadas) == 0 {
+			logger.Info("El esquema ya está actualizado")
+		}
+
+		indexadas, err := repository.IndexarBusquedaPendiente(db)
+		if err != nil {
+			return err
+		}
+		if indexadas > 0 {
+			logger.Info("Texto de búsqueda recalculado", zap.Int("filas", indexadas))
+		}
+
+	case "down":
+		pasos := 1
+		if len(args) > 1 {
+			pasos, err = strconv.Atoi(args[1])
+			if err != nil || pasos <= 0 {
+				return fmt.Errorf("cantidad de pasos inválida: %q", args[1])
+			}
+		}
+		revertidas, err := migrator.Down(pasos)
+		for _, m := range revertidas {
+			logger.Info("Migración revertida", zap.Int("version", m.Version), zap.String("nombre", m.Nombre))
+		}
+		if err != nil {
+			return err
+		}
+
+	case "status":
+		estados, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, st := range estados {
+			estado := "pendiente"
+			if st.Aplicada {
+				estado = "aplicada " + st.AplicadaEn.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", st.Version, st.Nombre, estado)
+		}
+
+	default:
+		return fmt.Errorf("subcomando desconocido %q (usar up, down [n] o status)", comando)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// LimitadorIntentos bloquea una clave (el CUIT) después de `max` logins fallidos
+// consecutivos durante el tiempo de bloqueo
+type LimitadorIntentos struct {
+	mu       sync.Mutex
+	max      int
+	bloqueo  time.Duration
+	intentos map[string]*registroIntentos
+	now      func() time.Time
+}
+
+type registroIntentos struct {
+	fallidos       int
+	bloqueadoHasta time.Time
+}
+
+func NewLimitadorIntentos(max int, bloqueo time.Duration) *LimitadorIntentos {
+	return &LimitadorIntentos{
+		max:      max,
+		bloqueo:  bloqueo,
+		intentos: make(map[string]*registroIntentos),
+		now:      time.Now,
+	}
+}
+
+// Bloqueado indica si la clave está bloqueada y hasta cuándo
+func (l *LimitadorIntentos) Bloqueado(clave string) (bool, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reg, ok := l.intentos[clave]
+	if !ok || reg.bloqueadoHasta.IsZero() {
+		return false, time.Time{}
+	}
+	if l.now().Before(reg.bloqueadoHasta) {
+		return true, reg.bloqueadoHasta
+	}
+
+	// El bloqueo venció: se empieza a contar de nuevo
+	delete(l.intentos, clave)
+	return false, time.Time{}
+}
+
+// RegistrarFallo suma un intento fallido y bloquea la clave al llegar al máximo
+func (l *LimitadorIntentos) RegistrarFallo(clave string) (bloqueado bool, hasta time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reg, ok := l.intentos[clave]
+	if !ok {
+		reg = &registroIntentos{}
+		l.intentos[clave] = reg
+	}
+
+	reg.fallidos++
+	if reg.fallidos >= l.max {
+		reg.bloqueadoHasta = l.now().Add(l.bloqueo)
+		return true, reg.bloqueadoHasta
+	}
+	return false, time.Time{}
+}
+
+// Reset limpia los intentos fallidos después de un login exitoso
+func (l *LimitadorIntentos) Reset(clave string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.intentos, clave)
+}
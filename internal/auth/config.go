@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config agrupa la configuración de autenticación
+type Config struct {
+	Secret          []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MaxIntentos     int
+	Bloqueo         time.Duration
+
+	// SecretGenerado indica que no se definió JWT_SECRET y se generó uno aleatorio
+	// (los tokens dejan de ser válidos al reiniciar)
+	SecretGenerado bool
+}
+
+// ConfigFromEnv lee JWT_SECRET, ACCESS_TOKEN_TTL, REFRESH_TOKEN_TTL, LOGIN_MAX_INTENTOS y LOGIN_BLOQUEO
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Secret:          []byte(os.Getenv("JWT_SECRET")),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		MaxIntentos:     5,
+		Bloqueo:         15 * time.Minute,
+	}
+
+	if len(cfg.Secret) == 0 {
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			return cfg, fmt.Errorf("error al generar JWT_SECRET: %w", err)
+		}
+		cfg.SecretGenerado = true
+	}
+
+	var err error
+	if cfg.AccessTokenTTL, err = durationEnv("ACCESS_TOKEN_TTL", cfg.AccessTokenTTL); err != nil {
+		return cfg, err
+	}
+	if cfg.RefreshTokenTTL, err = durationEnv("REFRESH_TOKEN_TTL", cfg.RefreshTokenTTL); err != nil {
+		return cfg, err
+	}
+	if cfg.Bloqueo, err = durationEnv("LOGIN_BLOQUEO", cfg.Bloqueo); err != nil {
+		return cfg, err
+	}
+
+	if v := os.Getenv("LOGIN_MAX_INTENTOS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, fmt.Errorf("LOGIN_MAX_INTENTOS inválido: %q", v)
+		}
+		cfg.MaxIntentos = n
+	}
+
+	return cfg, nil
+}
+
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return def, fmt.Errorf("%s inválido: %q", name, v)
+	}
+	return d, nil
+}
@@ -0,0 +1,27 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// hashDummy se compara cuando el usuario no existe, para que la respuesta tarde
+// lo mismo que con un usuario real y no se pueda inferir qué CUITs están registrados.
+// Es el hash de un valor aleatorio que se descartó, con el mismo costo que HashPassword.
+var hashDummy = []byte("$2a$10$.I4ARkTT47QEnz.vhsOeqerOLmnhAsQKFPZL8jloWJoC8lx94UGy6")
+
+// HashPassword genera el hash bcrypt de una contraseña
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword compara la contraseña con el hash. Con hash vacío compara contra
+// un hash dummy y devuelve false.
+func CheckPassword(hash string, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(hashDummy, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"prestadores-api/internal/model"
+	"slices"
+)
+
+// Permiso identifica una operación de la API sujeta a control por rol
+type Permiso string
+
+const (
+	PermisoVerAfiliados         Permiso = "afiliados:ver"
+	PermisoGestionarSituaciones Permiso = "situaciones:gestionar"
+	PermisoGestionarTurnos      Permiso = "turnos:gestionar"
+	PermisoGestionarCuentas     Permiso = "cuentas-cobro:gestionar"
+	PermisoVerSolicitudes       Permiso = "solicitudes:ver"
+	PermisoCrearSolicitudes     Permiso = "solicitudes:crear"
+	PermisoEditarSolicitudes    Permiso = "solicitudes:editar"
+	PermisoCambiarEstado        Permiso = "solicitudes:estado" // qué transición puede hacer cada rol lo decide el service
+	PermisoAdjuntarComprobantes Permiso = "solicitudes:adjuntar"
+	PermisoGestionarUsuarios    Permiso = "usuarios:gestionar" // solo ADMIN
+	PermisoVerPlanes            Permiso = "planes:ver"
+	PermisoGestionarPlanes      Permiso = "planes:gestionar" // solo ADMIN
+	PermisoVerNomenclador       Permiso = "nomenclador:ver"
+	PermisoVerVademecum         Permiso = "vademecum:ver"
+	PermisoImportarVademecum    Permiso = "vademecum:importar" // solo ADMIN
+	PermisoDispensarRecetas     Permiso = "recetas:dispensar"
+)
+
+// Matriz de permisos por rol. ADMIN no figura porque tiene todos.
+var permisosPorRol = map[model.Rol][]Permiso{
+	model.RolPrestador: {
+		PermisoVerAfiliados,
+		PermisoGestionarSituaciones,
+		PermisoGestionarTurnos,
+		PermisoGestionarCuentas,
+		PermisoVerSolicitudes,
+		PermisoCrearSolicitudes,
+		PermisoEditarSolicitudes,
+		PermisoCambiarEstado,
+		PermisoAdjuntarComprobantes,
+		PermisoVerPlanes,
+		PermisoVerNomenclador,
+		PermisoVerVademecum,
+	},
+	model.RolAuditor: {
+		PermisoVerAfiliados,
+		PermisoVerSolicitudes,
+		PermisoCambiarEstado,
+		PermisoVerPlanes,
+		PermisoVerNomenclador,
+		PermisoVerVademecum,
+	},
+	// Las farmacias solo dispensan: la verificación por código es pública
+	model.RolFarmacia: {
+		PermisoDispensarRecetas,
+	},
+}
+
+// TienePermiso indica si el rol puede ejecutar la operación
+func TienePermiso(rol model.Rol, permiso Permiso) bool {
+	if rol == model.RolAdmin {
+		return true
+	}
+	return slices.Contains(permisosPorRol[rol], permiso)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const issuer = "prestadores-api"
+
+// ErrTokenInvalido indica un access token mal formado, con firma inválida o expirado
+var ErrTokenInvalido = errors.New("token inválido o expirado")
+
+// Claims son los datos del prestador que viajan en el access token
+type Claims struct {
+	CUIT     string    `json:"cuit"`
+	Username string    `json:"username"`
+	Nombre   string    `json:"nombre"`
+	Rol      model.Rol `json:"rol"`
+	jwt.RegisteredClaims
+}
+
+// TokenManager emite y valida access tokens JWT firmados con HMAC-SHA256
+type TokenManager struct {
+	secret    []byte
+	accessTTL time.Duration
+	now       func() time.Time
+}
+
+func NewTokenManager(secret []byte, accessTTL time.Duration) *TokenManager {
+	return &TokenManager{
+		secret:    secret,
+		accessTTL: accessTTL,
+		now:       time.Now,
+	}
+}
+
+// AccessTTL devuelve la duración de los access tokens emitidos
+func (m *TokenManager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+// GenerarAccessToken firma un access token para el usuario
+func (m *TokenManager) GenerarAccessToken(u model.Usuario) (string, time.Time, error) {
+	now := m.now()
+	expira := now.Add(m.accessTTL)
+
+	claims := Claims{
+		CUIT:     u.CUIT,
+		Username: u.Username,
+		Nombre:   u.Nombre,
+		Rol:      u.Rol,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.Itoa(u.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expira),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error al firmar token: %w", err)
+	}
+	return token, expira, nil
+}
+
+// ValidarAccessToken verifica firma, emisor y vencimiento y devuelve los claims
+func (m *TokenManager) ValidarAccessToken(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenInvalido, err)
+	}
+	return claims, nil
+}
+
+// GenerarRefreshToken devuelve un token opaco aleatorio y el hash que se persiste
+func GenerarRefreshToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error al generar refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken calcula el SHA-256 con el que se guarda el refresh token
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Drivers soportados. "memory" mantiene los repositorios en memoria (tests / demo).
+const (
+	DriverMemory   = "memory"
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Config representa la configuración de la base de datos
+type Config struct {
+	Driver string
+	DSN    string
+}
+
+// ConfigFromEnv arma la configuración a partir de DB_DRIVER y DB_DSN.
+// Si no se define DB_DRIVER se usan los repositorios en memoria.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Driver: strings.ToLower(strings.TrimSpace(os.Getenv("DB_DRIVER"))),
+		DSN:    os.Getenv("DB_DSN"),
+	}
+
+	if cfg.Driver == "" {
+		cfg.Driver = DriverMemory
+	}
+
+	if cfg.Driver == DriverSQLite && cfg.DSN == "" {
+		cfg.DSN = "file:prestadores.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	}
+
+	return cfg
+}
+
+// Open abre la conexión y verifica que la base responda
+func Open(cfg Config) (*sql.DB, error) {
+	switch cfg.Driver {
+	case DriverSQLite, DriverPostgres:
+	default:
+		return nil, fmt.Errorf("driver de base de datos no soportado: %q", cfg.Driver)
+	}
+
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("DB_DSN es obligatorio para el driver %q", cfg.Driver)
+	}
+
+	db, err := sql.Open(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir la base de datos: %w", err)
+	}
+
+	if cfg.Driver == DriverSQLite {
+		// SQLite no soporta escrituras concurrentes
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al conectar con la base de datos: %w", err)
+	}
+
+	return db, nil
+}
+
+// Dialect adapta las diferencias de DDL entre SQLite y Postgres.
+// Las consultas usan placeholders $1, $2... que ambos motores aceptan.
+type Dialect string
+
+// AdaptarDDL reemplaza las construcciones propias de Postgres por su equivalente en SQLite
+func (d Dialect) AdaptarDDL(stmt string) string {
+	if d != DriverSQLite {
+		return stmt
+	}
+	return strings.ReplaceAll(stmt, "BIGSERIAL PRIMARY KEY", "INTEGER PRIMARY KEY AUTOINCREMENT")
+}
@@ -0,0 +1,301 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// ErrEsquemaDesactualizado indica que hay migraciones pendientes de aplicar
+var ErrEsquemaDesactualizado = errors.New("el esquema de base de datos tiene migraciones pendientes")
+
+// Migration representa una versión del esquema con sus scripts de subida y bajada
+type Migration struct {
+	Version int
+	Nombre  string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describe el estado de una migración respecto de la base
+type MigrationStatus struct {
+	Version    int
+	Nombre     string
+	Aplicada   bool
+	AplicadaEn *time.Time
+}
+
+// Migrator aplica y revierte las migraciones embebidas, registrando las versiones
+// aplicadas en la tabla schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// NewMigrator carga las migraciones embebidas para el driver indicado
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    Dialect(driver),
+		migrations: migrations,
+	}, nil
+}
+
+// loadMigrations lee los archivos NNNN_nombre.up.sql / NNNN_nombre.down.sql del directorio
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer migraciones: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+
+		var direccion string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direccion = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direccion = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direccion+".sql")
+		versionStr, nombre, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("nombre de migración inválido: %s", name)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("versión de migración inválida: %s", name)
+		}
+
+		contenido, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer migración %s: %w", name, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Nombre: nombre}
+			byVersion[version] = m
+		} else if m.Nombre != nombre {
+			return nil, fmt.Errorf("migraciones con la misma versión %d: %s y %s", version, m.Nombre, nombre)
+		}
+
+		if direccion == "up" {
+			m.Up = string(contenido)
+		} else {
+			m.Down = string(contenido)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("la migración %04d_%s debe tener scripts up y down", m.Version, m.Nombre)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			nombre     TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("error al crear tabla schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied devuelve las versiones aplicadas con su fecha de aplicación
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error al leer schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error al leer schema_migrations: %w", err)
+		}
+		out[version] = appliedAt
+	}
+	return out, rows.Err()
+}
+
+// Status lista todas las migraciones conocidas indicando cuáles están aplicadas
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationStatus{Version: mig.Version, Nombre: mig.Nombre}
+		if at, ok := applied[mig.Version]; ok {
+			st.Aplicada = true
+			st.AplicadaEn = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// Pending devuelve las migraciones que todavía no se aplicaron
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Up aplica todas las migraciones pendientes en orden, cada una en su transacción
+func (m *Migrator) Up() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range pending {
+		err := m.run(mig.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec("INSERT INTO schema_migrations (version, nombre, applied_at) VALUES ($1, $2, $3)",
+				mig.Version, mig.Nombre, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("error al aplicar migración %04d_%s: %w", mig.Version, mig.Nombre, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down revierte las últimas `steps` migraciones aplicadas
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("la cantidad de pasos debe ser mayor a cero")
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+
+		err := m.run(mig.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("error al revertir migración %04d_%s: %w", mig.Version, mig.Nombre, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// EnsureUpToDate devuelve ErrEsquemaDesactualizado si quedan migraciones sin aplicar
+func (m *Migrator) EnsureUpToDate() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pendiente(s), la primera es %04d_%s",
+			ErrEsquemaDesactualizado, len(pending), pending[0].Version, pending[0].Nombre)
+	}
+	return nil
+}
+
+// run ejecuta el script y el registro en schema_migrations dentro de una misma transacción
+func (m *Migrator) run(script string, registrar func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.Exec(m.dialect.AdaptarDDL(stmt)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := registrar(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements separa un script en sentencias por ';', descartando comentarios de línea.
+// No entiende literales ni bloques: una migración no puede tener ';' dentro de un string, un
+// comentario al final de línea o el cuerpo de una función o trigger.
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var out []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
@@ -0,0 +1,6 @@
+DROP TABLE reintegro_historial;
+DROP TABLE reintegros;
+DROP TABLE receta_historial;
+DROP TABLE recetas;
+DROP TABLE autorizacion_historial;
+DROP TABLE autorizaciones;
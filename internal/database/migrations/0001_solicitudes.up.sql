@@ -0,0 +1,74 @@
+-- Solicitudes de autorizaciones, recetas y reintegros con su historial de estados
+
+CREATE TABLE autorizaciones (
+	id                  BIGSERIAL PRIMARY KEY,
+	estado              TEXT NOT NULL,
+	fecha_creacion      TIMESTAMP NOT NULL,
+	fecha_actualizacion TIMESTAMP NOT NULL,
+	afiliado_id         INTEGER NOT NULL,
+	afiliado_dni        TEXT NOT NULL,
+	afiliado_nombre     TEXT NOT NULL,
+	afiliado_apellido   TEXT NOT NULL,
+	procedimiento       TEXT NOT NULL,
+	especialidad        TEXT NOT NULL
+);
+
+CREATE TABLE autorizacion_historial (
+	id              BIGSERIAL PRIMARY KEY,
+	autorizacion_id INTEGER NOT NULL REFERENCES autorizaciones(id) ON DELETE CASCADE,
+	estado          TEXT NOT NULL,
+	usuario         TEXT NOT NULL,
+	fecha_cambio    TIMESTAMP NOT NULL,
+	motivo          TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_autorizacion_historial_autorizacion ON autorizacion_historial(autorizacion_id);
+
+CREATE TABLE recetas (
+	id                  BIGSERIAL PRIMARY KEY,
+	estado              TEXT NOT NULL,
+	fecha_creacion      TIMESTAMP NOT NULL,
+	fecha_actualizacion TIMESTAMP NOT NULL,
+	afiliado_id         INTEGER NOT NULL,
+	afiliado_dni        TEXT NOT NULL,
+	afiliado_nombre     TEXT NOT NULL,
+	afiliado_apellido   TEXT NOT NULL,
+	medicamento         TEXT NOT NULL,
+	dosis               TEXT NOT NULL
+);
+
+CREATE TABLE receta_historial (
+	id           BIGSERIAL PRIMARY KEY,
+	receta_id    INTEGER NOT NULL REFERENCES recetas(id) ON DELETE CASCADE,
+	estado       TEXT NOT NULL,
+	usuario      TEXT NOT NULL,
+	fecha_cambio TIMESTAMP NOT NULL,
+	motivo       TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_receta_historial_receta ON receta_historial(receta_id);
+
+CREATE TABLE reintegros (
+	id                  BIGSERIAL PRIMARY KEY,
+	estado              TEXT NOT NULL,
+	fecha_creacion      TIMESTAMP NOT NULL,
+	fecha_actualizacion TIMESTAMP NOT NULL,
+	afiliado_id         INTEGER NOT NULL,
+	afiliado_dni        TEXT NOT NULL,
+	afiliado_nombre     TEXT NOT NULL,
+	afiliado_apellido   TEXT NOT NULL,
+	prestacion          TEXT NOT NULL,
+	metodo              TEXT NOT NULL,
+	monto               DOUBLE PRECISION NOT NULL
+);
+
+CREATE TABLE reintegro_historial (
+	id           BIGSERIAL PRIMARY KEY,
+	reintegro_id INTEGER NOT NULL REFERENCES reintegros(id) ON DELETE CASCADE,
+	estado       TEXT NOT NULL,
+	usuario      TEXT NOT NULL,
+	fecha_cambio TIMESTAMP NOT NULL,
+	motivo       TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_reintegro_historial_reintegro ON reintegro_historial(reintegro_id);
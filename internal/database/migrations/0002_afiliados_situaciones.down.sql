@@ -0,0 +1,3 @@
+DROP TABLE situacion_historial;
+DROP TABLE situaciones;
+DROP TABLE afiliados;
@@ -0,0 +1,43 @@
+-- Afiliados (con grupo familiar) y situaciones terapéuticas
+
+CREATE TABLE afiliados (
+	id           BIGSERIAL PRIMARY KEY,
+	nro_afiliado TEXT NOT NULL UNIQUE,
+	dni          TEXT NOT NULL UNIQUE,
+	nombre       TEXT NOT NULL,
+	apellido     TEXT NOT NULL,
+	plan_medico  TEXT NOT NULL,
+	titular_id   INTEGER REFERENCES afiliados(id),
+	parentesco   TEXT NOT NULL DEFAULT '',
+	email        TEXT NOT NULL DEFAULT '',
+	telefono     TEXT NOT NULL DEFAULT '',
+	ciudad       TEXT NOT NULL DEFAULT '',
+	provincia    TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_afiliados_titular ON afiliados(titular_id);
+
+CREATE TABLE situaciones (
+	id                  BIGSERIAL PRIMARY KEY,
+	afiliado_id         INTEGER NOT NULL,
+	miembro_id          INTEGER,
+	descripcion         TEXT NOT NULL,
+	fecha_inicio        TEXT NOT NULL,
+	fecha_fin           TEXT,
+	estado              TEXT NOT NULL,
+	fecha_creacion      TIMESTAMP NOT NULL,
+	fecha_actualizacion TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_situaciones_afiliado ON situaciones(afiliado_id);
+
+CREATE TABLE situacion_historial (
+	id           BIGSERIAL PRIMARY KEY,
+	situacion_id INTEGER NOT NULL REFERENCES situaciones(id) ON DELETE CASCADE,
+	estado       TEXT NOT NULL,
+	usuario      TEXT NOT NULL,
+	fecha_cambio TIMESTAMP NOT NULL,
+	motivo       TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_situacion_historial_situacion ON situacion_historial(situacion_id);
@@ -0,0 +1,3 @@
+ALTER TABLE reintegros DROP COLUMN texto_busqueda;
+ALTER TABLE recetas DROP COLUMN texto_busqueda;
+ALTER TABLE autorizaciones DROP COLUMN texto_busqueda;
@@ -0,0 +1,9 @@
+-- Texto normalizado (minúsculas, sin tildes) para la búsqueda `q` de los listados.
+-- Lo mantienen los repositorios. Las filas existentes quedan vacías y las completa la API al iniciar
+-- (repository.IndexarBusquedaPendiente) con la misma normalización que las búsquedas.
+
+ALTER TABLE autorizaciones ADD COLUMN texto_busqueda TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE recetas ADD COLUMN texto_busqueda TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE reintegros ADD COLUMN texto_busqueda TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE situacion_historial DROP COLUMN rol;
+ALTER TABLE reintegro_historial DROP COLUMN rol;
+ALTER TABLE receta_historial DROP COLUMN rol;
+ALTER TABLE autorizacion_historial DROP COLUMN rol;
@@ -0,0 +1,6 @@
+-- Rol del usuario que hizo cada cambio de estado
+
+ALTER TABLE autorizacion_historial ADD COLUMN rol TEXT NOT NULL DEFAULT '';
+ALTER TABLE receta_historial ADD COLUMN rol TEXT NOT NULL DEFAULT '';
+ALTER TABLE reintegro_historial ADD COLUMN rol TEXT NOT NULL DEFAULT '';
+ALTER TABLE situacion_historial ADD COLUMN rol TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX idx_reintegros_afiliado_prestacion;
+ALTER TABLE reintegros DROP COLUMN autorizacion_id;
+ALTER TABLE reintegros DROP COLUMN monto_reconocido;
@@ -0,0 +1,7 @@
+-- Monto reconocido según la cobertura del plan y autorización previa, si el plan la exige.
+-- Los reintegros anteriores quedan con monto_reconocido = 0 (sin calcular).
+
+ALTER TABLE reintegros ADD COLUMN monto_reconocido DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN autorizacion_id INTEGER REFERENCES autorizaciones(id);
+
+CREATE INDEX idx_reintegros_afiliado_prestacion ON reintegros(afiliado_id, prestacion);
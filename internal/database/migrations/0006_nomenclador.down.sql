@@ -0,0 +1,7 @@
+DROP INDEX idx_reintegros_afiliado_especialidad;
+CREATE INDEX idx_reintegros_afiliado_prestacion ON reintegros(afiliado_id, prestacion);
+
+ALTER TABLE reintegros DROP COLUMN especialidad;
+ALTER TABLE reintegros DROP COLUMN prestacion_codigo;
+
+ALTER TABLE autorizaciones DROP COLUMN procedimiento_codigo;
@@ -0,0 +1,12 @@
+-- Procedimientos y prestaciones referenciados por código del nomenclador.
+-- Las filas anteriores quedan con código vacío; en reintegros la especialidad
+-- (sobre la que se calcula el tope anual) toma el texto libre de la prestación.
+
+ALTER TABLE autorizaciones ADD COLUMN procedimiento_codigo TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE reintegros ADD COLUMN prestacion_codigo TEXT NOT NULL DEFAULT '';
+ALTER TABLE reintegros ADD COLUMN especialidad TEXT NOT NULL DEFAULT '';
+UPDATE reintegros SET especialidad = prestacion;
+
+DROP INDEX idx_reintegros_afiliado_prestacion;
+CREATE INDEX idx_reintegros_afiliado_especialidad ON reintegros(afiliado_id, especialidad);
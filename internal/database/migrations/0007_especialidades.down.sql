@@ -0,0 +1,5 @@
+DROP INDEX idx_reintegros_especialidad;
+DROP INDEX idx_autorizaciones_especialidad;
+
+ALTER TABLE reintegros DROP COLUMN especialidad_codigo;
+ALTER TABLE autorizaciones DROP COLUMN especialidad_codigo;
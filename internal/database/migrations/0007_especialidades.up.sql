@@ -0,0 +1,33 @@
+-- Especialidad referenciada por código del catálogo, para filtrar los listados.
+-- Las filas existentes se completan a partir del nombre; las que no coinciden
+-- con ninguna especialidad del catálogo quedan con código vacío.
+
+ALTER TABLE autorizaciones ADD COLUMN especialidad_codigo TEXT NOT NULL DEFAULT '';
+ALTER TABLE reintegros ADD COLUMN especialidad_codigo TEXT NOT NULL DEFAULT '';
+
+UPDATE autorizaciones SET especialidad_codigo = CASE especialidad
+    WHEN 'Cardiología' THEN 'CARDIOLOGIA'
+    WHEN 'Clínica Médica' THEN 'CLINICA_MEDICA'
+    WHEN 'Dermatología' THEN 'DERMATOLOGIA'
+    WHEN 'Diagnóstico por Imágenes' THEN 'DIAGNOSTICO_IMAGENES'
+    WHEN 'Kinesiología' THEN 'KINESIOLOGIA'
+    WHEN 'Odontología' THEN 'ODONTOLOGIA'
+    WHEN 'Pediatría' THEN 'PEDIATRIA'
+    WHEN 'Traumatología' THEN 'TRAUMATOLOGIA'
+    ELSE ''
+END;
+
+UPDATE reintegros SET especialidad_codigo = CASE especialidad
+    WHEN 'Cardiología' THEN 'CARDIOLOGIA'
+    WHEN 'Clínica Médica' THEN 'CLINICA_MEDICA'
+    WHEN 'Dermatología' THEN 'DERMATOLOGIA'
+    WHEN 'Diagnóstico por Imágenes' THEN 'DIAGNOSTICO_IMAGENES'
+    WHEN 'Kinesiología' THEN 'KINESIOLOGIA'
+    WHEN 'Odontología' THEN 'ODONTOLOGIA'
+    WHEN 'Pediatría' THEN 'PEDIATRIA'
+    WHEN 'Traumatología' THEN 'TRAUMATOLOGIA'
+    ELSE ''
+END;
+
+CREATE INDEX idx_autorizaciones_especialidad ON autorizaciones(especialidad_codigo);
+CREATE INDEX idx_reintegros_especialidad ON reintegros(especialidad_codigo);
@@ -0,0 +1,3 @@
+ALTER TABLE recetas DROP COLUMN marca_sugerida;
+ALTER TABLE recetas DROP COLUMN droga_generica;
+ALTER TABLE recetas DROP COLUMN medicamento_codigo;
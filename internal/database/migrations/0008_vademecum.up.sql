@@ -0,0 +1,6 @@
+-- Recetas referenciadas por código del vademécum y prescriptas por nombre genérico.
+-- Las filas anteriores quedan con código y droga vacíos y conservan el texto libre en medicamento.
+
+ALTER TABLE recetas ADD COLUMN medicamento_codigo TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN droga_generica TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN marca_sugerida TEXT NOT NULL DEFAULT '';
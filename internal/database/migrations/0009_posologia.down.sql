@@ -0,0 +1,6 @@
+ALTER TABLE recetas DROP COLUMN envases;
+ALTER TABLE recetas DROP COLUMN via_administracion;
+ALTER TABLE recetas DROP COLUMN duracion_dias;
+ALTER TABLE recetas DROP COLUMN frecuencia_horas;
+ALTER TABLE recetas DROP COLUMN dosis_unidad;
+ALTER TABLE recetas DROP COLUMN dosis_cantidad;
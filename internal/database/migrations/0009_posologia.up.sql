@@ -0,0 +1,9 @@
+-- Posología estructurada de las recetas. dosis_unidad vacía indica que la receta solo tiene la dosis
+-- en texto libre; las filas anteriores quedan así y la API intenta interpretar el texto al leerlas.
+
+ALTER TABLE recetas ADD COLUMN dosis_cantidad DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE recetas ADD COLUMN dosis_unidad TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN frecuencia_horas INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recetas ADD COLUMN duracion_dias INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recetas ADD COLUMN via_administracion TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN envases INTEGER NOT NULL DEFAULT 0;
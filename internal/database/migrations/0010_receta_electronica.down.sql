@@ -0,0 +1,8 @@
+DROP INDEX idx_recetas_codigo_verificacion;
+
+ALTER TABLE recetas DROP COLUMN farmacia_cuit;
+ALTER TABLE recetas DROP COLUMN fecha_dispensa;
+ALTER TABLE recetas DROP COLUMN codigo_verificacion;
+ALTER TABLE recetas DROP COLUMN prestador_nombre;
+ALTER TABLE recetas DROP COLUMN prestador_cuit;
+ALTER TABLE recetas DROP COLUMN prestador_id;
@@ -0,0 +1,11 @@
+-- Receta electrónica: prescriptor, código de verificación (se asigna al aprobar) y dispensa en farmacia.
+-- Las recetas anteriores quedan sin prescriptor; las aprobadas reciben el código al generar el PDF.
+
+ALTER TABLE recetas ADD COLUMN prestador_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recetas ADD COLUMN prestador_cuit TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN prestador_nombre TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN codigo_verificacion TEXT NOT NULL DEFAULT '';
+ALTER TABLE recetas ADD COLUMN fecha_dispensa TIMESTAMP;
+ALTER TABLE recetas ADD COLUMN farmacia_cuit TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_recetas_codigo_verificacion ON recetas(codigo_verificacion) WHERE codigo_verificacion <> '';
@@ -0,0 +1,15 @@
+ALTER TABLE recetas ADD COLUMN fecha_dispensa TIMESTAMP;
+ALTER TABLE recetas ADD COLUMN farmacia_cuit TEXT NOT NULL DEFAULT '';
+
+-- Se conserva la primera entrega de cada receta
+UPDATE recetas SET
+	fecha_dispensa = (SELECT MIN(d.fecha) FROM receta_dispensas d WHERE d.receta_id = recetas.id),
+	farmacia_cuit = COALESCE((SELECT d.farmacia_cuit FROM receta_dispensas d WHERE d.receta_id = recetas.id ORDER BY d.fecha, d.id LIMIT 1), '');
+
+UPDATE recetas SET estado = 'APROBADO' WHERE estado IN ('DISPENSADA', 'PARCIALMENTE_DISPENSADA', 'VENCIDA');
+
+DROP TABLE receta_dispensas;
+
+DROP INDEX idx_recetas_estado_vencimiento;
+
+ALTER TABLE recetas DROP COLUMN fecha_vencimiento;
@@ -0,0 +1,26 @@
+-- Vigencia de las recetas y dispensas parciales. Una receta puede dispensarse en varias entregas,
+-- una por fila de receta_dispensas. Las aprobadas antes quedan sin fecha de vencimiento (no vencen).
+
+ALTER TABLE recetas ADD COLUMN fecha_vencimiento TIMESTAMP;
+
+CREATE INDEX idx_recetas_estado_vencimiento ON recetas(estado, fecha_vencimiento);
+
+CREATE TABLE receta_dispensas (
+	id            BIGSERIAL PRIMARY KEY,
+	receta_id     INTEGER NOT NULL REFERENCES recetas(id) ON DELETE CASCADE,
+	fecha         TIMESTAMP NOT NULL,
+	farmacia_cuit TEXT NOT NULL,
+	envases       INTEGER NOT NULL
+);
+
+CREATE INDEX idx_receta_dispensas_receta ON receta_dispensas(receta_id);
+
+-- La dispensa única de la receta electrónica entregaba todos los envases
+INSERT INTO receta_dispensas (receta_id, fecha, farmacia_cuit, envases)
+SELECT id, fecha_dispensa, farmacia_cuit, CASE WHEN envases > 0 THEN envases ELSE 1 END
+FROM recetas WHERE fecha_dispensa IS NOT NULL;
+
+UPDATE recetas SET estado = 'DISPENSADA' WHERE fecha_dispensa IS NOT NULL AND estado = 'APROBADO';
+
+ALTER TABLE recetas DROP COLUMN farmacia_cuit;
+ALTER TABLE recetas DROP COLUMN fecha_dispensa;
@@ -0,0 +1,10 @@
+ALTER TABLE reintegros ADD COLUMN monto DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN monto_reconocido DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+UPDATE reintegros SET
+	monto = monto_centavos / 100.0,
+	monto_reconocido = monto_reconocido_centavos / 100.0;
+
+ALTER TABLE reintegros DROP COLUMN monto_reconocido_centavos;
+ALTER TABLE reintegros DROP COLUMN monto_centavos;
+ALTER TABLE reintegros DROP COLUMN moneda;
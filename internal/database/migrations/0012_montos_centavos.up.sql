@@ -0,0 +1,13 @@
+-- Los montos de los reintegros pasan a centavos enteros para no acumular errores de redondeo.
+-- Los existentes se convierten redondeando al centavo.
+
+ALTER TABLE reintegros ADD COLUMN moneda TEXT NOT NULL DEFAULT 'ARS';
+ALTER TABLE reintegros ADD COLUMN monto_centavos BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN monto_reconocido_centavos BIGINT NOT NULL DEFAULT 0;
+
+UPDATE reintegros SET
+	monto_centavos = CAST(ROUND(monto * 100) AS BIGINT),
+	monto_reconocido_centavos = CAST(ROUND(monto_reconocido * 100) AS BIGINT);
+
+ALTER TABLE reintegros DROP COLUMN monto;
+ALTER TABLE reintegros DROP COLUMN monto_reconocido;
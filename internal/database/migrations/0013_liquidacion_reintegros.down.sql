@@ -0,0 +1,8 @@
+ALTER TABLE reintegros DROP COLUMN tope_aplicado_centavos;
+ALTER TABLE reintegros DROP COLUMN consumido_anual_centavos;
+ALTER TABLE reintegros DROP COLUMN tope_anual_centavos;
+ALTER TABLE reintegros DROP COLUMN coseguro_centavos;
+ALTER TABLE reintegros DROP COLUMN porcentaje_cobertura;
+ALTER TABLE reintegros DROP COLUMN monto_reconocible_centavos;
+ALTER TABLE reintegros DROP COLUMN valor_referencia_centavos;
+ALTER TABLE reintegros DROP COLUMN fecha_liquidacion;
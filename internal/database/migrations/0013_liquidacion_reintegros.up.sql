@@ -0,0 +1,11 @@
+-- Desglose del cálculo del monto reconocido de cada reintegro. Los cargados antes quedan sin
+-- fecha_liquidacion: no se sabe con qué valores se calcularon.
+
+ALTER TABLE reintegros ADD COLUMN fecha_liquidacion TIMESTAMP;
+ALTER TABLE reintegros ADD COLUMN valor_referencia_centavos BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN monto_reconocible_centavos BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN porcentaje_cobertura INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN coseguro_centavos BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN tope_anual_centavos BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN consumido_anual_centavos BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN tope_aplicado_centavos BIGINT NOT NULL DEFAULT 0;
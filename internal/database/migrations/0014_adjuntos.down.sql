@@ -0,0 +1,2 @@
+DROP INDEX idx_adjuntos_solicitud_sha256;
+DROP TABLE adjuntos;
@@ -0,0 +1,19 @@
+-- Comprobantes adjuntos a autorizaciones y reintegros. El contenido está en el storage bajo `clave`;
+-- solicitud_id no tiene FK porque apunta a la tabla que indica tipo_solicitud.
+
+CREATE TABLE adjuntos (
+	id             BIGSERIAL PRIMARY KEY,
+	tipo_solicitud TEXT NOT NULL,
+	solicitud_id   INTEGER NOT NULL,
+	categoria      TEXT NOT NULL DEFAULT 'OTRO',
+	nombre_archivo TEXT NOT NULL,
+	content_type   TEXT NOT NULL,
+	tamanio        BIGINT NOT NULL,
+	sha256         TEXT NOT NULL,
+	clave          TEXT NOT NULL,
+	usuario        TEXT NOT NULL,
+	rol            TEXT NOT NULL DEFAULT '',
+	fecha_carga    TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_adjuntos_solicitud_sha256 ON adjuntos(tipo_solicitud, solicitud_id, sha256);
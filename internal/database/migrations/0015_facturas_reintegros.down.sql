@@ -0,0 +1,2 @@
+DROP INDEX idx_reintegro_facturas_comprobante;
+DROP TABLE reintegro_facturas;
@@ -0,0 +1,15 @@
+-- Factura AFIP que respalda cada reintegro. Los reintegros cargados antes no tienen fila.
+
+CREATE TABLE reintegro_facturas (
+	reintegro_id     INTEGER PRIMARY KEY REFERENCES reintegros(id) ON DELETE CASCADE,
+	tipo             TEXT NOT NULL,
+	punto_venta      INTEGER NOT NULL,
+	numero           INTEGER NOT NULL,
+	cuit_emisor      TEXT NOT NULL,
+	fecha            TEXT NOT NULL,
+	cae              TEXT NOT NULL,
+	importe_centavos BIGINT NOT NULL
+);
+
+-- Para buscar si la factura ya se usó en otro reintegro
+CREATE INDEX idx_reintegro_facturas_comprobante ON reintegro_facturas(cuit_emisor, tipo, punto_venta, numero);
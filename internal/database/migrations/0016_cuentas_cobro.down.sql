@@ -0,0 +1,9 @@
+-- Los reintegros por transferencia vuelven como Debito: el texto original no se conserva
+UPDATE reintegros SET metodo = 'Efectivo' WHERE metodo = 'EFECTIVO';
+UPDATE reintegros SET metodo = 'Debito', texto_busqueda = REPLACE(texto_busqueda, ' transferencia', ' debito')
+WHERE metodo = 'TRANSFERENCIA';
+
+ALTER TABLE reintegros DROP COLUMN cuenta_cobro_id;
+
+DROP INDEX idx_cuentas_cobro_afiliado;
+DROP TABLE cuentas_cobro;
@@ -0,0 +1,25 @@
+-- Cuentas (CBU/CVU) donde los afiliados cobran los reintegros por transferencia. afiliado_id no
+-- tiene FK porque el padrón no se guarda en esta base, igual que en reintegros.
+
+CREATE TABLE cuentas_cobro (
+	id             BIGSERIAL PRIMARY KEY,
+	afiliado_id    INTEGER NOT NULL,
+	tipo           TEXT NOT NULL,
+	cbu            TEXT NOT NULL,
+	alias          TEXT NOT NULL DEFAULT '',
+	titular_cuit   TEXT NOT NULL,
+	titular_nombre TEXT NOT NULL,
+	usuario        TEXT NOT NULL,
+	fecha_alta     TIMESTAMP NOT NULL,
+	fecha_baja     TIMESTAMP
+);
+
+CREATE INDEX idx_cuentas_cobro_afiliado ON cuentas_cobro(afiliado_id);
+
+ALTER TABLE reintegros ADD COLUMN cuenta_cobro_id INTEGER REFERENCES cuentas_cobro(id);
+
+-- El método deja de ser texto libre. Débito y crédito eran acreditaciones en cuenta: pasan a
+-- TRANSFERENCIA sin cuenta, que se completa con un PUT.
+UPDATE reintegros SET metodo = 'EFECTIVO' WHERE LOWER(metodo) = 'efectivo';
+UPDATE reintegros SET metodo = 'TRANSFERENCIA', texto_busqueda = texto_busqueda || ' transferencia'
+WHERE metodo <> 'EFECTIVO';
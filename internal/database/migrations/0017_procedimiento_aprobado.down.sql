@@ -0,0 +1 @@
+ALTER TABLE autorizacion_historial DROP COLUMN procedimiento_codigo;
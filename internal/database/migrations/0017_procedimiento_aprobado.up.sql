@@ -0,0 +1,9 @@
+-- Procedimiento vigente al aprobar cada autorización, para validar los reintegros contra lo aprobado
+
+ALTER TABLE autorizacion_historial ADD COLUMN procedimiento_codigo TEXT NOT NULL DEFAULT '';
+
+UPDATE autorizacion_historial
+SET procedimiento_codigo = (
+	SELECT a.procedimiento_codigo FROM autorizaciones a WHERE a.id = autorizacion_historial.autorizacion_id
+)
+WHERE estado = 'APROBADO';
@@ -0,0 +1,4 @@
+DROP INDEX idx_refresh_tokens_expira;
+DROP TABLE refresh_tokens;
+DROP INDEX idx_usuarios_username;
+DROP TABLE usuarios;
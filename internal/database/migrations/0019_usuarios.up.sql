@@ -0,0 +1,24 @@
+-- Usuarios de la API y refresh tokens emitidos. Del refresh token solo se guarda el hash.
+-- La tabla arranca vacía: el primer ADMIN se crea al iniciar con ADMIN_CUIT y ADMIN_PASSWORD.
+
+CREATE TABLE usuarios (
+	id            BIGSERIAL PRIMARY KEY,
+	cuit          TEXT NOT NULL UNIQUE,
+	username      TEXT NOT NULL,
+	nombre        TEXT NOT NULL,
+	rol           TEXT NOT NULL,
+	password_hash TEXT NOT NULL,
+	activo        BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE UNIQUE INDEX idx_usuarios_username ON usuarios(LOWER(username));
+
+CREATE TABLE refresh_tokens (
+	hash        TEXT PRIMARY KEY,
+	usuario_id  INTEGER NOT NULL REFERENCES usuarios(id),
+	creado_en   TIMESTAMP NOT NULL,
+	expira_en   TIMESTAMP NOT NULL,
+	revocado_en TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_expira ON refresh_tokens(expira_en);
@@ -0,0 +1,3 @@
+DROP INDEX idx_planes_nombre;
+DROP TABLE plan_coberturas;
+DROP TABLE planes;
@@ -0,0 +1,53 @@
+-- Catálogo de planes médicos con la cobertura de cada prestación. Los nombres se guardan también
+-- normalizados (minúsculas y sin tildes) para compararlos igual que en memoria. Topes y copagos
+-- en centavos, como los montos de los reintegros.
+
+CREATE TABLE planes (
+	id                 BIGSERIAL PRIMARY KEY,
+	nombre             TEXT NOT NULL,
+	nombre_normalizado TEXT NOT NULL,
+	vigencia_desde     TIMESTAMP NOT NULL,
+	vigencia_hasta     TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_planes_nombre ON planes(nombre_normalizado);
+
+CREATE TABLE plan_coberturas (
+	plan_id                INTEGER NOT NULL REFERENCES planes(id) ON DELETE CASCADE,
+	orden                  INTEGER NOT NULL,
+	prestacion             TEXT NOT NULL,
+	prestacion_normalizada TEXT NOT NULL,
+	porcentaje_cobertura   INTEGER NOT NULL,
+	tope_anual_centavos    BIGINT NOT NULL DEFAULT 0,
+	copago_centavos        BIGINT NOT NULL DEFAULT 0,
+	requiere_autorizacion  BOOLEAN NOT NULL DEFAULT FALSE,
+	carencia_dias          INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (plan_id, prestacion_normalizada)
+);
+
+-- Los planes a los que apunta el padrón de afiliados de ejemplo. Se insertan en orden
+-- sobre la tabla vacía para que reciban los IDs 1, 2 y 3.
+INSERT INTO planes (nombre, nombre_normalizado, vigencia_desde) VALUES ('Sancor Salud', 'sancor salud', '2015-01-01 00:00:00');
+INSERT INTO planes (nombre, nombre_normalizado, vigencia_desde) VALUES ('Galeno 210', 'galeno 210', '2015-01-01 00:00:00');
+INSERT INTO planes (nombre, nombre_normalizado, vigencia_desde) VALUES ('Swiss Medical', 'swiss medical', '2015-01-01 00:00:00');
+
+INSERT INTO plan_coberturas (plan_id, orden, prestacion, prestacion_normalizada, porcentaje_cobertura, tope_anual_centavos, copago_centavos, requiere_autorizacion, carencia_dias) VALUES
+	(1, 1, 'Clínica Médica', 'clinica medica', 100, 0, 0, FALSE, 0),
+	(1, 2, 'Cardiología', 'cardiologia', 100, 0, 250000, FALSE, 0),
+	(1, 3, 'Diagnóstico por Imágenes', 'diagnostico por imagenes', 80, 0, 0, TRUE, 30),
+	(1, 4, 'Kinesiología', 'kinesiologia', 70, 15000000, 200000, FALSE, 60),
+	(1, 5, 'Odontología', 'odontologia', 50, 12000000, 0, FALSE, 90),
+	(1, 6, 'Traumatología', 'traumatologia', 100, 0, 250000, FALSE, 0),
+	(1, 7, 'Medicamentos', 'medicamentos', 40, 0, 0, FALSE, 0),
+	(2, 1, 'Clínica Médica', 'clinica medica', 100, 0, 0, FALSE, 0),
+	(2, 2, 'Cardiología', 'cardiologia', 90, 0, 300000, FALSE, 0),
+	(2, 3, 'Diagnóstico por Imágenes', 'diagnostico por imagenes', 70, 0, 0, TRUE, 60),
+	(2, 4, 'Kinesiología', 'kinesiologia', 60, 10000000, 250000, FALSE, 90),
+	(2, 5, 'Medicamentos', 'medicamentos', 40, 0, 0, FALSE, 0),
+	(3, 1, 'Clínica Médica', 'clinica medica', 100, 0, 0, FALSE, 0),
+	(3, 2, 'Cardiología', 'cardiologia', 100, 0, 0, FALSE, 0),
+	(3, 3, 'Diagnóstico por Imágenes', 'diagnostico por imagenes', 100, 0, 0, FALSE, 0),
+	(3, 4, 'Kinesiología', 'kinesiologia', 80, 25000000, 0, FALSE, 30),
+	(3, 5, 'Odontología', 'odontologia', 70, 20000000, 0, TRUE, 180),
+	(3, 6, 'Traumatología', 'traumatologia', 100, 0, 0, FALSE, 0),
+	(3, 7, 'Medicamentos', 'medicamentos', 50, 0, 0, FALSE, 30);
@@ -0,0 +1,13 @@
+-- Último vademécum importado por PUT /vademecum. Mientras la tabla esté vacía se usa el CSV
+-- embebido o el de VADEMECUM_CSV.
+
+CREATE TABLE vademecum (
+	codigo          TEXT PRIMARY KEY,
+	orden           INTEGER NOT NULL,
+	droga_generica  TEXT NOT NULL,
+	marca_comercial TEXT NOT NULL,
+	presentacion    TEXT NOT NULL,
+	concentracion   TEXT NOT NULL,
+	laboratorio     TEXT NOT NULL,
+	condicion_venta TEXT NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP INDEX idx_reintegro_facturas_comprobante;
+CREATE INDEX idx_reintegro_facturas_comprobante ON reintegro_facturas(cuit_emisor, tipo, punto_venta, numero);
+
+ALTER TABLE reintegro_facturas DROP COLUMN liberada;
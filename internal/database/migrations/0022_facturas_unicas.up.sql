@@ -0,0 +1,12 @@
+-- Una factura respalda un solo reintegro. Al rechazarlo la factura queda liberada para presentarla
+-- en otro, por eso el índice único excluye las liberadas. Si quedaron duplicados de antes de este
+-- control, la creación del índice falla y hay que rechazar a mano uno de los reintegros.
+
+ALTER TABLE reintegro_facturas ADD COLUMN liberada BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE reintegro_facturas SET liberada = TRUE
+WHERE reintegro_id IN (SELECT id FROM reintegros WHERE estado = 'RECHAZADO');
+
+DROP INDEX idx_reintegro_facturas_comprobante;
+CREATE UNIQUE INDEX idx_reintegro_facturas_comprobante ON reintegro_facturas(cuit_emisor, tipo, punto_venta, numero)
+WHERE NOT liberada;
@@ -0,0 +1 @@
+-- Nada que revertir: el texto de búsqueda recalculado sigue siendo válido con el esquema anterior.
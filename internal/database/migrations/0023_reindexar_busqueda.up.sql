@@ -0,0 +1,9 @@
+-- El texto de búsqueda de las filas anteriores se había completado en SQL reemplazando solo algunas
+-- tildes, mientras que la API normaliza quitando todas las marcas diacríticas (à, ç, ö...). Se vacía
+-- para que la API lo vuelva a calcular al iniciar con la misma normalización que las búsquedas.
+
+UPDATE autorizaciones SET texto_busqueda = '';
+
+UPDATE recetas SET texto_busqueda = '';
+
+UPDATE reintegros SET texto_busqueda = '';
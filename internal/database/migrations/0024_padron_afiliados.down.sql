@@ -0,0 +1,24 @@
+-- Vuelve a la tabla de 0002 para que su down la pueda borrar
+
+DROP INDEX idx_afiliados_plan;
+
+DROP INDEX idx_afiliados_titular;
+
+DROP TABLE afiliados;
+
+CREATE TABLE afiliados (
+	id           BIGSERIAL PRIMARY KEY,
+	nro_afiliado TEXT NOT NULL UNIQUE,
+	dni          TEXT NOT NULL UNIQUE,
+	nombre       TEXT NOT NULL,
+	apellido     TEXT NOT NULL,
+	plan_medico  TEXT NOT NULL,
+	titular_id   INTEGER REFERENCES afiliados(id),
+	parentesco   TEXT NOT NULL DEFAULT '',
+	email        TEXT NOT NULL DEFAULT '',
+	telefono     TEXT NOT NULL DEFAULT '',
+	ciudad       TEXT NOT NULL DEFAULT '',
+	provincia    TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_afiliados_titular ON afiliados(titular_id);
@@ -0,0 +1,53 @@
+-- Padrón de afiliados para los drivers SQL. Reemplaza la tabla de 0002, que nunca se usó y tenía el
+-- plan como texto y no tenía fechas de alta y baja (las bases que aplicaron la vieja 0018 ya no la tienen).
+-- texto_busqueda se inserta vacío y lo completa la API al iniciar (repository.IndexarBusquedaPendiente).
+
+DROP INDEX IF EXISTS idx_afiliados_titular;
+
+DROP TABLE IF EXISTS afiliados;
+
+CREATE TABLE afiliados (
+	id             BIGSERIAL PRIMARY KEY,
+	nro_afiliado   TEXT NOT NULL UNIQUE,
+	dni            TEXT NOT NULL UNIQUE,
+	nombre         TEXT NOT NULL,
+	apellido       TEXT NOT NULL,
+	plan_medico_id INTEGER NOT NULL REFERENCES planes(id),
+	titular_id     INTEGER REFERENCES afiliados(id),
+	parentesco     TEXT NOT NULL,
+	email          TEXT NOT NULL DEFAULT '',
+	telefono       TEXT NOT NULL DEFAULT '',
+	ciudad         TEXT NOT NULL DEFAULT '',
+	provincia      TEXT NOT NULL DEFAULT '',
+	fecha_alta     TIMESTAMP NOT NULL,
+	fecha_baja     TIMESTAMP,
+	texto_busqueda TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_afiliados_titular ON afiliados(titular_id);
+
+CREATE INDEX idx_afiliados_plan ON afiliados(plan_medico_id);
+
+-- El mismo padrón de ejemplo que en memoria, con sus IDs: las solicitudes y situaciones ya guardadas
+-- apuntan a ellos. Los titulares van antes que sus integrantes por la referencia a titular_id.
+-- El afiliado 7 es el alta reciente: en memoria se da de alta 15 días antes de iniciar, acá con fecha fija.
+INSERT INTO afiliados (id, nro_afiliado, dni, nombre, apellido, plan_medico_id, titular_id, parentesco, email, telefono, ciudad, provincia, fecha_alta, fecha_baja) VALUES
+	(1, '15121231523', '43521489', 'María', 'Candia', 1, NULL, 'Titular', 'maria.candia@email.com', '011-4567-8901', 'Buenos Aires', 'Buenos Aires', '2018-03-01 00:00:00', NULL),
+	(2, '15121231524', '53521489', 'Stella', 'Rodriguez', 2, NULL, 'Titular', 'stella.rodriguez@email.com', '0341-234-5678', 'Rosario', 'Santa Fe', '2020-07-15 00:00:00', NULL),
+	(3, '15121231525', '40456015', 'Nicolas', 'Martin', 1, 1, 'Cónyuge', 'nicolas.martin@email.com', '011-4567-8902', 'Buenos Aires', 'Buenos Aires', '2018-03-01 00:00:00', NULL),
+	(5, '15121231527', '11000189', 'Facundo', 'Gomez', 1, NULL, 'Titular', 'facundo.gomez@email.com', '0351-422-1101', 'Córdoba', 'Córdoba', '2016-05-10 00:00:00', NULL),
+	(4, '15121231526', '12334555', 'Sofia', 'Lopez', 1, 5, 'Hija', 'sofia.lopez@email.com', '0351-422-1100', 'Córdoba', 'Córdoba', '2016-05-10 00:00:00', NULL),
+	(6, '15121231528', '29876543', 'Julieta', 'Paz', 2, NULL, 'Titular', 'julieta.paz@email.com', '011-4822-3344', 'Buenos Aires', 'Buenos Aires', '2017-02-01 00:00:00', '2025-06-30 00:00:00'),
+	(7, '15121231529', '44123987', 'Tomás', 'Ríos', 3, NULL, 'Titular', 'tomas.rios@email.com', '0342-455-1212', 'Santa Fe', 'Santa Fe', '2026-10-01 00:00:00', NULL),
+	(22, '15121232201', '32654708', 'Miguel', 'Osorio', 2, NULL, 'Titular', 'miguel.osorio@email.com', '011-4780-1122', 'Vicente López', 'Buenos Aires', '2019-02-01 00:00:00', NULL),
+	(2201, '15121232202', '52123789', 'Ana', 'Osorio', 2, 22, 'Hija', 'ana.osorio@email.com', '011-4780-1123', 'Vicente López', 'Buenos Aires', '2019-02-01 00:00:00', NULL),
+	(2202, '15121232203', '33987654', 'Luis', 'Osorio', 2, 22, 'Cónyuge', 'luis.osorio@email.com', '011-4780-1124', 'Vicente López', 'Buenos Aires', '2019-02-01 00:00:00', NULL),
+	(23, '15121232301', '28456123', 'Ana', 'Fernández', 3, NULL, 'Titular', 'ana.fernandez@email.com', '0221-455-7788', 'La Plata', 'Buenos Aires', '2021-09-01 00:00:00', NULL),
+	(24, '15121232401', '35789456', 'Roberto', 'Díaz', 1, NULL, 'Titular', 'roberto.diaz@email.com', '0261-423-9090', 'Mendoza', 'Mendoza', '2017-11-20 00:00:00', NULL),
+	(31, '15121233101', '45678089', 'David', 'Queen', 3, NULL, 'Titular', 'david.queen@email.com', '011-4311-2020', 'Buenos Aires', 'Buenos Aires', '2022-04-01 00:00:00', NULL),
+	(3101, '15121233102', '58456321', 'Pedro', 'Queen', 3, 31, 'Hijo', 'pedro.queen@email.com', '011-4311-2021', 'Buenos Aires', 'Buenos Aires', '2022-04-01 00:00:00', NULL),
+	(32, '15121233201', '38567123', 'Laura', 'García', 2, NULL, 'Titular', 'laura.garcia@email.com', '0341-448-3030', 'Rosario', 'Santa Fe', '2020-01-06 00:00:00', NULL),
+	(33, '15121233301', '42123456', 'Carlos', 'Martínez', 1, NULL, 'Titular', 'carlos.martinez@email.com', '0351-425-4040', 'Córdoba', 'Córdoba', '2015-08-03 00:00:00', NULL),
+	(45, '15121234501', '21345633', 'Daniela', 'Reynoso', 3, NULL, 'Titular', 'daniela.reynoso@email.com', '0299-443-5050', 'Neuquén', 'Neuquén', '2023-06-12 00:00:00', NULL),
+	(46, '15121234601', '30123456', 'Marcos', 'Ledesma', 2, NULL, 'Titular', 'marcos.ledesma@email.com', '0381-421-6060', 'San Miguel de Tucumán', 'Tucumán', '2019-10-01 00:00:00', NULL),
+	(47, '15121234701', '34567890', 'Lucía', 'Fernández', 1, NULL, 'Titular', 'lucia.fernandez@email.com', '0223-492-7070', 'Mar del Plata', 'Buenos Aires', '2024-03-18 00:00:00', NULL);
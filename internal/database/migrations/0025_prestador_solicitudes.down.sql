@@ -0,0 +1,11 @@
+DROP INDEX idx_reintegros_prestador;
+
+DROP INDEX idx_autorizaciones_prestador;
+
+ALTER TABLE reintegros DROP COLUMN prestador_nombre;
+ALTER TABLE reintegros DROP COLUMN prestador_cuit;
+ALTER TABLE reintegros DROP COLUMN prestador_id;
+
+ALTER TABLE autorizaciones DROP COLUMN prestador_nombre;
+ALTER TABLE autorizaciones DROP COLUMN prestador_cuit;
+ALTER TABLE autorizaciones DROP COLUMN prestador_id;
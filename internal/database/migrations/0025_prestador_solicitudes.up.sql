@@ -0,0 +1,42 @@
+-- Prestador que carga cada autorización y reintegro, como en las recetas. Con él se controla quién
+-- puede ver y subir adjuntos. Las anteriores se completan con el usuario de la primera entrada del
+-- historial si es un prestador; las que no lo tienen quedan con prestador_id 0 y solo las ven
+-- auditores y ADMIN.
+
+ALTER TABLE autorizaciones ADD COLUMN prestador_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE autorizaciones ADD COLUMN prestador_cuit TEXT NOT NULL DEFAULT '';
+ALTER TABLE autorizaciones ADD COLUMN prestador_nombre TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE reintegros ADD COLUMN prestador_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reintegros ADD COLUMN prestador_cuit TEXT NOT NULL DEFAULT '';
+ALTER TABLE reintegros ADD COLUMN prestador_nombre TEXT NOT NULL DEFAULT '';
+
+UPDATE autorizaciones SET prestador_id = COALESCE((
+	SELECT u.id
+	FROM autorizacion_historial h
+	JOIN usuarios u ON LOWER(u.username) = LOWER(h.usuario)
+	WHERE h.id = (SELECT MIN(id) FROM autorizacion_historial WHERE autorizacion_id = autorizaciones.id)
+	  AND u.rol = 'PRESTADOR'
+), 0);
+
+UPDATE autorizaciones SET
+	prestador_cuit = (SELECT cuit FROM usuarios WHERE usuarios.id = autorizaciones.prestador_id),
+	prestador_nombre = (SELECT nombre FROM usuarios WHERE usuarios.id = autorizaciones.prestador_id)
+WHERE prestador_id <> 0;
+
+UPDATE reintegros SET prestador_id = COALESCE((
+	SELECT u.id
+	FROM reintegro_historial h
+	JOIN usuarios u ON LOWER(u.username) = LOWER(h.usuario)
+	WHERE h.id = (SELECT MIN(id) FROM reintegro_historial WHERE reintegro_id = reintegros.id)
+	  AND u.rol = 'PRESTADOR'
+), 0);
+
+UPDATE reintegros SET
+	prestador_cuit = (SELECT cuit FROM usuarios WHERE usuarios.id = reintegros.prestador_id),
+	prestador_nombre = (SELECT nombre FROM usuarios WHERE usuarios.id = reintegros.prestador_id)
+WHERE prestador_id <> 0;
+
+CREATE INDEX idx_autorizaciones_prestador ON autorizaciones(prestador_id);
+
+CREATE INDEX idx_reintegros_prestador ON reintegros(prestador_id);
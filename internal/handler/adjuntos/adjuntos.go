@@ -0,0 +1,217 @@
+package adjuntos
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"prestadores-api/internal/middleware"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// margenMultipart cubre los headers y el campo categoria del multipart, además del archivo
+const margenMultipart = 64 << 10
+
+// AdjuntoHandler atiende los adjuntos de autorizaciones y reintegros. Los métodos reciben el
+// tipo de solicitud porque las rutas son las mismas bajo cada recurso.
+type AdjuntoHandler struct {
+	service  service.AdjuntoService
+	maxBytes int64
+	logger   *zap.Logger
+}
+
+func NewAdjuntoHandler(s service.AdjuntoService, maxBytes int64, logger *zap.Logger) *AdjuntoHandler {
+	return &AdjuntoHandler{service: s, maxBytes: maxBytes, logger: logger}
+}
+
+// SubirAdjunto recibe multipart/form-data con el campo "archivo" y opcionalmente "categoria"
+func (h *AdjuntoHandler) SubirAdjunto(tipo model.TipoSolicitud) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := h.parseID(c, "id")
+		if !ok {
+			return
+		}
+
+		usuario, ok := middleware.UsuarioActual(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+margenMultipart)
+		archivo, err := c.FormFile("archivo")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				h.responderError(c, &service.AdjuntoDemasiadoGrandeError{MaxBytes: h.maxBytes})
+				return
+			}
+			h.logger.Warn("Request inválido para adjuntar archivo", zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido: se espera multipart/form-data con el campo \"archivo\"", "details": err.Error()})
+			return
+		}
+		if archivo.Size > h.maxBytes {
+			h.responderError(c, &service.AdjuntoDemasiadoGrandeError{MaxBytes: h.maxBytes})
+			return
+		}
+
+		f, err := archivo.Open()
+		if err != nil {
+			h.logger.Error("Error al leer el archivo subido", zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el archivo"})
+			return
+		}
+		defer f.Close()
+		contenido, err := io.ReadAll(io.LimitReader(f, h.maxBytes+1))
+		if err != nil {
+			h.logger.Error("Error al leer el archivo subido", zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el archivo"})
+			return
+		}
+
+		h.logger.Info("Adjuntando archivo",
+			zap.String("endpoint", c.FullPath()),
+			zap.String("method", "POST"),
+			zap.Int("id", id),
+			zap.String("nombreArchivo", archivo.Filename),
+			zap.Int64("tamanio", archivo.Size),
+		)
+
+		req := model.SubirAdjuntoRequest{
+			Categoria:     model.CategoriaAdjunto(strings.ToUpper(strings.TrimSpace(c.PostForm("categoria")))),
+			NombreArchivo: archivo.Filename,
+			Contenido:     contenido,
+		}
+		adjunto, err := h.service.Adjuntar(tipo, id, req, usuario)
+		if err != nil {
+			h.logger.Error("Error al adjuntar archivo", zap.Int("id", id), zap.Error(err))
+			h.responderError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, adjunto)
+	}
+}
+
+func (h *AdjuntoHandler) GetAdjuntos(tipo model.TipoSolicitud) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := h.parseID(c, "id")
+		if !ok {
+			return
+		}
+
+		usuario, ok := middleware.UsuarioActual(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+			return
+		}
+
+		adjuntos, err := h.service.GetAdjuntos(tipo, id, usuario)
+		if err != nil {
+			h.logger.Error("Error al obtener adjuntos", zap.Int("id", id), zap.Error(err))
+			h.responderError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, adjuntos)
+	}
+}
+
+// DescargarAdjunto devuelve el archivo con su Content-Type detectado al subirlo y el SHA-256 en
+// Repr-Digest para que el cliente pueda verificarlo
+func (h *AdjuntoHandler) DescargarAdjunto(tipo model.TipoSolicitud) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := h.parseID(c, "id")
+		if !ok {
+			return
+		}
+		adjuntoID, ok := h.parseID(c, "adjuntoId")
+		if !ok {
+			return
+		}
+
+		usuario, ok := middleware.UsuarioActual(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+			return
+		}
+
+		adjunto, contenido, err := h.service.Descargar(tipo, id, adjuntoID, usuario)
+		if err != nil {
+			h.logger.Error("Error al descargar adjunto", zap.Int("id", id), zap.Int("adjuntoId", adjuntoID), zap.Error(err))
+			h.responderError(c, err)
+			return
+		}
+		defer contenido.Close()
+
+		headers := map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": adjunto.NombreArchivo}),
+			"ETag":                   `"` + adjunto.SHA256 + `"`,
+			"X-Content-Type-Options": "nosniff",
+		}
+		if suma, err := hex.DecodeString(adjunto.SHA256); err == nil {
+			headers["Repr-Digest"] = "sha-256=:" + base64.StdEncoding.EncodeToString(suma) + ":"
+		}
+
+		c.DataFromReader(http.StatusOK, adjunto.Tamanio, adjunto.ContentType, contenido, headers)
+		if errs := c.Errors.ByType(gin.ErrorTypeAny); len(errs) > 0 {
+			h.logger.Error("Error al enviar adjunto", zap.Int("adjuntoId", adjuntoID), zap.Error(errs.Last()))
+		}
+	}
+}
+
+func (h *AdjuntoHandler) parseID(c *gin.Context, param string) (int, bool) {
+	valor := c.Param(param)
+	id, err := strconv.Atoi(valor)
+	if err != nil {
+		h.logger.Warn("ID inválido", zap.String(param, valor))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *AdjuntoHandler) responderError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrSolicitudNoEncontrada) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Solicitud no encontrada"})
+		return
+	}
+	if errors.Is(err, service.ErrSolicitudAjena) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "La solicitud pertenece a otro prestador"})
+		return
+	}
+	if errors.Is(err, service.ErrAdjuntoNoEncontrado) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Adjunto no encontrado"})
+		return
+	}
+	if errors.Is(err, service.ErrChecksumAdjunto) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "El archivo guardado está dañado y no se puede descargar"})
+		return
+	}
+	if errors.Is(err, service.ErrAdjuntoDuplicado) {
+		c.JSON(http.StatusConflict, gin.H{"error": "El archivo ya está adjunto a la solicitud"})
+		return
+	}
+	var cerradaErr *service.SolicitudCerradaError
+	if errors.As(err, &cerradaErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": cerradaErr.Error(), "estadoActual": cerradaErr.Estado})
+		return
+	}
+	var grandeErr *service.AdjuntoDemasiadoGrandeError
+	if errors.As(err, &grandeErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": grandeErr.Error()})
+		return
+	}
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al procesar el adjunto"})
+}
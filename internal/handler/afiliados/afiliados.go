@@ -1,131 +1,113 @@
 package afiliados
 
 import (
+	"errors"
 	"net/http"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-type AfiliadoListItem struct {
-	ID         int    `json:"id"`
-	DNI        string `json:"dni"`
-	Nombre     string `json:"nombre"`
-	Apellido   string `json:"apellido"`
-	PlanMedico string `json:"planMedico"`
-	Titular    bool   `json:"titular"`
+type AfiliadoHandler struct {
+	service service.AfiliadoService
+	logger  *zap.Logger
 }
 
-// Detalle de afiliado
-type AfiliadoDetalle struct {
-	ID            int               `json:"id"`
-	NroAfiliado   string            `json:"nroAfiliado"`
-	DNI           string            `json:"dni"`
-	Nombre        string            `json:"nombre"`
-	Apellido      string            `json:"apellido"`
-	PlanMedico    string            `json:"planMedico"`
-	Titular       bool              `json:"titular"`
-	Email         string            `json:"email"`
-	Telefono      string            `json:"telefono"`
-	Ciudad        string            `json:"ciudad"`
-	Provincia     string            `json:"provincia"`
-	GrupoFamiliar []IntegranteGrupo `json:"grupoFamiliar"`
+func NewAfiliadoHandler(service service.AfiliadoService, logger *zap.Logger) *AfiliadoHandler {
+	return &AfiliadoHandler{
+		service: service,
+		logger:  logger,
+	}
 }
 
-// Integrante del grupo familiar (detalle)
-type IntegranteGrupo struct {
-	ID         int    `json:"id"`
-	DNI        string `json:"dni"`
-	Nombre     string `json:"nombre"`
-	Apellido   string `json:"apellido"`
-	PlanMedico string `json:"planMedico"`
-}
+/* ===== Endpoints ===== */
 
-type AfiliadoHandler struct {
-	logger *zap.Logger
-}
+// GET /v1/prestadores/afiliados  → lista paginada para la tabla
+// Query params: planId?, titular? (true|false), q? (DNI, nro de afiliado, nombre), page?, size?
+func (h *AfiliadoHandler) GetAfiliados(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "0")
+	sizeStr := c.DefaultQuery("size", "20")
 
-func NewAfiliadoHandler(logger *zap.Logger) *AfiliadoHandler {
-	return &AfiliadoHandler{logger: logger}
-}
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 0 {
+		page = 0
+	}
 
-var afiliadosListMock = []AfiliadoListItem{
-	{ID: 1, DNI: "43521489", Nombre: "María", Apellido: "Candia", PlanMedico: "Sancor Salud", Titular: true},
-	{ID: 2, DNI: "53521489", Nombre: "Stella", Apellido: "Rodriguez", PlanMedico: "Galeno 210", Titular: true},
-	{ID: 3, DNI: "40456015", Nombre: "Nicolas", Apellido: "Martin", PlanMedico: "Sancor Salud", Titular: false},
-	{ID: 4, DNI: "12334555", Nombre: "Sofia", Apellido: "Lopez", PlanMedico: "Swiss Medical", Titular: false},
-	{ID: 5, DNI: "11000189", Nombre: "Facundo", Apellido: "Gomez", PlanMedico: "Sancor Salud", Titular: true},
-}
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size <= 0 {
+		size = 20
+	}
 
-func detalleMockByID(id int) (AfiliadoDetalle, bool) {
-	switch id {
-	case 1:
-		return AfiliadoDetalle{
-			ID:          1,
-			NroAfiliado: "15121231523",
-			DNI:         "43521489",
-			Nombre:      "María",
-			Apellido:    "Candia",
-			PlanMedico:  "Sancor Salud",
-			Titular:     true,
-			Email:       "juan.perez@email.com",
-			Telefono:    "011-4567-8901",
-			Ciudad:      "Buenos Aires",
-			Provincia:   "Buenos Aires",
-			GrupoFamiliar: []IntegranteGrupo{
-				{ID: 1, DNI: "43521489", Nombre: "María", Apellido: "Candia", PlanMedico: "Sancor Salud"},
-				{ID: 2, DNI: "53521489", Nombre: "Stella", Apellido: "Candia", PlanMedico: "Sancor Salud"},
-			},
-		}, true
-	case 2:
-		return AfiliadoDetalle{
-			ID:          2,
-			NroAfiliado: "15121231524",
-			DNI:         "53521489",
-			Nombre:      "Stella",
-			Apellido:    "Candia",
-			PlanMedico:  "Sancor Salud",
-			Titular:     true,
-			Email:       "maria.rodriguez@email.com",
-			Telefono:    "0341-234-5678",
-			Ciudad:      "Rosario",
-			Provincia:   "Santa Fe",
-			GrupoFamiliar: []IntegranteGrupo{
-				{ID: 1, DNI: "43521489", Nombre: "María", Apellido: "Candia", PlanMedico: "Sancor Salud"},
-				{ID: 2, DNI: "53521489", Nombre: "Matias", Apellido: "Candia", PlanMedico: "Sancor Salud"},
-			},
-		}, true
-	default:
-		return AfiliadoDetalle{}, false
+	filtro := model.AfiliadoFiltro{
+		Query: c.Query("q"),
 	}
-}
 
-/* ===== Endpoints ===== */
+	if planStr := c.Query("planId"); planStr != "" {
+		planID, err := strconv.Atoi(planStr)
+		if err != nil || planID <= 0 {
+			h.logger.Warn("Parámetro planId inválido", zap.String("planId", planStr))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "El parámetro 'planId' debe ser un entero positivo"})
+			return
+		}
+		filtro.PlanID = planID
+	}
+
+	if titularStr := c.Query("titular"); titularStr != "" {
+		titular, err := strconv.ParseBool(titularStr)
+		if err != nil {
+			h.logger.Warn("Parámetro titular inválido", zap.String("titular", titularStr))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "El parámetro 'titular' debe ser true o false"})
+			return
+		}
+		filtro.Titular = &titular
+	}
 
-// GET /v1/prestadores/afiliados  → devuelve un array simple para la tabla
-func (h *AfiliadoHandler) GetAfiliados(c *gin.Context) {
 	h.logger.Info("Obteniendo lista de afiliados",
 		zap.String("endpoint", "/afiliados"),
-		zap.String("method", "GET"))
+		zap.String("method", "GET"),
+		zap.Int("planId", filtro.PlanID),
+		zap.String("query", filtro.Query),
+		zap.Int("page", page),
+		zap.Int("size", size),
+	)
+
+	response, err := h.service.GetAfiliados(filtro, page, size)
+	if err != nil {
+		h.logger.Error("Error al obtener afiliados", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener afiliados"})
+		return
+	}
 
-	c.JSON(http.StatusOK, afiliadosListMock)
+	c.JSON(http.StatusOK, response)
 }
 
-// GET /v1/prestadores/afiliados/:id  → devuelve detalle con grupo familiar
+// GET /v1/prestadores/afiliados/:afiliadoId  → devuelve detalle con grupo familiar
 func (h *AfiliadoHandler) GetAfiliadoDetalle(c *gin.Context) {
-	idStr := c.Param("id")
-	id, _ := strconv.Atoi(idStr)
+	idStr := c.Param("afiliadoId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		h.logger.Warn("afiliadoId inválido", zap.String("afiliadoId", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "afiliadoId inválido"})
+		return
+	}
 
 	h.logger.Info("Obteniendo detalle de afiliado",
-		zap.String("endpoint", "/afiliados/:id"),
+		zap.String("endpoint", "/afiliados/:afiliadoId"),
 		zap.String("method", "GET"),
-		zap.String("id", idStr),
+		zap.Int("afiliadoId", id),
 	)
 
-	detalle, ok := detalleMockByID(id)
-	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+	detalle, err := h.service.GetAfiliadoDetalle(id)
+	if err != nil {
+		h.logger.Error("Error al obtener afiliado", zap.Int("afiliadoId", id), zap.Error(err))
+		if errors.Is(err, service.ErrAfiliadoNoEncontrado) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener afiliado"})
 		return
 	}
 
@@ -0,0 +1,148 @@
+package afiliados
+
+import (
+	"errors"
+	"net/http"
+	"prestadores-api/internal/middleware"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CuentaCobroHandler struct {
+	service service.CuentaCobroService
+	logger  *zap.Logger
+}
+
+func NewCuentaCobroHandler(service service.CuentaCobroService, logger *zap.Logger) *CuentaCobroHandler {
+	return &CuentaCobroHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GET /v1/prestadores/afiliados/:afiliadoId/cuentas-cobro
+// Solo las cuentas activas, que son las que se pueden elegir en un reintegro
+func (h *CuentaCobroHandler) GetCuentasCobro(c *gin.Context) {
+	afiliadoID, ok := h.parseID(c, "afiliadoId")
+	if !ok {
+		return
+	}
+
+	h.logger.Info("Listando cuentas de cobro",
+		zap.String("endpoint", "/afiliados/:afiliadoId/cuentas-cobro"),
+		zap.String("method", "GET"),
+		zap.Int("afiliadoId", afiliadoID),
+	)
+
+	cuentas, err := h.service.GetCuentas(afiliadoID)
+	if err != nil {
+		h.logger.Error("Error al obtener cuentas de cobro", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		h.responderError(c, err, "Error al obtener cuentas de cobro")
+		return
+	}
+	c.JSON(http.StatusOK, cuentas)
+}
+
+// POST /v1/prestadores/afiliados/:afiliadoId/cuentas-cobro
+func (h *CuentaCobroHandler) CreateCuentaCobro(c *gin.Context) {
+	afiliadoID, ok := h.parseID(c, "afiliadoId")
+	if !ok {
+		return
+	}
+
+	var req model.CreateCuentaCobroRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para registrar cuenta de cobro", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
+	h.logger.Info("Registrando cuenta de cobro",
+		zap.String("endpoint", "/afiliados/:afiliadoId/cuentas-cobro"),
+		zap.String("method", "POST"),
+		zap.Int("afiliadoId", afiliadoID),
+	)
+
+	cuenta, err := h.service.CreateCuenta(afiliadoID, req, usuario)
+	if err != nil {
+		h.logger.Error("Error al registrar cuenta de cobro", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		h.responderError(c, err, "Error al registrar cuenta de cobro")
+		return
+	}
+	c.JSON(http.StatusCreated, cuenta)
+}
+
+// DELETE /v1/prestadores/afiliados/:afiliadoId/cuentas-cobro/:cuentaId
+// Baja lógica: deja de ofrecerse para nuevos reintegros
+func (h *CuentaCobroHandler) BajaCuentaCobro(c *gin.Context) {
+	afiliadoID, ok := h.parseID(c, "afiliadoId")
+	if !ok {
+		return
+	}
+	cuentaID, ok := h.parseID(c, "cuentaId")
+	if !ok {
+		return
+	}
+
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
+	h.logger.Info("Dando de baja cuenta de cobro",
+		zap.String("endpoint", "/afiliados/:afiliadoId/cuentas-cobro/:cuentaId"),
+		zap.String("method", "DELETE"),
+		zap.Int("afiliadoId", afiliadoID),
+		zap.Int("cuentaId", cuentaID),
+	)
+
+	if err := h.service.BajaCuenta(afiliadoID, cuentaID, usuario); err != nil {
+		h.logger.Error("Error al dar de baja cuenta de cobro", zap.Int("cuentaId", cuentaID), zap.Error(err))
+		h.responderError(c, err, "Error al dar de baja la cuenta de cobro")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CuentaCobroHandler) parseID(c *gin.Context, param string) (int, bool) {
+	valor := c.Param(param)
+	id, err := strconv.Atoi(valor)
+	if err != nil || id <= 0 {
+		h.logger.Warn(param+" inválido", zap.String(param, valor))
+		c.JSON(http.StatusBadRequest, gin.H{"error": param + " inválido"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *CuentaCobroHandler) responderError(c *gin.Context, err error, mensaje string) {
+	if errors.Is(err, service.ErrAfiliadoNoEncontrado) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+		return
+	}
+	if errors.Is(err, service.ErrCuentaCobroNoEncontrada) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cuenta de cobro no encontrada"})
+		return
+	}
+	if errors.Is(err, service.ErrCuentaCobroDuplicada) {
+		c.JSON(http.StatusConflict, gin.H{"error": "El afiliado ya tiene registrada esa cuenta"})
+		return
+	}
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": mensaje})
+}
@@ -0,0 +1,77 @@
+package afiliados
+
+import (
+	"errors"
+	"net/http"
+	"prestadores-api/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ElegibilidadHandler struct {
+	service     service.ElegibilidadService
+	nomenclador service.NomencladorService
+	logger      *zap.Logger
+}
+
+func NewElegibilidadHandler(service service.ElegibilidadService, nomenclador service.NomencladorService, logger *zap.Logger) *ElegibilidadHandler {
+	return &ElegibilidadHandler{
+		service:     service,
+		nomenclador: nomenclador,
+		logger:      logger,
+	}
+}
+
+// GET /v1/prestadores/afiliados/:afiliadoId/elegibilidad?prestacion=... | ?codigo=...
+// Con codigo se evalúa la especialidad del nomenclador. Devuelve 200 también cuando no es
+// elegible: el resultado viene en "elegible" y "motivos"
+func (h *ElegibilidadHandler) GetElegibilidad(c *gin.Context) {
+	idStr := c.Param("afiliadoId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		h.logger.Warn("afiliadoId inválido", zap.String("afiliadoId", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "afiliadoId inválido"})
+		return
+	}
+
+	prestacion := c.Query("prestacion")
+	if codigo := c.Query("codigo"); codigo != "" {
+		p, err := h.nomenclador.GetPrestacion(codigo)
+		if err != nil {
+			if errors.Is(err, service.ErrCodigoNoEncontrado) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Código no encontrado en el nomenclador"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar elegibilidad"})
+			return
+		}
+		prestacion = p.Especialidad
+	}
+
+	h.logger.Info("Consultando elegibilidad",
+		zap.String("endpoint", "/afiliados/:afiliadoId/elegibilidad"),
+		zap.String("method", "GET"),
+		zap.Int("afiliadoId", id),
+		zap.String("prestacion", prestacion),
+	)
+
+	elegibilidad, err := h.service.VerificarElegibilidad(id, prestacion)
+	if err != nil {
+		h.logger.Error("Error al verificar elegibilidad", zap.Int("afiliadoId", id), zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
+		if errors.Is(err, service.ErrAfiliadoNoEncontrado) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al verificar elegibilidad"})
+		return
+	}
+
+	c.JSON(http.StatusOK, elegibilidad)
+}
@@ -1,115 +1,171 @@
 package afiliados
 
 import (
+	"errors"
 	"net/http"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-// ===== Modelos =====
-
-type NotaTurno struct {
-	ID          int       `json:"id"`
-	Fecha       time.Time `json:"fecha"`
-	PrestadorID int       `json:"prestadorId"`
-	Texto       string    `json:"texto"`
-}
-
-type Turno struct {
-	ID           int         `json:"id"`
-	Fecha        time.Time   `json:"fecha"`
-	Especialidad string      `json:"especialidad"`
-	Estado       string      `json:"estado"`
-	Notas        []NotaTurno `json:"notas"`
-}
-
-type HistoriaClinica struct {
-	AfiliadoID int     `json:"afiliadoId"`
-	Page       int     `json:"page"`
-	Size       int     `json:"size"`
-	Total      int     `json:"total"`
-	Turnos     []Turno `json:"turnos"`
-}
-
-// ===== Handler =====
-
 type HistoriaClinicaHandler struct {
-	logger *zap.Logger
+	service service.HistoriaClinicaService
+	logger  *zap.Logger
 }
 
-func NewHistoriaClinicaHandler(logger *zap.Logger) *HistoriaClinicaHandler {
+func NewHistoriaClinicaHandler(service service.HistoriaClinicaService, logger *zap.Logger) *HistoriaClinicaHandler {
 	return &HistoriaClinicaHandler{
-		logger: logger,
+		service: service,
+		logger:  logger,
 	}
 }
 
-// GetHistoriaClinica GET /v1/prestadores/afiliados/:id/historia-clinica
+// GetHistoriaClinica GET /v1/prestadores/afiliados/:afiliadoId/historia-clinica
+// Query params: prestadorId?, especialidad? (código del catálogo), page?, size?
 func (h *HistoriaClinicaHandler) GetHistoriaClinica(c *gin.Context) {
 	h.logger.Info("Obteniendo historia clínica",
-		zap.String("endpoint", "/afiliados/:id/historia-clinica"),
+		zap.String("endpoint", "/afiliados/:afiliadoId/historia-clinica"),
 		zap.String("method", "GET"))
 
-	// --- Validar y convertir :id ---
-	idStr := c.Param("id")
+	// --- Validar y convertir :afiliadoId ---
+	idStr := c.Param("afiliadoId")
 	afiliadoID, err := strconv.Atoi(idStr)
 	if err != nil || afiliadoID <= 0 {
-		h.logger.Warn("Parametro id inválido",
-			zap.String("id", idStr),
+		h.logger.Warn("Parametro afiliadoId inválido",
+			zap.String("afiliadoId", idStr),
 			zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "El parámetro 'id' debe ser un entero positivo.",
+			"error": "El parámetro 'afiliadoId' debe ser un entero positivo.",
 		})
 		return
 	}
 
-	turnos := []Turno{
-		{
-			ID:           500,
-			Fecha:        time.Date(2025, 9, 20, 10, 0, 0, 0, time.UTC),
-			Especialidad: "Clínica",
-			Estado:       "RESERVADO",
-			Notas: []NotaTurno{
-				{ID: 10, Fecha: time.Date(2025, 9, 20, 10, 30, 0, 0, time.UTC), PrestadorID: 45, Texto: "Control general"},
-			},
-		},
-		{
-			ID:           501,
-			Fecha:        time.Date(2025, 9, 25, 15, 0, 0, 0, time.UTC),
-			Especialidad: "Kinesiología",
-			Estado:       "ATENDIDO",
-			Notas: []NotaTurno{
-				{ID: 10, Fecha: time.Date(2025, 9, 20, 10, 30, 0, 0, time.UTC), PrestadorID: 45, Texto: "Control general"},
-				{ID: 12, Fecha: time.Date(2025, 9, 25, 15, 45, 0, 0, time.UTC), PrestadorID: 55, Texto: "Ejercicios domiciliarios"},
-				{ID: 14, Fecha: time.Date(2025, 9, 25, 15, 50, 0, 0, time.UTC), PrestadorID: 55, Texto: "Se pudo notar un leve problema en la rodilla izquierda"},
-			},
-		},
-	}
-
-	// Filtro por prestadorId
-	if pidStr := c.Query("prestadorId"); pidStr != "" {
-		if pid, err := strconv.Atoi(pidStr); err == nil && pid > 0 {
-			for i := range turnos {
-				filtradas := make([]NotaTurno, 0, len(turnos[i].Notas))
-				for _, n := range turnos[i].Notas {
-					if n.PrestadorID == pid {
-						filtradas = append(filtradas, n)
-					}
-				}
-				turnos[i].Notas = filtradas
-			}
-		}
+	// Filtro por prestadorId: un valor inválido se ignora
+	prestadorID, err := strconv.Atoi(c.DefaultQuery("prestadorId", "0"))
+	if err != nil || prestadorID < 0 {
+		prestadorID = 0
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "0"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	size, err := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if err != nil || size <= 0 {
+		size = 20
 	}
 
-	historia := HistoriaClinica{
-		AfiliadoID: afiliadoID,
-		Page:       0,
-		Size:       20,
-		Total:      len(turnos),
-		Turnos:     turnos,
+	historia, err := h.service.GetHistoriaClinica(afiliadoID, prestadorID, c.Query("especialidad"), page, size)
+	if err != nil {
+		h.logger.Error("Error al obtener historia clínica", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		if errors.Is(err, service.ErrAfiliadoNoEncontrado) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener historia clínica"})
+		return
 	}
 
 	c.JSON(http.StatusOK, historia)
 }
+
+// CreateTurno POST /v1/prestadores/afiliados/:afiliadoId/turnos
+func (h *HistoriaClinicaHandler) CreateTurno(c *gin.Context) {
+	idStr := c.Param("afiliadoId")
+	afiliadoID, err := strconv.Atoi(idStr)
+	if err != nil || afiliadoID <= 0 {
+		h.logger.Warn("afiliadoId inválido", zap.String("afiliadoId", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "afiliadoId inválido"})
+		return
+	}
+
+	var req model.CreateTurnoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para crear turno", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+	req.AfiliadoID = afiliadoID
+
+	h.logger.Info("Creando turno",
+		zap.String("endpoint", "/afiliados/:afiliadoId/turnos"),
+		zap.String("method", "POST"),
+		zap.Int("afiliadoId", afiliadoID),
+		zap.String("especialidadCodigo", req.EspecialidadCodigo),
+	)
+
+	turno, err := h.service.CreateTurno(req)
+	if err != nil {
+		h.logger.Error("Error al crear turno", zap.Error(err))
+		h.responderErrorTurno(c, err, "Error al crear turno")
+		return
+	}
+
+	c.JSON(http.StatusCreated, turno)
+}
+
+// UpdateTurno PATCH /v1/prestadores/afiliados/:afiliadoId/turnos/:turnoId
+func (h *HistoriaClinicaHandler) UpdateTurno(c *gin.Context) {
+	afiliadoIDStr := c.Param("afiliadoId")
+	turnoIDStr := c.Param("turnoId")
+	afiliadoID, err1 := strconv.Atoi(afiliadoIDStr)
+	turnoID, err2 := strconv.Atoi(turnoIDStr)
+	if err1 != nil || err2 != nil || afiliadoID <= 0 || turnoID <= 0 {
+		h.logger.Warn("IDs inválidos", zap.String("afiliadoId", afiliadoIDStr), zap.String("turnoId", turnoIDStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "IDs inválidos"})
+		return
+	}
+
+	var req model.UpdateTurnoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para actualizar turno", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	h.logger.Info("Actualizando turno",
+		zap.String("endpoint", "/afiliados/:afiliadoId/turnos/:turnoId"),
+		zap.String("method", "PATCH"),
+		zap.Int("afiliadoId", afiliadoID),
+		zap.Int("turnoId", turnoID),
+	)
+
+	turno, err := h.service.UpdateTurno(afiliadoID, turnoID, req)
+	if err != nil {
+		h.logger.Error("Error al actualizar turno", zap.Int("turnoId", turnoID), zap.Error(err))
+		h.responderErrorTurno(c, err, "Error al actualizar turno")
+		return
+	}
+
+	c.JSON(http.StatusOK, turno)
+}
+
+func (h *HistoriaClinicaHandler) responderErrorTurno(c *gin.Context, err error, mensaje string) {
+	if errors.Is(err, service.ErrAfiliadoNoEncontrado) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+		return
+	}
+	if errors.Is(err, service.ErrTurnoNoEncontrado) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Turno no encontrado"})
+		return
+	}
+	var especialidadErr *service.EspecialidadInvalidaError
+	if errors.As(err, &especialidadErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": especialidadErr.Error()})
+		return
+	}
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": mensaje})
+}
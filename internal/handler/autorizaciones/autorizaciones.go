@@ -1,7 +1,9 @@
 package autorizaciones
 
 import (
+	"errors"
 	"net/http"
+	"prestadores-api/internal/middleware"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/service"
 	"strconv"
@@ -22,9 +24,10 @@ func NewAutorizacionHandler(service service.AutorizacionService, logger *zap.Log
 	}
 }
 
-// Query params: estado?, q?, page?, size?, sort?
+// Query params: estado?, especialidad? (código del catálogo), q?, page?, size?, sort?
 func (h *AutorizacionHandler) GetAutorizaciones(c *gin.Context) {
 	estado := c.DefaultQuery("estado", "")
+	especialidad := c.DefaultQuery("especialidad", "")
 	query := c.DefaultQuery("q", "")
 	pageStr := c.DefaultQuery("page", "0")
 	sizeStr := c.DefaultQuery("size", "20")
@@ -44,15 +47,21 @@ func (h *AutorizacionHandler) GetAutorizaciones(c *gin.Context) {
 		zap.String("endpoint", "/solicitudes/autorizaciones"),
 		zap.String("method", "GET"),
 		zap.String("estado", estado),
+		zap.String("especialidad", especialidad),
 		zap.String("query", query),
 		zap.Int("page", page),
 		zap.Int("size", size),
 		zap.String("sort", sort),
 	)
 
-	response, err := h.service.GetAutorizaciones(estado, query, page, size, sort)
+	response, err := h.service.GetAutorizaciones(estado, especialidad, query, page, size, sort)
 	if err != nil {
 		h.logger.Error("Error al obtener autorizaciones", zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener autorizaciones"})
 		return
 	}
@@ -94,17 +103,30 @@ func (h *AutorizacionHandler) CreateAutorizacion(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Creando nueva autorización",
 		zap.String("endpoint", "/solicitudes/autorizaciones"),
 		zap.String("method", "POST"),
 		zap.Int("afiliadoId", req.AfiliadoID),
-		zap.String("procedimiento", req.Procedimiento),
-		zap.String("especialidad", req.Especialidad),
+		zap.String("procedimientoCodigo", req.ProcedimientoCodigo),
 	)
 
-	response, err := h.service.CreateAutorizacion(req)
+	response, err := h.service.CreateAutorizacion(req, usuario)
 	if err != nil {
 		h.logger.Error("Error al crear autorización", zap.Error(err))
+		if responderErrorValidacion(c, err) {
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear autorización"})
 		return
 	}
@@ -133,13 +155,24 @@ func (h *AutorizacionHandler) UpdateAutorizacion(c *gin.Context) {
 		zap.String("endpoint", "/solicitudes/autorizaciones/:id"),
 		zap.String("method", "PATCH"),
 		zap.Int("id", id),
-		zap.String("procedimiento", req.Procedimiento),
-		zap.String("especialidad", req.Especialidad),
+		zap.String("procedimientoCodigo", req.ProcedimientoCodigo),
 	)
 
 	err = h.service.UpdateAutorizacion(id, req)
 	if err != nil {
 		h.logger.Error("Error al actualizar autorización", zap.Int("id", id), zap.Error(err))
+		var noEditableErr *service.SolicitudNoEditableError
+		if errors.As(err, &noEditableErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":            err.Error(),
+				"estadoActual":     noEditableErr.Estado,
+				"estadosEditables": noEditableErr.Editables,
+			})
+			return
+		}
+		if responderErrorValidacion(c, err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Autorización no encontrada"})
 		return
 	}
@@ -164,20 +197,66 @@ func (h *AutorizacionHandler) CambiarEstadoAutorizacion(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Cambiando estado de autorización",
 		zap.String("endpoint", "/solicitudes/autorizaciones/:id/estado"),
 		zap.String("method", "PATCH"),
 		zap.Int("id", id),
 		zap.String("nuevoEstado", string(req.NuevoEstado)),
-		zap.String("usuario", req.Usuario),
+		zap.String("usuario", usuario.Username),
 	)
 
-	response, err := h.service.CambiarEstadoAutorizacion(id, req)
+	response, err := h.service.CambiarEstadoAutorizacion(id, req, usuario)
 	if err != nil {
 		h.logger.Error("Error al cambiar estado", zap.Int("id", id), zap.Error(err))
+		var transicionErr *service.TransicionInvalidaError
+		if errors.As(err, &transicionErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":                  err.Error(),
+				"estadoActual":           transicionErr.EstadoActual,
+				"transicionesPermitidas": transicionErr.Permitidas,
+			})
+			return
+		}
+		var permisoErr *service.PermisoDenegadoError
+		if errors.As(err, &permisoErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "rol": permisoErr.Rol})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// responderErrorValidacion responde 422 cuando el afiliado, el código del nomenclador o la
+// especialidad no son válidos, o el afiliado no es elegible. Devuelve false si el error es de otro tipo.
+func responderErrorValidacion(c *gin.Context, err error) bool {
+	var afiliadoErr *service.AfiliadoInexistenteError
+	if errors.As(err, &afiliadoErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": afiliadoErr.Error()})
+		return true
+	}
+	var codigoErr *service.CodigoNomencladorError
+	if errors.As(err, &codigoErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": codigoErr.Error()})
+		return true
+	}
+	var especialidadErr *service.EspecialidadInvalidaError
+	if errors.As(err, &especialidadErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": especialidadErr.Error()})
+		return true
+	}
+	var elegibilidadErr *service.AfiliadoNoElegibleError
+	if errors.As(err, &elegibilidadErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": elegibilidadErr.Error(), "motivos": elegibilidadErr.Elegibilidad.Motivos})
+		return true
+	}
+	return false
+}
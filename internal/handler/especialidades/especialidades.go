@@ -0,0 +1,38 @@
+package especialidades
+
+import (
+	"net/http"
+	"prestadores-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type EspecialidadHandler struct {
+	service service.EspecialidadService
+	logger  *zap.Logger
+}
+
+func NewEspecialidadHandler(service service.EspecialidadService, logger *zap.Logger) *EspecialidadHandler {
+	return &EspecialidadHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GET /v1/prestadores/especialidades  → catálogo completo, para selects
+func (h *EspecialidadHandler) GetEspecialidades(c *gin.Context) {
+	h.logger.Info("Obteniendo especialidades",
+		zap.String("endpoint", "/especialidades"),
+		zap.String("method", "GET"),
+	)
+
+	especialidades, err := h.service.GetEspecialidades()
+	if err != nil {
+		h.logger.Error("Error al obtener especialidades", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener especialidades"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": especialidades})
+}
@@ -1,32 +1,38 @@
 package login
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"prestadores-api/internal/middleware"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type LoginHandler struct {
-	logger *zap.Logger
+	service service.AuthService
+	logger  *zap.Logger
 }
 
-type LoginInfo struct {
-	Username string `json:"username" binding:"required"`
-}
-
-func NewLoginHandler(logger *zap.Logger) *LoginHandler {
+func NewLoginHandler(service service.AuthService, logger *zap.Logger) *LoginHandler {
 	return &LoginHandler{
-		logger: logger,
+		service: service,
+		logger:  logger,
 	}
 }
 
+// POST /v1/prestadores/login
 func (h *LoginHandler) Login(c *gin.Context) {
 	h.logger.Info("Ejecutando Login App",
 		zap.String("endpoint", "/login"),
 		zap.String("method", "POST"))
 
-	var loginInfo LoginInfo
+	var loginInfo model.LoginRequest
 
 	if parseError := c.ShouldBindJSON(&loginInfo); parseError != nil {
 		h.logger.Error("Error al parsear request", zap.Error(parseError))
@@ -41,8 +47,83 @@ func (h *LoginHandler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":  "Login success",
-		"username": loginInfo.Username,
-	})
+	if loginInfo.Password == "" {
+		h.logger.Warn("password vacío en login")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El campo 'password' es obligatorio"})
+		return
+	}
+
+	response, err := h.service.Login(loginInfo)
+	if err != nil {
+		h.responderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /v1/prestadores/refresh  → rota el refresh token y emite un nuevo access token
+func (h *LoginHandler) Refresh(c *gin.Context) {
+	h.logger.Info("Refrescando tokens",
+		zap.String("endpoint", "/refresh"),
+		zap.String("method", "POST"))
+
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para refresh", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	response, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		h.responderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /v1/prestadores/logout  → revoca el refresh token
+func (h *LoginHandler) Logout(c *gin.Context) {
+	h.logger.Info("Ejecutando Logout",
+		zap.String("endpoint", "/logout"),
+		zap.String("method", "POST"))
+
+	var req model.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para logout", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
+	if err := h.service.Logout(req.RefreshToken, usuario); err != nil {
+		h.responderError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LoginHandler) responderError(c *gin.Context, err error) {
+	var bloqueadoErr *service.UsuarioBloqueadoError
+	switch {
+	case errors.As(err, &bloqueadoErr):
+		segundos := int(math.Ceil(time.Until(bloqueadoErr.Hasta).Seconds()))
+		c.Header("Retry-After", strconv.Itoa(segundos))
+		c.JSON(http.StatusLocked, gin.H{"error": "Usuario bloqueado por intentos fallidos", "bloqueadoHasta": bloqueadoErr.Hasta})
+	case errors.Is(err, service.ErrCredencialesInvalidas), errors.Is(err, service.ErrRefreshTokenInvalido):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.As(err, new(*service.ServiceError)):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Error de autenticación", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al procesar la autenticación"})
+	}
 }
@@ -0,0 +1,80 @@
+package nomenclador
+
+import (
+	"errors"
+	"net/http"
+	"prestadores-api/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type NomencladorHandler struct {
+	service service.NomencladorService
+	logger  *zap.Logger
+}
+
+func NewNomencladorHandler(service service.NomencladorService, logger *zap.Logger) *NomencladorHandler {
+	return &NomencladorHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GET /v1/prestadores/nomenclador?q=&especialidad=&limit=  → typeahead por código o descripción;
+// especialidad es el código del catálogo
+func (h *NomencladorHandler) BuscarPrestaciones(c *gin.Context) {
+	query := c.DefaultQuery("q", "")
+	especialidad := c.DefaultQuery("especialidad", "")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+
+	h.logger.Info("Buscando en el nomenclador",
+		zap.String("endpoint", "/nomenclador"),
+		zap.String("method", "GET"),
+		zap.String("query", query),
+		zap.String("especialidad", especialidad),
+		zap.Int("limit", limit),
+	)
+
+	resp, err := h.service.BuscarPrestaciones(query, especialidad, limit)
+	if err != nil {
+		h.logger.Error("Error al buscar en el nomenclador", zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar en el nomenclador"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /v1/prestadores/nomenclador/:codigo
+func (h *NomencladorHandler) GetPrestacion(c *gin.Context) {
+	codigo := c.Param("codigo")
+
+	h.logger.Info("Obteniendo prestación del nomenclador",
+		zap.String("endpoint", "/nomenclador/:codigo"),
+		zap.String("method", "GET"),
+		zap.String("codigo", codigo),
+	)
+
+	prestacion, err := h.service.GetPrestacion(codigo)
+	if err != nil {
+		if errors.Is(err, service.ErrCodigoNoEncontrado) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Código no encontrado en el nomenclador"})
+			return
+		}
+		h.logger.Error("Error al obtener prestación del nomenclador", zap.String("codigo", codigo), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener prestación"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prestacion)
+}
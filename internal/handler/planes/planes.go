@@ -0,0 +1,166 @@
+package planes
+
+import (
+	"errors"
+	"net/http"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type PlanHandler struct {
+	service service.PlanService
+	logger  *zap.Logger
+}
+
+func NewPlanHandler(service service.PlanService, logger *zap.Logger) *PlanHandler {
+	return &PlanHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GET /v1/prestadores/planes  → catálogo completo con sus coberturas
+func (h *PlanHandler) GetPlanes(c *gin.Context) {
+	h.logger.Info("Obteniendo planes",
+		zap.String("endpoint", "/planes"),
+		zap.String("method", "GET"),
+	)
+
+	planes, err := h.service.GetPlanes()
+	if err != nil {
+		h.logger.Error("Error al obtener planes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener planes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": planes})
+}
+
+// GET /v1/prestadores/planes/:planId
+func (h *PlanHandler) GetPlanByID(c *gin.Context) {
+	id, ok := h.parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.service.GetPlanByID(id)
+	if err != nil {
+		h.responderError(c, err, "Error al obtener plan")
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// POST /v1/prestadores/planes
+func (h *PlanHandler) CreatePlan(c *gin.Context) {
+	var req model.PlanMedicoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para crear plan", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	h.logger.Info("Creando plan",
+		zap.String("endpoint", "/planes"),
+		zap.String("method", "POST"),
+		zap.String("nombre", req.Nombre),
+	)
+
+	plan, err := h.service.CreatePlan(req)
+	if err != nil {
+		h.responderError(c, err, "Error al crear plan")
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+// PUT /v1/prestadores/planes/:planId  → reemplaza nombre, vigencia y coberturas
+func (h *PlanHandler) UpdatePlan(c *gin.Context) {
+	id, ok := h.parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	var req model.PlanMedicoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para actualizar plan", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	h.logger.Info("Actualizando plan",
+		zap.String("endpoint", "/planes/:planId"),
+		zap.String("method", "PUT"),
+		zap.Int("planId", id),
+	)
+
+	plan, err := h.service.UpdatePlan(id, req)
+	if err != nil {
+		h.responderError(c, err, "Error al actualizar plan")
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// DELETE /v1/prestadores/planes/:planId  → 409 si hay afiliados con el plan
+func (h *PlanHandler) DeletePlan(c *gin.Context) {
+	id, ok := h.parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	h.logger.Info("Eliminando plan",
+		zap.String("endpoint", "/planes/:planId"),
+		zap.String("method", "DELETE"),
+		zap.Int("planId", id),
+	)
+
+	if err := h.service.DeletePlan(id); err != nil {
+		h.responderError(c, err, "Error al eliminar plan")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PlanHandler) parsePlanID(c *gin.Context) (int, bool) {
+	idStr := c.Param("planId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		h.logger.Warn("planId inválido", zap.String("planId", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "planId inválido"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *PlanHandler) responderError(c *gin.Context, err error, mensaje string) {
+	h.logger.Error(mensaje, zap.Error(err))
+
+	if errors.Is(err, service.ErrPlanNoEncontrado) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plan no encontrado"})
+		return
+	}
+	if errors.Is(err, service.ErrPlanExistente) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	var enUsoErr *service.PlanEnUsoError
+	if errors.As(err, &enUsoErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": enUsoErr.Error()})
+		return
+	}
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": mensaje})
+}
@@ -0,0 +1,132 @@
+package recetas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"prestadores-api/internal/middleware"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RecetaElectronicaHandler struct {
+	service service.RecetaElectronicaService
+	logger  *zap.Logger
+}
+
+func NewRecetaElectronicaHandler(service service.RecetaElectronicaService, logger *zap.Logger) *RecetaElectronicaHandler {
+	return &RecetaElectronicaHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GET /v1/prestadores/solicitudes/recetas/:id/pdf  → receta electrónica de una receta APROBADO
+func (h *RecetaElectronicaHandler) GetRecetaPDF(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.logger.Warn("ID inválido", zap.String("id", idStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	h.logger.Info("Generando PDF de receta",
+		zap.String("endpoint", "/solicitudes/recetas/:id/pdf"),
+		zap.String("method", "GET"),
+		zap.Int("id", id),
+	)
+
+	pdf, detalle, err := h.service.GenerarPDF(id)
+	if err != nil {
+		h.logger.Error("Error al generar PDF de receta", zap.Int("id", id), zap.Error(err))
+		var noAprobadaErr *service.RecetaNoAprobadaError
+		if errors.As(err, &noAprobadaErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": noAprobadaErr.Error(), "estadoActual": noAprobadaErr.Estado})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Receta no encontrada"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="receta-%d.pdf"`, detalle.ID))
+	c.Header("X-Codigo-Verificacion", detalle.CodigoVerificacion)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GET /v1/prestadores/recetas/verificar/:codigo  (público) → validez de la receta para la farmacia
+func (h *RecetaElectronicaHandler) VerificarReceta(c *gin.Context) {
+	codigo := c.Param("codigo")
+
+	h.logger.Info("Verificando receta electrónica",
+		zap.String("endpoint", "/recetas/verificar/:codigo"),
+		zap.String("method", "GET"),
+		zap.String("codigo", codigo),
+	)
+
+	verificacion, err := h.service.Verificar(codigo)
+	if err != nil {
+		h.responderError(c, err, "Error al verificar receta")
+		return
+	}
+
+	c.JSON(http.StatusOK, verificacion)
+}
+
+// POST /v1/prestadores/recetas/verificar/:codigo/dispensar  (rol FARMACIA) → la farmacia registra la entrega
+func (h *RecetaElectronicaHandler) DispensarReceta(c *gin.Context) {
+	codigo := c.Param("codigo")
+
+	// El body es opcional: sin envases se entregan todos los pendientes
+	var req model.DispensarRecetaRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Warn("Request inválido para dispensar receta", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	farmacia, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
+	h.logger.Info("Dispensando receta electrónica",
+		zap.String("endpoint", "/recetas/verificar/:codigo/dispensar"),
+		zap.String("method", "POST"),
+		zap.String("codigo", codigo),
+		zap.String("farmaciaCuit", farmacia.CUIT),
+	)
+
+	verificacion, err := h.service.Dispensar(codigo, req, farmacia)
+	if err != nil {
+		h.responderError(c, err, "Error al dispensar receta")
+		return
+	}
+
+	c.JSON(http.StatusOK, verificacion)
+}
+
+func (h *RecetaElectronicaHandler) responderError(c *gin.Context, err error, mensaje string) {
+	h.logger.Warn(mensaje, zap.Error(err))
+	if errors.Is(err, service.ErrCodigoVerificacionNoEncontrado) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Código de verificación inexistente"})
+		return
+	}
+	var noDispensableErr *service.RecetaNoDispensableError
+	if errors.As(err, &noDispensableErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": noDispensableErr.Error()})
+		return
+	}
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": mensaje})
+}
@@ -1,7 +1,9 @@
 package recetas
 
 import (
+	"errors"
 	"net/http"
+	"prestadores-api/internal/middleware"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/service"
 	"strconv"
@@ -22,9 +24,10 @@ func NewRecetaHandler(service service.RecetaService, logger *zap.Logger) *Receta
 	}
 }
 
-// Query params: estado?, q?, page?, size?, sort?
+// Query params: estado?, vigencia? (VIGENTE | POR_VENCER | VENCIDA), q?, page?, size?, sort?
 func (h *RecetaHandler) GetRecetas(c *gin.Context) {
 	estado := c.DefaultQuery("estado", "")
+	vigencia := c.DefaultQuery("vigencia", "")
 	query := c.DefaultQuery("q", "")
 	pageStr := c.DefaultQuery("page", "0")
 	sizeStr := c.DefaultQuery("size", "20")
@@ -44,15 +47,21 @@ func (h *RecetaHandler) GetRecetas(c *gin.Context) {
 		zap.String("endpoint", "/solicitudes/recetas"),
 		zap.String("method", "GET"),
 		zap.String("estado", estado),
+		zap.String("vigencia", vigencia),
 		zap.String("query", query),
 		zap.Int("page", page),
 		zap.Int("size", size),
 		zap.String("sort", sort),
 	)
 
-	response, err := h.service.GetRecetas(estado, query, page, size, sort)
+	response, err := h.service.GetRecetas(estado, vigencia, query, page, size, sort)
 	if err != nil {
 		h.logger.Error("Error al obtener recetas", zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener recetas"})
 		return
 	}
@@ -94,17 +103,48 @@ func (h *RecetaHandler) CreateReceta(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Creando nueva receta",
 		zap.String("endpoint", "/solicitudes/recetas"),
 		zap.String("method", "POST"),
 		zap.Int("afiliadoId", req.AfiliadoID),
-		zap.String("medicamento", req.Medicamento),
+		zap.String("medicamentoCodigo", req.MedicamentoCodigo),
 		zap.String("dosis", req.Dosis),
 	)
 
-	response, err := h.service.CreateReceta(req)
+	response, err := h.service.CreateReceta(req, usuario)
 	if err != nil {
 		h.logger.Error("Error al crear receta", zap.Error(err))
+		var afiliadoErr *service.AfiliadoInexistenteError
+		if errors.As(err, &afiliadoErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": afiliadoErr.Error()})
+			return
+		}
+		var medicamentoErr *service.MedicamentoInexistenteError
+		if errors.As(err, &medicamentoErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": medicamentoErr.Error()})
+			return
+		}
+		var elegibilidadErr *service.AfiliadoNoElegibleError
+		if errors.As(err, &elegibilidadErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": elegibilidadErr.Error(), "motivos": elegibilidadErr.Elegibilidad.Motivos})
+			return
+		}
+		var advertenciasErr *service.RecetaConAdvertenciasError
+		if errors.As(err, &advertenciasErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": advertenciasErr.Error(), "advertencias": advertenciasErr.Advertencias})
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear receta"})
 		return
 	}
@@ -129,22 +169,60 @@ func (h *RecetaHandler) UpdateReceta(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Actualizando receta",
 		zap.String("endpoint", "/solicitudes/recetas/:id"),
 		zap.String("method", "PUT"),
 		zap.Int("id", id),
-		zap.String("medicamento", req.Medicamento),
+		zap.String("medicamentoCodigo", req.MedicamentoCodigo),
 		zap.String("dosis", req.Dosis),
 	)
 
-	err = h.service.UpdateReceta(id, req)
+	advertencias, err := h.service.UpdateReceta(id, req, usuario)
 	if err != nil {
 		h.logger.Error("Error al actualizar receta", zap.Int("id", id), zap.Error(err))
+		if errors.Is(err, service.ErrSolicitudAjena) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "La receta pertenece a otro prestador"})
+			return
+		}
+		var noEditableErr *service.SolicitudNoEditableError
+		if errors.As(err, &noEditableErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":            err.Error(),
+				"estadoActual":     noEditableErr.Estado,
+				"estadosEditables": noEditableErr.Editables,
+			})
+			return
+		}
+		var medicamentoErr *service.MedicamentoInexistenteError
+		if errors.As(err, &medicamentoErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": medicamentoErr.Error()})
+			return
+		}
+		var advertenciasErr *service.RecetaConAdvertenciasError
+		if errors.As(err, &advertenciasErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": advertenciasErr.Error(), "advertencias": advertenciasErr.Advertencias})
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Receta no encontrada"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Receta actualizada exitosamente"})
+	response := gin.H{"message": "Receta actualizada exitosamente"}
+	if len(advertencias) > 0 {
+		response["advertencias"] = advertencias
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *RecetaHandler) CambiarEstadoReceta(c *gin.Context) {
@@ -164,17 +242,37 @@ func (h *RecetaHandler) CambiarEstadoReceta(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Cambiando estado de receta",
 		zap.String("endpoint", "/solicitudes/recetas/:id/estado"),
 		zap.String("method", "PATCH"),
 		zap.Int("id", id),
 		zap.String("nuevoEstado", string(req.NuevoEstado)),
-		zap.String("usuario", req.Usuario),
+		zap.String("usuario", usuario.Username),
 	)
 
-	response, err := h.service.CambiarEstadoReceta(id, req)
+	response, err := h.service.CambiarEstadoReceta(id, req, usuario)
 	if err != nil {
 		h.logger.Error("Error al cambiar estado", zap.Int("id", id), zap.Error(err))
+		var transicionErr *service.TransicionInvalidaError
+		if errors.As(err, &transicionErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":                  err.Error(),
+				"estadoActual":           transicionErr.EstadoActual,
+				"transicionesPermitidas": transicionErr.Permitidas,
+			})
+			return
+		}
+		var permisoErr *service.PermisoDenegadoError
+		if errors.As(err, &permisoErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "rol": permisoErr.Rol})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
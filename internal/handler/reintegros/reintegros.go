@@ -1,7 +1,9 @@
 package reintegros
 
 import (
+	"errors"
 	"net/http"
+	"prestadores-api/internal/middleware"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/service"
 	"strconv"
@@ -22,9 +24,10 @@ func NewReintegroHandler(service service.ReintegroService, logger *zap.Logger) *
 	}
 }
 
-// Query params: estado?, q?, page?, size?, sort?
+// Query params: estado?, especialidad? (código del catálogo), q?, page?, size?, sort?
 func (h *ReintegroHandler) GetReintegros(c *gin.Context) {
 	estado := c.DefaultQuery("estado", "")
+	especialidad := c.DefaultQuery("especialidad", "")
 	query := c.DefaultQuery("q", "")
 	pageStr := c.DefaultQuery("page", "0")
 	sizeStr := c.DefaultQuery("size", "20")
@@ -43,15 +46,21 @@ func (h *ReintegroHandler) GetReintegros(c *gin.Context) {
 		zap.String("endpoint", "/solicitudes/reintegros"),
 		zap.String("method", "GET"),
 		zap.String("estado", estado),
+		zap.String("especialidad", especialidad),
 		zap.String("query", query),
 		zap.Int("page", page),
 		zap.Int("size", size),
 		zap.String("sort", sort),
 	)
 
-	resp, err := h.service.GetReintegros(estado, query, page, size, sort)
+	resp, err := h.service.GetReintegros(estado, especialidad, query, page, size, sort)
 	if err != nil {
 		h.logger.Error("Error al obtener reintegros", zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener reintegros"})
 		return
 	}
@@ -90,18 +99,37 @@ func (h *ReintegroHandler) CreateReintegro(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Creando nuevo reintegro",
 		zap.String("endpoint", "/solicitudes/reintegros"),
 		zap.String("method", "POST"),
 		zap.Int("afiliadoId", req.AfiliadoID),
-		zap.String("prestacion", req.Prestacion),
-		zap.String("metodo", req.Metodo),
-		zap.Float64("monto", req.Monto),
+		zap.String("prestacionCodigo", req.PrestacionCodigo),
+		zap.String("metodo", string(req.Metodo)),
+		zap.Stringer("monto", req.Monto),
 	)
 
-	resp, err := h.service.CreateReintegro(req)
+	resp, err := h.service.CreateReintegro(req, usuario)
 	if err != nil {
 		h.logger.Error("Error al crear reintegro", zap.Error(err))
+		if responderErrorCobertura(c, err) {
+			return
+		}
+		var facturaErr *service.FacturaDuplicadaError
+		if errors.As(err, &facturaErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": facturaErr.Error(), "reintegroId": facturaErr.ReintegroID})
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear reintegro"})
 		return
 	}
@@ -128,13 +156,30 @@ func (h *ReintegroHandler) UpdateReintegro(c *gin.Context) {
 		zap.String("endpoint", "/solicitudes/reintegros/:id"),
 		zap.String("method", "PUT"),
 		zap.Int("id", id),
-		zap.String("prestacion", req.Prestacion),
-		zap.String("metodo", req.Metodo),
-		zap.Float64("monto", req.Monto),
+		zap.String("prestacionCodigo", req.PrestacionCodigo),
+		zap.String("metodo", string(req.Metodo)),
+		zap.Any("monto", req.Monto),
 	)
 
 	if err := h.service.UpdateReintegro(id, req); err != nil {
 		h.logger.Error("Error al actualizar reintegro", zap.Int("id", id), zap.Error(err))
+		var noEditableErr *service.SolicitudNoEditableError
+		if errors.As(err, &noEditableErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":            err.Error(),
+				"estadoActual":     noEditableErr.Estado,
+				"estadosEditables": noEditableErr.Editables,
+			})
+			return
+		}
+		if responderErrorCobertura(c, err) {
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Reintegro no encontrado"})
 		return
 	}
@@ -157,19 +202,65 @@ func (h *ReintegroHandler) CambiarEstadoReintegro(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Cambiando estado de reintegro",
 		zap.String("endpoint", "/solicitudes/reintegros/:id/estado"),
 		zap.String("method", "PATCH"),
 		zap.Int("id", id),
 		zap.String("nuevoEstado", string(req.NuevoEstado)),
-		zap.String("usuario", req.Usuario),
+		zap.String("usuario", usuario.Username),
 	)
 
-	resp, err := h.service.CambiarEstadoReintegro(id, req)
+	resp, err := h.service.CambiarEstadoReintegro(id, req, usuario)
 	if err != nil {
 		h.logger.Error("Error al cambiar estado de reintegro", zap.Int("id", id), zap.Error(err))
+		var transicionErr *service.TransicionInvalidaError
+		if errors.As(err, &transicionErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":                  err.Error(),
+				"estadoActual":           transicionErr.EstadoActual,
+				"transicionesPermitidas": transicionErr.Permitidas,
+			})
+			return
+		}
+		var permisoErr *service.PermisoDenegadoError
+		if errors.As(err, &permisoErr) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "rol": permisoErr.Rol})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, resp)
 }
+
+// responderErrorCobertura responde 422 cuando el afiliado, el código del nomenclador, el plan
+// o la autorización previa no permiten el reintegro. Devuelve false si el error es de otro tipo.
+func responderErrorCobertura(c *gin.Context, err error) bool {
+	var afiliadoErr *service.AfiliadoInexistenteError
+	if errors.As(err, &afiliadoErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": afiliadoErr.Error()})
+		return true
+	}
+	var codigoErr *service.CodigoNomencladorError
+	if errors.As(err, &codigoErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": codigoErr.Error()})
+		return true
+	}
+	var elegibilidadErr *service.AfiliadoNoElegibleError
+	if errors.As(err, &elegibilidadErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": elegibilidadErr.Error(), "motivos": elegibilidadErr.Elegibilidad.Motivos})
+		return true
+	}
+	var autorizacionErr *service.AutorizacionPreviaError
+	if errors.As(err, &autorizacionErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": autorizacionErr.Error()})
+		return true
+	}
+	return false
+}
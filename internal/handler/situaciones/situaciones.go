@@ -1,7 +1,9 @@
 package situaciones
 
 import (
+	"errors"
 	"net/http"
+	"prestadores-api/internal/middleware"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/service"
 	"strconv"
@@ -44,6 +46,10 @@ func (h *SituacionHandler) GetSituaciones(c *gin.Context) {
 	resp, err := h.service.GetSituaciones(afiliadoID, scope)
 	if err != nil {
 		h.logger.Error("Error al obtener situaciones", zap.Error(err))
+		if errors.Is(err, service.ErrAfiliadoNoEncontrado) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Afiliado no encontrado"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener situaciones"})
 		return
 	}
@@ -68,6 +74,12 @@ func (h *SituacionHandler) CreateSituacion(c *gin.Context) {
 	}
 	req.AfiliadoID = afiliadoID
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Creando nueva situación terapéutica",
 		zap.String("endpoint", "/afiliados/:afiliadoId/situaciones"),
 		zap.String("method", "POST"),
@@ -76,9 +88,19 @@ func (h *SituacionHandler) CreateSituacion(c *gin.Context) {
 		zap.String("fechaInicio", req.FechaInicio),
 	)
 
-	resp, err := h.service.CreateSituacion(req)
+	resp, err := h.service.CreateSituacion(req, usuario)
 	if err != nil {
 		h.logger.Error("Error al crear situación", zap.Error(err))
+		var afiliadoErr *service.AfiliadoInexistenteError
+		if errors.As(err, &afiliadoErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": afiliadoErr.Error()})
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": svcErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear situación"})
 		return
 	}
@@ -139,14 +161,21 @@ func (h *SituacionHandler) CambiarEstadoSituacion(c *gin.Context) {
 		return
 	}
 
+	usuario, ok := middleware.UsuarioActual(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+		return
+	}
+
 	h.logger.Info("Cambiando estado de situación",
 		zap.String("endpoint", "/afiliados/:afiliadoId/situaciones/:situacionId/estado"),
 		zap.String("method", "PATCH"),
 		zap.Int("situacionId", situacionID),
 		zap.String("nuevoEstado", string(req.Estado)),
+		zap.String("usuario", usuario.Username),
 	)
 
-	resp, err := h.service.CambiarEstadoSituacion(situacionID, req)
+	resp, err := h.service.CambiarEstadoSituacion(situacionID, req, usuario)
 	if err != nil {
 		h.logger.Error("Error al cambiar estado de situación", zap.Int("situacionId", situacionID), zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
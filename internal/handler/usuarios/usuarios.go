@@ -0,0 +1,58 @@
+package usuarios
+
+import (
+	"errors"
+	"net/http"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UsuarioHandler struct {
+	service service.UsuarioService
+	logger  *zap.Logger
+}
+
+func NewUsuarioHandler(service service.UsuarioService, logger *zap.Logger) *UsuarioHandler {
+	return &UsuarioHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// POST /v1/prestadores/usuarios  → alta de prestadores, auditores y administradores
+func (h *UsuarioHandler) RegistrarUsuario(c *gin.Context) {
+	var req model.RegistrarUsuarioRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Request inválido para registrar usuario", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request inválido", "details": err.Error()})
+		return
+	}
+
+	h.logger.Info("Registrando usuario",
+		zap.String("endpoint", "/usuarios"),
+		zap.String("method", "POST"),
+		zap.String("cuit", req.CUIT),
+	)
+
+	usuario, err := h.service.RegistrarUsuario(req)
+	if err != nil {
+		h.logger.Error("Error al registrar usuario", zap.Error(err))
+		if errors.Is(err, service.ErrUsuarioExistente) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al registrar usuario"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, usuario)
+}
@@ -0,0 +1,119 @@
+package vademecum
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"prestadores-api/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Tamaño máximo del CSV que se acepta al importar (10 MB)
+const maxTamanioCSV = 10 << 20
+
+type VademecumHandler struct {
+	service service.VademecumService
+	logger  *zap.Logger
+}
+
+func NewVademecumHandler(service service.VademecumService, logger *zap.Logger) *VademecumHandler {
+	return &VademecumHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GET /v1/prestadores/vademecum?q=&condicionVenta=&limit=  → busca por código, droga, marca o laboratorio
+func (h *VademecumHandler) BuscarMedicamentos(c *gin.Context) {
+	query := c.DefaultQuery("q", "")
+	condicionVenta := c.DefaultQuery("condicionVenta", "")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+
+	h.logger.Info("Buscando en el vademécum",
+		zap.String("endpoint", "/vademecum"),
+		zap.String("method", "GET"),
+		zap.String("query", query),
+		zap.String("condicionVenta", condicionVenta),
+		zap.Int("limit", limit),
+	)
+
+	resp, err := h.service.BuscarMedicamentos(query, condicionVenta, limit)
+	if err != nil {
+		h.logger.Error("Error al buscar en el vademécum", zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar en el vademécum"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /v1/prestadores/vademecum/:codigo
+func (h *VademecumHandler) GetMedicamento(c *gin.Context) {
+	codigo := c.Param("codigo")
+
+	h.logger.Info("Obteniendo medicamento del vademécum",
+		zap.String("endpoint", "/vademecum/:codigo"),
+		zap.String("method", "GET"),
+		zap.String("codigo", codigo),
+	)
+
+	medicamento, err := h.service.GetMedicamento(codigo)
+	if err != nil {
+		if errors.Is(err, service.ErrMedicamentoNoEncontrado) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Código no encontrado en el vademécum"})
+			return
+		}
+		h.logger.Error("Error al obtener medicamento", zap.String("codigo", codigo), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener medicamento"})
+		return
+	}
+
+	c.JSON(http.StatusOK, medicamento)
+}
+
+// PUT /v1/prestadores/vademecum  (body: CSV) → reemplaza el vademécum completo
+func (h *VademecumHandler) ImportarVademecum(c *gin.Context) {
+	h.logger.Info("Importando vademécum",
+		zap.String("endpoint", "/vademecum"),
+		zap.String("method", "PUT"),
+		zap.String("contentType", c.ContentType()),
+	)
+
+	csv, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxTamanioCSV))
+	if err != nil {
+		h.logger.Warn("No se pudo leer el CSV del vademécum", zap.Error(err))
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "El archivo supera el tamaño máximo"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el archivo"})
+		return
+	}
+
+	resp, err := h.service.ImportarCSV(bytes.NewReader(csv))
+	if err != nil {
+		h.logger.Error("Error al importar vademécum", zap.Error(err))
+		var svcErr *service.ServiceError
+		if errors.As(err, &svcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": svcErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al importar vademécum"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
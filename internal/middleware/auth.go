@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"prestadores-api/internal/auth"
+	"prestadores-api/internal/model"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const usuarioKey = "usuarioAutenticado"
+
+// Auth valida el access token del header Authorization: Bearer <token> y deja
+// el prestador autenticado en el contexto. Sin token válido responde 401.
+func Auth(tokens *auth.TokenManager, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			logger.Warn("Request sin access token", zap.String("path", c.FullPath()))
+			c.Header("WWW-Authenticate", `Bearer realm="prestadores"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un access token (Authorization: Bearer <token>)"})
+			return
+		}
+
+		claims, err := tokens.ValidarAccessToken(strings.TrimSpace(token))
+		if err != nil {
+			logger.Warn("Access token inválido", zap.String("path", c.FullPath()), zap.Error(err))
+			c.Header("WWW-Authenticate", `Bearer realm="prestadores", error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Access token inválido o expirado"})
+			return
+		}
+
+		id, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			logger.Warn("Access token con subject inválido", zap.String("sub", claims.Subject))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Access token inválido o expirado"})
+			return
+		}
+
+		c.Set(usuarioKey, model.UsuarioAutenticado{
+			ID:       id,
+			CUIT:     claims.CUIT,
+			Username: claims.Username,
+			Nombre:   claims.Nombre,
+			Rol:      claims.Rol,
+		})
+		c.Next()
+	}
+}
+
+// UsuarioActual devuelve el prestador autenticado que dejó el middleware Auth
+func UsuarioActual(c *gin.Context) (model.UsuarioAutenticado, bool) {
+	v, ok := c.Get(usuarioKey)
+	if !ok {
+		return model.UsuarioAutenticado{}, false
+	}
+	usuario, ok := v.(model.UsuarioAutenticado)
+	return usuario, ok
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"prestadores-api/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequierePermiso corta con 403 si el rol del usuario autenticado no tiene el permiso.
+// Debe ir después de Auth.
+func RequierePermiso(permiso auth.Permiso, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usuario, ok := UsuarioActual(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Se requiere un usuario autenticado"})
+			return
+		}
+
+		if !auth.TienePermiso(usuario.Rol, permiso) {
+			logger.Warn("Acceso denegado por rol",
+				zap.String("usuario", usuario.Username),
+				zap.String("rol", string(usuario.Rol)),
+				zap.String("permiso", string(permiso)),
+				zap.String("path", c.FullPath()),
+			)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "No tiene permisos para realizar esta operación",
+				"rol":   usuario.Rol,
+			})
+			return
+		}
+		c.Next()
+	}
+}
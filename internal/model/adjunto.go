@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// CategoriaAdjunto indica qué documentación respalda el archivo
+type CategoriaAdjunto string
+
+const (
+	CategoriaFactura     CategoriaAdjunto = "FACTURA"
+	CategoriaTicket      CategoriaAdjunto = "TICKET"
+	CategoriaOrdenMedica CategoriaAdjunto = "ORDEN_MEDICA"
+	CategoriaOtro        CategoriaAdjunto = "OTRO"
+)
+
+// Valida indica si la categoría es una de las conocidas
+func (c CategoriaAdjunto) Valida() bool {
+	switch c {
+	case CategoriaFactura, CategoriaTicket, CategoriaOrdenMedica, CategoriaOtro:
+		return true
+	}
+	return false
+}
+
+// Adjunto es un comprobante subido a una solicitud (autorización o reintegro). El archivo vive en el
+// almacenamiento configurado; acá quedan los metadatos.
+type Adjunto struct {
+	ID            int              `json:"id"`
+	TipoSolicitud TipoSolicitud    `json:"tipoSolicitud"`
+	SolicitudID   int              `json:"solicitudId"`
+	Categoria     CategoriaAdjunto `json:"categoria"`
+	NombreArchivo string           `json:"nombreArchivo"`
+	ContentType   string           `json:"contentType"` // detectado del contenido, no el que declara el cliente
+	Tamanio       int64            `json:"tamanio"`     // en bytes
+	SHA256        string           `json:"sha256"`      // hex del contenido, para verificar la descarga
+	Clave         string           `json:"-"`           // ubicación en el almacenamiento
+	Usuario       string           `json:"usuario"`
+	Rol           Rol              `json:"rol,omitempty"`
+	FechaCarga    time.Time        `json:"fechaCarga"`
+}
+
+// SubirAdjuntoRequest lo arma el handler con el multipart: el archivo y el campo categoria
+type SubirAdjuntoRequest struct {
+	Categoria     CategoriaAdjunto
+	NombreArchivo string // el que manda el cliente; el service se queda con el nombre base
+	Contenido     []byte
+}
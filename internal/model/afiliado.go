@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+// Parentescos con el titular del grupo familiar
+const (
+	ParentescoTitular = "Titular"
+	ParentescoConyuge = "Cónyuge"
+	ParentescoHijo    = "Hijo"
+	ParentescoHija    = "Hija"
+)
+
+// Afiliado es un integrante del padrón. Los integrantes de un grupo familiar
+// apuntan a su titular con TitularID; el titular tiene TitularID nil.
+type Afiliado struct {
+	ID           int        `json:"id"`
+	NroAfiliado  string     `json:"nroAfiliado"`
+	DNI          string     `json:"dni"`
+	Nombre       string     `json:"nombre"`
+	Apellido     string     `json:"apellido"`
+	PlanMedicoID int        `json:"planMedicoId"`
+	PlanMedico   string     `json:"planMedico"` // nombre del plan, lo completa el service desde el catálogo
+	TitularID    *int       `json:"titularId,omitempty"`
+	Parentesco   string     `json:"parentesco"`
+	Email        string     `json:"email"`
+	Telefono     string     `json:"telefono"`
+	Ciudad       string     `json:"ciudad"`
+	Provincia    string     `json:"provincia"`
+	FechaAlta    time.Time  `json:"fechaAlta"`
+	FechaBaja    *time.Time `json:"fechaBaja,omitempty"` // nil mientras siga afiliado
+}
+
+// EstaActivo indica si el afiliado estaba dado de alta y sin baja en la fecha indicada
+func (a Afiliado) EstaActivo(fecha time.Time) bool {
+	if fecha.Before(a.FechaAlta) {
+		return false
+	}
+	return a.FechaBaja == nil || fecha.Before(*a.FechaBaja)
+}
+
+// EsTitular indica si el afiliado encabeza su grupo familiar
+func (a Afiliado) EsTitular() bool {
+	return a.TitularID == nil
+}
+
+// Basico devuelve los datos del afiliado que se copian en las solicitudes
+func (a Afiliado) Basico() AfiliadoBasico {
+	return AfiliadoBasico{ID: a.ID, DNI: a.DNI, Nombre: a.Nombre, Apellido: a.Apellido}
+}
+
+// AfiliadoFiltro agrupa los filtros de GET /afiliados
+type AfiliadoFiltro struct {
+	PlanID  int    // 0 = todos
+	Titular *bool  // nil = todos
+	Query   string // DNI, nro de afiliado, nombre o apellido
+}
+
+// AfiliadoListItem representa una fila de la tabla de afiliados
+type AfiliadoListItem struct {
+	ID           int    `json:"id"`
+	NroAfiliado  string `json:"nroAfiliado"`
+	DNI          string `json:"dni"`
+	Nombre       string `json:"nombre"`
+	Apellido     string `json:"apellido"`
+	PlanMedicoID int    `json:"planMedicoId"`
+	PlanMedico   string `json:"planMedico"`
+	Titular      bool   `json:"titular"`
+}
+
+// PaginatedAfiliadosResponse representa la respuesta paginada de afiliados
+type PaginatedAfiliadosResponse struct {
+	Page  int                `json:"page"`
+	Size  int                `json:"size"`
+	Total int                `json:"total"`
+	Items []AfiliadoListItem `json:"items"`
+}
+
+// IntegranteGrupo es un miembro del grupo familiar visto desde el detalle
+type IntegranteGrupo struct {
+	ID           int    `json:"id"`
+	NroAfiliado  string `json:"nroAfiliado"`
+	DNI          string `json:"dni"`
+	Nombre       string `json:"nombre"`
+	Apellido     string `json:"apellido"`
+	PlanMedicoID int    `json:"planMedicoId"`
+	PlanMedico   string `json:"planMedico"`
+	Parentesco   string `json:"parentesco"`
+}
+
+// GrupoFamiliar es el titular con sus integrantes a cargo
+type GrupoFamiliar struct {
+	Titular     IntegranteGrupo   `json:"titular"`
+	Integrantes []IntegranteGrupo `json:"integrantes"`
+}
+
+// AfiliadoDetalle para GET /afiliados/:afiliadoId
+type AfiliadoDetalle struct {
+	Afiliado
+	Titular       bool          `json:"titular"`
+	GrupoFamiliar GrupoFamiliar `json:"grupoFamiliar"`
+}
@@ -30,43 +30,67 @@ type AfiliadoBasico struct {
 
 // AutorizacionListItem representa un item de la lista de autorizaciones
 type AutorizacionListItem struct {
-	ID                 int                `json:"id"`
-	Tipo               TipoSolicitud      `json:"tipo"`
-	Afiliado           AfiliadoBasico     `json:"afiliado"`
-	Estado             EstadoAutorizacion `json:"estado"`
-	FechaCreacion      time.Time          `json:"fechaCreacion"`
-	FechaActualizacion time.Time          `json:"fechaActualizacion"`
-	Procedimiento      string             `json:"procedimiento"`
-	Especialidad       string             `json:"especialidad"`
+	ID                  int                `json:"id"`
+	Tipo                TipoSolicitud      `json:"tipo"`
+	Afiliado            AfiliadoBasico     `json:"afiliado"`
+	Estado              EstadoAutorizacion `json:"estado"`
+	FechaCreacion       time.Time          `json:"fechaCreacion"`
+	FechaActualizacion  time.Time          `json:"fechaActualizacion"`
+	ProcedimientoCodigo string             `json:"procedimientoCodigo"`
+	Procedimiento       string             `json:"procedimiento"`
+	EspecialidadCodigo  string             `json:"especialidadCodigo"`
+	Especialidad        string             `json:"especialidad"`
 }
 
 // HistorialEstado representa un cambio de estado en el historial
 type HistorialEstado struct {
-	Estado      EstadoAutorizacion `json:"estado"`
-	Usuario     string             `json:"usuario"`
-	FechaCambio time.Time          `json:"fechaCambio"`
-	Motivo      string             `json:"motivo,omitempty"`
+	Estado              EstadoAutorizacion `json:"estado"`
+	Usuario             string             `json:"usuario"`
+	Rol                 Rol                `json:"rol,omitempty"`
+	FechaCambio         time.Time          `json:"fechaCambio"`
+	Motivo              string             `json:"motivo,omitempty"`
+	ProcedimientoCodigo string             `json:"procedimientoCodigo,omitempty"` // en la aprobación de una autorización: lo aprobado
 }
 
 // AutorizacionDetalle representa el detalle completo de una autorización
 type AutorizacionDetalle struct {
-	ID                 int                `json:"id"`
-	Tipo               TipoSolicitud      `json:"tipo"`
-	Estado             EstadoAutorizacion `json:"estado"`
-	FechaCreacion      time.Time          `json:"fechaCreacion"`
-	FechaActualizacion time.Time          `json:"fechaActualizacion"`
-	Afiliado           AfiliadoBasico     `json:"afiliado"`
-	Procedimiento      string             `json:"procedimiento"`
-	Especialidad       string             `json:"especialidad"`
-	Historial          []HistorialEstado  `json:"historial"`
+	ID                  int                `json:"id"`
+	Tipo                TipoSolicitud      `json:"tipo"`
+	Estado              EstadoAutorizacion `json:"estado"`
+	FechaCreacion       time.Time          `json:"fechaCreacion"`
+	FechaActualizacion  time.Time          `json:"fechaActualizacion"`
+	Afiliado            AfiliadoBasico     `json:"afiliado"`
+	Prestador           PrestadorBasico    `json:"prestador"`           // ID 0 en las cargadas antes de registrarlo
+	ProcedimientoCodigo string             `json:"procedimientoCodigo"` // código del nomenclador
+	Procedimiento       string             `json:"procedimiento"`       // descripción del nomenclador
+	EspecialidadCodigo  string             `json:"especialidadCodigo"`  // código del catálogo de especialidades
+	Especialidad        string             `json:"especialidad"`
+	Historial           []HistorialEstado  `json:"historial"`
+	Adjuntos            []Adjunto          `json:"adjuntos"` // los completa el service
+}
+
+// ProcedimientoAprobado devuelve el código que figuraba en la última aprobación del historial
+func (a AutorizacionDetalle) ProcedimientoAprobado() (string, bool) {
+	for i := len(a.Historial) - 1; i >= 0; i-- {
+		if a.Historial[i].Estado == EstadoAprobado {
+			return a.Historial[i].ProcedimientoCodigo, a.Historial[i].ProcedimientoCodigo != ""
+		}
+	}
+	return "", false
 }
 
 // CreateAutorizacionRequest representa el request para crear una autorización
 type CreateAutorizacionRequest struct {
-	AfiliadoID    int                `json:"afiliadoId" binding:"required"`
-	Procedimiento string             `json:"procedimiento" binding:"required"`
-	Especialidad  string             `json:"especialidad" binding:"required"`
-	EstadoInicial EstadoAutorizacion `json:"estadoInicial"`
+	AfiliadoID          int                `json:"afiliadoId" binding:"required"`
+	ProcedimientoCodigo string             `json:"procedimientoCodigo" binding:"required"` // código del nomenclador
+	EspecialidadCodigo  string             `json:"especialidadCodigo,omitempty"`           // opcional: si viene debe ser la del procedimiento
+	EstadoInicial       EstadoAutorizacion `json:"estadoInicial"`
+	Procedimiento       string             `json:"-"` // descripción y especialidad las completa el service desde el nomenclador
+	Especialidad        string             `json:"-"`
+	Usuario             string             `json:"-"` // lo completa el service con el prestador autenticado
+	Rol                 Rol                `json:"-"`
+	Afiliado            AfiliadoBasico     `json:"-"` // snapshot del padrón, lo completa el service
+	Prestador           PrestadorBasico    `json:"-"` // el prestador autenticado, lo completa el service
 }
 
 // CreateAutorizacionResponse representa la respuesta al crear una autorización
@@ -79,15 +103,18 @@ type CreateAutorizacionResponse struct {
 
 // UpdateAutorizacionRequest representa el request para actualizar datos de una autorización
 type UpdateAutorizacionRequest struct {
-	Procedimiento string `json:"procedimiento,omitempty"`
-	Especialidad  string `json:"especialidad,omitempty"`
+	ProcedimientoCodigo string `json:"procedimientoCodigo,omitempty"`
+	EspecialidadCodigo  string `json:"especialidadCodigo,omitempty"` // opcional: si viene debe ser la del procedimiento
+	Procedimiento       string `json:"-"`                            // las completa el service desde el nomenclador
+	Especialidad        string `json:"-"`
 }
 
 // CambioEstadoRequest representa el request para cambiar el estado de una autorización
 type CambioEstadoRequest struct {
 	NuevoEstado EstadoAutorizacion `json:"nuevoEstado" binding:"required"`
 	Motivo      string             `json:"motivo,omitempty"`
-	Usuario     string             `json:"usuario" binding:"required"`
+	Usuario     string             `json:"-"` // lo completa el service con el prestador autenticado
+	Rol         Rol                `json:"-"`
 }
 
 // CambioEstadoResponse representa la respuesta al cambiar el estado
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// MetodoReintegro indica cómo se le paga el reintegro al afiliado
+type MetodoReintegro string
+
+const (
+	MetodoEfectivo      MetodoReintegro = "EFECTIVO"      // se cobra en una sucursal
+	MetodoTransferencia MetodoReintegro = "TRANSFERENCIA" // a una cuenta de cobro del afiliado
+)
+
+// Valido indica si el método es EFECTIVO o TRANSFERENCIA
+func (m MetodoReintegro) Valido() bool {
+	return m == MetodoEfectivo || m == MetodoTransferencia
+}
+
+// TipoCuenta distingue una cuenta bancaria (CBU) de una billetera virtual (CVU)
+type TipoCuenta string
+
+const (
+	CuentaCBU TipoCuenta = "CBU"
+	CuentaCVU TipoCuenta = "CVU"
+)
+
+// CuentaCobro es una cuenta donde el afiliado recibe las transferencias de sus reintegros. Las
+// bajas son lógicas para que los reintegros que la usaron sigan mostrando a dónde se pagó.
+type CuentaCobro struct {
+	ID            int        `json:"id"`
+	AfiliadoID    int        `json:"afiliadoId"`
+	Tipo          TipoCuenta `json:"tipo"`            // se deduce del número
+	CBU           string     `json:"cbu"`             // 22 dígitos, sea CBU o CVU
+	Alias         string     `json:"alias,omitempty"` // en minúsculas
+	TitularCUIT   string     `json:"titularCuit"`     // sin guiones
+	TitularNombre string     `json:"titularNombre"`
+	Usuario       string     `json:"usuario"` // quién la registró
+	FechaAlta     time.Time  `json:"fechaAlta"`
+	FechaBaja     *time.Time `json:"fechaBaja,omitempty"`
+}
+
+// Activa indica si la cuenta puede elegirse para nuevos reintegros
+func (c CuentaCobro) Activa() bool {
+	return c.FechaBaja == nil
+}
+
+// CreateCuentaCobroRequest para POST /afiliados/:afiliadoId/cuentas-cobro
+type CreateCuentaCobroRequest struct {
+	CBU           string `json:"cbu" binding:"required"` // CBU o CVU, se aceptan espacios y guiones
+	Alias         string `json:"alias,omitempty"`
+	TitularCUIT   string `json:"titularCuit" binding:"required"`
+	TitularNombre string `json:"titularNombre" binding:"required"`
+}
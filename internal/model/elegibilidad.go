@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// CodigoInelegibilidad identifica por qué un afiliado no puede recibir una prestación
+type CodigoInelegibilidad string
+
+const (
+	InelegibleAfiliadoInactivo     CodigoInelegibilidad = "AFILIADO_INACTIVO"
+	InelegiblePlanDesconocido      CodigoInelegibilidad = "PLAN_DESCONOCIDO"
+	InelegiblePlanNoVigente        CodigoInelegibilidad = "PLAN_NO_VIGENTE"
+	InelegiblePrestacionNoCubierta CodigoInelegibilidad = "PRESTACION_NO_CUBIERTA"
+	InelegibleCarencia             CodigoInelegibilidad = "CARENCIA"
+)
+
+// MotivoInelegibilidad es cada una de las razones por las que no se cubre la prestación
+type MotivoInelegibilidad struct {
+	Codigo  CodigoInelegibilidad `json:"codigo"`
+	Detalle string               `json:"detalle"`
+}
+
+// Elegibilidad para GET /afiliados/:afiliadoId/elegibilidad
+type Elegibilidad struct {
+	AfiliadoID    int                    `json:"afiliadoId"`
+	Prestacion    string                 `json:"prestacion"`
+	PlanMedicoID  int                    `json:"planMedicoId"`
+	PlanMedico    string                 `json:"planMedico,omitempty"`
+	Elegible      bool                   `json:"elegible"`
+	Motivos       []MotivoInelegibilidad `json:"motivos"`                 // vacío si es elegible
+	Cobertura     *CoberturaPrestacion   `json:"cobertura,omitempty"`     // reglas del plan para la prestación, si la cubre
+	CarenciaHasta *time.Time             `json:"carenciaHasta,omitempty"` // solo si está en carencia
+	FechaConsulta time.Time              `json:"fechaConsulta"`
+}
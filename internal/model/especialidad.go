@@ -0,0 +1,8 @@
+package model
+
+// Especialidad médica del catálogo. Las solicitudes y los turnos la referencian por código,
+// que no cambia; el nombre es el que se muestra y el que usan las coberturas de los planes.
+type Especialidad struct {
+	Codigo string `json:"codigo"`
+	Nombre string `json:"nombre"`
+}
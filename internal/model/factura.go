@@ -0,0 +1,34 @@
+package model
+
+import "fmt"
+
+// TipoFactura es la letra del comprobante AFIP
+type TipoFactura string
+
+const (
+	FacturaA TipoFactura = "A"
+	FacturaB TipoFactura = "B"
+	FacturaC TipoFactura = "C"
+)
+
+// Valida indica si el tipo es A, B o C
+func (t TipoFactura) Valida() bool {
+	return t == FacturaA || t == FacturaB || t == FacturaC
+}
+
+// Factura es el comprobante fiscal que respalda un reintegro. Una factura (emisor, tipo, punto
+// de venta y número) solo puede usarse en un reintegro que no esté RECHAZADO.
+type Factura struct {
+	Tipo       TipoFactura `json:"tipo" binding:"required"`
+	PuntoVenta int         `json:"puntoVenta" binding:"required"` // 1 a 99999
+	Numero     int         `json:"numero" binding:"required"`     // 1 a 99999999
+	CUITEmisor string      `json:"cuitEmisor" binding:"required"` // se guarda normalizado, sin guiones
+	Fecha      string      `json:"fecha" binding:"required"`      // yyyy-mm-dd
+	CAE        string      `json:"cae" binding:"required"`        // 14 dígitos
+	Importe    Monto       `json:"importe" binding:"required"`
+}
+
+// Comprobante devuelve la forma impresa del número: "B 00003-00012345"
+func (f Factura) Comprobante() string {
+	return fmt.Sprintf("%s %05d-%08d", f.Tipo, f.PuntoVenta, f.Numero)
+}
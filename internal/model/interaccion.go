@@ -0,0 +1,37 @@
+package model
+
+// SeveridadInteraccion clasifica una interacción entre drogas
+type SeveridadInteraccion string
+
+const (
+	SeveridadLeve     SeveridadInteraccion = "LEVE"
+	SeveridadModerada SeveridadInteraccion = "MODERADA"
+	SeveridadGrave    SeveridadInteraccion = "GRAVE"
+)
+
+// InteraccionMedicamentosa es un par de drogas genéricas que no conviene prescribir juntas
+type InteraccionMedicamentosa struct {
+	DrogaA      string               `json:"drogaA"`
+	DrogaB      string               `json:"drogaB"`
+	Severidad   SeveridadInteraccion `json:"severidad"`
+	Descripcion string               `json:"descripcion"`
+}
+
+// TipoAdvertenciaReceta indica qué se detectó contra las recetas activas del afiliado
+type TipoAdvertenciaReceta string
+
+const (
+	AdvertenciaDuplicado   TipoAdvertenciaReceta = "DUPLICADO"   // misma droga genérica
+	AdvertenciaInteraccion TipoAdvertenciaReceta = "INTERACCION" // par de drogas configurado
+)
+
+// AdvertenciaReceta es un problema de una receta nueva respecto de otra receta activa del afiliado
+type AdvertenciaReceta struct {
+	Tipo          TipoAdvertenciaReceta `json:"tipo"`
+	Severidad     SeveridadInteraccion  `json:"severidad,omitempty"` // solo interacciones
+	Bloqueante    bool                  `json:"bloqueante"`          // sin forzar la receta no se crea
+	RecetaID      int                   `json:"recetaId"`
+	DrogaGenerica string                `json:"drogaGenerica"`
+	Medicamento   string                `json:"medicamento"`
+	Mensaje       string                `json:"mensaje"`
+}
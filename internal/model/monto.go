@@ -0,0 +1,91 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Moneda es el código ISO 4217 de los importes. Por ahora los reintegros son solo en pesos.
+type Moneda string
+
+const MonedaARS Moneda = "ARS"
+
+// Monto es un importe exacto en centavos. En JSON se escribe como string decimal con dos
+// decimales ("40000.50") y se lee como string o número sin pasar por float64.
+type Monto int64
+
+// montoMaximoDigitos acota la parte entera para que el importe entre en int64 con margen
+const montoMaximoDigitos = 13
+
+var reMonto = regexp.MustCompile(`^(-?)(\d+)(?:\.(\d{1,2}))?$`)
+
+// MontoDesdePesos convierte un importe en pesos expresado como float (los valores del nomenclador),
+// redondeando al centavo
+func MontoDesdePesos(pesos float64) Monto {
+	return Monto(math.Round(pesos * 100))
+}
+
+// ParseMonto interpreta "1234", "1234.5" o "-1234.56". Rechaza más de dos decimales,
+// separadores de miles y notación exponencial.
+func ParseMonto(s string) (Monto, error) {
+	m := reMonto.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("monto inválido %q: se espera un decimal con hasta dos decimales, p. ej. \"1234.50\"", s)
+	}
+	entero := strings.TrimLeft(m[2], "0")
+	if len(entero) > montoMaximoDigitos {
+		return 0, fmt.Errorf("monto inválido %q: demasiado grande", s)
+	}
+
+	pesos, _ := strconv.ParseInt("0"+entero, 10, 64)
+	centavos, _ := strconv.ParseInt((m[3] + "00")[:2], 10, 64)
+	total := Monto(pesos*100 + centavos)
+	if m[1] == "-" {
+		total = -total
+	}
+	return total, nil
+}
+
+// String devuelve el importe con dos decimales y punto como separador ("40000.50")
+func (m Monto) String() string {
+	signo := ""
+	c := int64(m)
+	if c < 0 {
+		signo, c = "-", -c
+	}
+	return fmt.Sprintf("%s%d.%02d", signo, c/100, c%100)
+}
+
+// Porcentaje devuelve el p% del importe, redondeando al centavo (la mitad se aleja de cero)
+func (m Monto) Porcentaje(p int) Monto {
+	v := int64(m) * int64(p)
+	if v < 0 {
+		return Monto((v - 50) / 100)
+	}
+	return Monto((v + 50) / 100)
+}
+
+func (m Monto) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *Monto) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := ParseMonto(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMonto(t *testing.T) {
+	casos := []struct {
+		valor   string
+		want    Monto
+		wantErr bool
+	}{
+		{"1234", 123400, false},
+		{"1234.5", 123450, false},
+		{"1234.56", 123456, false},
+		{"0.01", 1, false},
+		{"-1234.56", -123456, false},
+		{"-0.5", -50, false},
+		{"  40000.50 ", 4000050, false},
+		{"00012", 1200, false},
+		{"9999999999999.99", 999999999999999, false},
+		{"", 0, true},
+		{"12.345", 0, true},
+		{"1,234.56", 0, true},
+		{"1.234,56", 0, true},
+		{"1e3", 0, true},
+		{".5", 0, true},
+		{"12.", 0, true},
+		{"+12", 0, true},
+		{"abc", 0, true},
+		{"10000000000000", 0, true},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.valor, func(t *testing.T) {
+			got, err := ParseMonto(tc.valor)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseMonto(%q) error = %v, wantErr %v", tc.valor, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseMonto(%q) = %d, se esperaba %d", tc.valor, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMontoString(t *testing.T) {
+	casos := []struct {
+		monto Monto
+		want  string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{4000050, "40000.50"},
+		{-123456, "-1234.56"},
+		{-7, "-0.07"},
+	}
+
+	for _, tc := range casos {
+		if got := tc.monto.String(); got != tc.want {
+			t.Errorf("Monto(%d).String() = %q, se esperaba %q", int64(tc.monto), got, tc.want)
+		}
+	}
+}
+
+func TestMontoPorcentaje(t *testing.T) {
+	casos := []struct {
+		monto Monto
+		p     int
+		want  Monto
+	}{
+		{10000, 80, 8000},
+		{10000, 0, 0},
+		{10000, 100, 10000},
+		{1, 50, 1},     // 0.5 centavos se redondea hacia arriba
+		{-1, 50, -1},   // y en negativos se aleja de cero
+		{333, 33, 110}, // 109.89
+		{12345, 70, 8642},
+	}
+
+	for _, tc := range casos {
+		if got := tc.monto.Porcentaje(tc.p); got != tc.want {
+			t.Errorf("Monto(%d).Porcentaje(%d) = %d, se esperaba %d", int64(tc.monto), tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestMontoDesdePesos(t *testing.T) {
+	casos := []struct {
+		pesos float64
+		want  Monto
+	}{
+		{150000, 15000000},
+		{0.1 + 0.2, 30},
+		{19.99, 1999}, // 1998.9999... en float64
+		{-2.5, -250},
+	}
+
+	for _, tc := range casos {
+		if got := MontoDesdePesos(tc.pesos); got != tc.want {
+			t.Errorf("MontoDesdePesos(%v) = %d, se esperaba %d", tc.pesos, got, tc.want)
+		}
+	}
+}
+
+func TestMontoJSON(t *testing.T) {
+	casos := []struct {
+		json    string
+		want    Monto
+		wantErr bool
+	}{
+		{`"40000.50"`, 4000050, false},
+		{`40000.5`, 4000050, false},
+		{`1234`, 123400, false},
+		{`"1e3"`, 0, true},
+		{`1e3`, 0, true},
+		{`0.001`, 0, true},
+		{`true`, 0, true},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.json, func(t *testing.T) {
+			var got Monto
+			err := json.Unmarshal([]byte(tc.json), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tc.json, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Unmarshal(%s) = %d, se esperaba %d", tc.json, got, tc.want)
+			}
+		})
+	}
+
+	t.Run("null no modifica el valor", func(t *testing.T) {
+		got := Monto(100)
+		if err := json.Unmarshal([]byte(`null`), &got); err != nil || got != 100 {
+			t.Errorf("Unmarshal(null) = %d, %v; se esperaba 100 sin error", got, err)
+		}
+	})
+
+	t.Run("se escribe como string con dos decimales", func(t *testing.T) {
+		b, err := json.Marshal(struct{ Monto Monto }{4000050})
+		if err != nil || string(b) != `{"Monto":"40000.50"}` {
+			t.Errorf("Marshal = %s, %v", b, err)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package model
+
+// PrestacionNomenclador es una prestación del nomenclador: las solicitudes la referencian
+// por código y guardan la descripción como etiqueta legible
+type PrestacionNomenclador struct {
+	Codigo             string  `json:"codigo"`
+	Descripcion        string  `json:"descripcion"`
+	EspecialidadCodigo string  `json:"especialidadCodigo"`
+	Especialidad       string  `json:"especialidad"` // nombre del catálogo, coincide con la prestación de la cobertura del plan
+	ValorUnitario      float64 `json:"valorUnitario"`
+}
+
+// NomencladorResponse representa el resultado de una búsqueda en el nomenclador
+type NomencladorResponse struct {
+	Total int                     `json:"total"`
+	Items []PrestacionNomenclador `json:"items"`
+}
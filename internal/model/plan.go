@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// PrestacionMedicamentos es la prestación contra la que se evalúan las recetas
+const PrestacionMedicamentos = "Medicamentos"
+
+// CoberturaPrestacion son las reglas de un plan para una prestación
+type CoberturaPrestacion struct {
+	Prestacion           string `json:"prestacion" binding:"required"`
+	PorcentajeCobertura  int    `json:"porcentajeCobertura"`  // 0 a 100, sobre el monto presentado
+	TopeAnual            Monto  `json:"topeAnual"`            // máximo reconocido por afiliado y año; 0 = sin tope
+	Copago               Monto  `json:"copago"`               // monto fijo a cargo del afiliado por prestación
+	RequiereAutorizacion bool   `json:"requiereAutorizacion"` // el reintegro exige una autorización APROBADA
+	CarenciaDias         int    `json:"carenciaDias"`         // días desde el alta hasta poder usarla
+}
+
+// PlanMedico es un plan del catálogo con su vigencia y coberturas
+type PlanMedico struct {
+	ID            int                   `json:"id"`
+	Nombre        string                `json:"nombre"`
+	VigenciaDesde time.Time             `json:"vigenciaDesde"`
+	VigenciaHasta *time.Time            `json:"vigenciaHasta,omitempty"` // nil = sin fecha de fin
+	Coberturas    []CoberturaPrestacion `json:"coberturas"`
+}
+
+// VigenteEn indica si el plan está vigente en la fecha indicada
+func (p PlanMedico) VigenteEn(fecha time.Time) bool {
+	if fecha.Before(p.VigenciaDesde) {
+		return false
+	}
+	return p.VigenciaHasta == nil || fecha.Before(*p.VigenciaHasta)
+}
+
+// PlanMedicoRequest para POST /planes y PUT /planes/:planId (reemplaza el plan completo)
+type PlanMedicoRequest struct {
+	Nombre        string                `json:"nombre" binding:"required"`
+	VigenciaDesde time.Time             `json:"vigenciaDesde" binding:"required"`
+	VigenciaHasta *time.Time            `json:"vigenciaHasta,omitempty"`
+	Coberturas    []CoberturaPrestacion `json:"coberturas" binding:"required,dive"`
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type UnidadDosis string
+
+const (
+	UnidadComprimido UnidadDosis = "COMPRIMIDO"
+	UnidadCapsula    UnidadDosis = "CAPSULA"
+	UnidadSobre      UnidadDosis = "SOBRE"
+	UnidadML         UnidadDosis = "ML"
+	UnidadGota       UnidadDosis = "GOTA"
+	UnidadDisparo    UnidadDosis = "DISPARO"
+	UnidadAplicacion UnidadDosis = "APLICACION"
+	UnidadAmpolla    UnidadDosis = "AMPOLLA"
+)
+
+type ViaAdministracion string
+
+const (
+	ViaOral          ViaAdministracion = "ORAL"
+	ViaSublingual    ViaAdministracion = "SUBLINGUAL"
+	ViaTopica        ViaAdministracion = "TOPICA"
+	ViaOftalmica     ViaAdministracion = "OFTALMICA"
+	ViaOtica         ViaAdministracion = "OTICA"
+	ViaNasal         ViaAdministracion = "NASAL"
+	ViaInhalatoria   ViaAdministracion = "INHALATORIA"
+	ViaRectal        ViaAdministracion = "RECTAL"
+	ViaVaginal       ViaAdministracion = "VAGINAL"
+	ViaIntramuscular ViaAdministracion = "INTRAMUSCULAR"
+	ViaSubcutanea    ViaAdministracion = "SUBCUTANEA"
+	ViaIntravenosa   ViaAdministracion = "INTRAVENOSA"
+)
+
+// Posologia es la indicación estructurada de una receta. CantidadTotal y FechaFin los calcula
+// el servidor y se ignoran si vienen en el request.
+type Posologia struct {
+	Cantidad        float64           `json:"cantidad"` // dosis por toma
+	Unidad          UnidadDosis       `json:"unidad"`
+	FrecuenciaHoras int               `json:"frecuenciaHoras"` // horas entre tomas
+	DuracionDias    int               `json:"duracionDias"`    // 0 = tratamiento sin fin definido
+	Via             ViaAdministracion `json:"via,omitempty"`
+	Envases         int               `json:"envases"`
+	CantidadTotal   float64           `json:"cantidadTotal,omitempty"` // unidades para todo el tratamiento
+	FechaFin        *time.Time        `json:"fechaFin,omitempty"`      // fin del tratamiento contado desde la fecha de la receta
+}
+
+var nombresUnidad = map[UnidadDosis][2]string{
+	UnidadComprimido: {"comprimido", "comprimidos"},
+	UnidadCapsula:    {"cápsula", "cápsulas"},
+	UnidadSobre:      {"sobre", "sobres"},
+	UnidadML:         {"ml", "ml"},
+	UnidadGota:       {"gota", "gotas"},
+	UnidadDisparo:    {"disparo", "disparos"},
+	UnidadAplicacion: {"aplicación", "aplicaciones"},
+	UnidadAmpolla:    {"ampolla", "ampollas"},
+}
+
+var nombresVia = map[ViaAdministracion]string{
+	ViaOral:          "oral",
+	ViaSublingual:    "sublingual",
+	ViaTopica:        "tópica",
+	ViaOftalmica:     "oftálmica",
+	ViaOtica:         "ótica",
+	ViaNasal:         "nasal",
+	ViaInhalatoria:   "inhalatoria",
+	ViaRectal:        "rectal",
+	ViaVaginal:       "vaginal",
+	ViaIntramuscular: "intramuscular",
+	ViaSubcutanea:    "subcutánea",
+	ViaIntravenosa:   "intravenosa",
+}
+
+// Valida indica si la unidad es una de las admitidas
+func (u UnidadDosis) Valida() bool {
+	_, ok := nombresUnidad[u]
+	return ok
+}
+
+// Valida indica si la vía es una de las admitidas
+func (v ViaAdministracion) Valida() bool {
+	_, ok := nombresVia[v]
+	return ok
+}
+
+// Texto arma la dosis en texto libre a partir de la posología, p. ej. "1 cápsula c/8h x 7d vía oral"
+func (p Posologia) Texto() string {
+	cantidad := strconv.FormatFloat(p.Cantidad, 'f', -1, 64)
+	unidad := strings.ToLower(string(p.Unidad))
+	if nombres, ok := nombresUnidad[p.Unidad]; ok {
+		unidad = nombres[1]
+		if p.Cantidad <= 1 {
+			unidad = nombres[0]
+		}
+	}
+
+	texto := fmt.Sprintf("%s %s c/%dh", cantidad, unidad, p.FrecuenciaHoras)
+	if p.DuracionDias > 0 {
+		texto += fmt.Sprintf(" x %dd", p.DuracionDias)
+	}
+	if p.Via != "" {
+		texto += " vía " + nombresVia[p.Via]
+	}
+	return texto
+}
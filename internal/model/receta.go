@@ -10,26 +10,52 @@ const (
 	RecetaEstadoAprobado   EstadoReceta = "APROBADO"
 	RecetaEstadoRechazado  EstadoReceta = "RECHAZADO"
 	RecetaEstadoObservado  EstadoReceta = "OBSERVADO"
+
+	// Ciclo de vida de la receta aprobada: los pasa la farmacia al dispensar y el vencimiento automático
+	RecetaEstadoDispensada             EstadoReceta = "DISPENSADA"
+	RecetaEstadoParcialmenteDispensada EstadoReceta = "PARCIALMENTE_DISPENSADA"
+	RecetaEstadoVencida                EstadoReceta = "VENCIDA"
 )
 
+// VigenciaReceta es el filtro ?vigencia= del listado
+type VigenciaReceta string
+
+const (
+	VigenciaVigente   VigenciaReceta = "VIGENTE"    // aprobada o parcialmente dispensada y sin vencer
+	VigenciaPorVencer VigenciaReceta = "POR_VENCER" // vigente y vence en los próximos días
+	VigenciaVencida   VigenciaReceta = "VENCIDA"    // VENCIDA o con la fecha de vencimiento ya pasada
+)
+
+// DiasRecetaPorVencer es la ventana del filtro POR_VENCER
+const DiasRecetaPorVencer = 7
+
+// Valida indica si es uno de los filtros de vigencia conocidos
+func (v VigenciaReceta) Valida() bool {
+	return v == VigenciaVigente || v == VigenciaPorVencer || v == VigenciaVencida
+}
+
 const (
 	TipoReceta TipoSolicitud = "RECETA"
 )
 
 type RecetaListItem struct {
-	ID                 int          `json:"id"`
-	Tipo               TipoSolicitud `json:"tipo"`
+	ID                 int            `json:"id"`
+	Tipo               TipoSolicitud  `json:"tipo"`
 	Afiliado           AfiliadoBasico `json:"afiliado"`
-	Estado             EstadoReceta `json:"estado"`
-	FechaCreacion      time.Time    `json:"fechaCreacion"`
-	FechaActualizacion time.Time    `json:"fechaActualizacion"`
-	Medicamento        string       `json:"medicamento"`
-	Dosis              string       `json:"dosis"`
+	Estado             EstadoReceta   `json:"estado"`
+	FechaCreacion      time.Time      `json:"fechaCreacion"`
+	FechaActualizacion time.Time      `json:"fechaActualizacion"`
+	FechaVencimiento   *time.Time     `json:"fechaVencimiento,omitempty"`
+	MedicamentoCodigo  string         `json:"medicamentoCodigo"`
+	DrogaGenerica      string         `json:"drogaGenerica"`
+	Medicamento        string         `json:"medicamento"`
+	Dosis              string         `json:"dosis"`
 }
 
 type HistorialEstadoReceta struct {
 	Estado      EstadoReceta `json:"estado"`
 	Usuario     string       `json:"usuario"`
+	Rol         Rol          `json:"rol,omitempty"`
 	FechaCambio time.Time    `json:"fechaCambio"`
 	Motivo      string       `json:"motivo,omitempty"`
 }
@@ -41,34 +67,85 @@ type RecetaDetalle struct {
 	FechaCreacion      time.Time               `json:"fechaCreacion"`
 	FechaActualizacion time.Time               `json:"fechaActualizacion"`
 	Afiliado           AfiliadoBasico          `json:"afiliado"`
-	Medicamento        string                  `json:"medicamento"`
+	Prestador          PrestadorBasico         `json:"prestador"`
+	MedicamentoCodigo  string                  `json:"medicamentoCodigo"` // código del vademécum
+	DrogaGenerica      string                  `json:"drogaGenerica"`
+	Medicamento        string                  `json:"medicamento"`             // prescripción por nombre genérico: droga, concentración y presentación
+	MarcaSugerida      string                  `json:"marcaSugerida,omitempty"` // marca comercial, solo si el prescriptor la sugiere
 	Dosis              string                  `json:"dosis"`
+	Posologia          *Posologia              `json:"posologia,omitempty"`          // nil si la dosis es texto libre que no se pudo interpretar
+	CodigoVerificacion string                  `json:"codigoVerificacion,omitempty"` // receta electrónica, se asigna al aprobarla
+	FechaVencimiento   *time.Time              `json:"fechaVencimiento,omitempty"`   // fin de la vigencia, se fija al aprobarla
+	Dispensas          []DispensaReceta        `json:"dispensas,omitempty"`
 	Historial          []HistorialEstadoReceta `json:"historial"`
 }
 
+// Vigente indica si la receta todavía se puede dispensar a la fecha: aprobada o parcialmente
+// dispensada y sin vencer. Las aprobadas antes de la vigencia no tienen vencimiento.
+func (r *RecetaDetalle) Vigente(ahora time.Time) bool {
+	if r.Estado != RecetaEstadoAprobado && r.Estado != RecetaEstadoParcialmenteDispensada {
+		return false
+	}
+	return r.FechaVencimiento == nil || r.FechaVencimiento.After(ahora)
+}
+
+// EnvasesDispensados suma las entregas registradas
+func (r *RecetaDetalle) EnvasesDispensados() int {
+	total := 0
+	for _, d := range r.Dispensas {
+		total += d.Envases
+	}
+	return total
+}
+
 type CreateRecetaRequest struct {
-	AfiliadoID    int          `json:"afiliadoId" binding:"required"`
-	Medicamento   string       `json:"medicamento" binding:"required"`
-	Dosis         string       `json:"dosis" binding:"required"`
-	EstadoInicial EstadoReceta `json:"estadoInicial"`
+	AfiliadoID        int             `json:"afiliadoId" binding:"required"`
+	MedicamentoCodigo string          `json:"medicamentoCodigo" binding:"required"` // código del vademécum
+	SugerirMarca      bool            `json:"sugerirMarca"`                         // agrega la marca del producto como sugerencia
+	DrogaGenerica     string          `json:"-"`                                    // droga, nombre genérico y marca los completa el service desde el vademécum
+	Medicamento       string          `json:"-"`
+	MarcaSugerida     string          `json:"-"`
+	Dosis             string          `json:"dosis"`     // texto libre; se ignora si viene posologia
+	Posologia         *Posologia      `json:"posologia"` // se requiere posologia o dosis
+	EstadoInicial     EstadoReceta    `json:"estadoInicial"`
+	Forzar            bool            `json:"forzar"`        // crea la receta aunque tenga advertencias bloqueantes
+	Justificacion     string          `json:"justificacion"` // obligatoria al forzar
+	MotivoAlta        string          `json:"-"`             // motivo del historial inicial, registra el forzado
+	Usuario           string          `json:"-"`             // lo completa el service con el prestador autenticado
+	Rol               Rol             `json:"-"`
+	Afiliado          AfiliadoBasico  `json:"-"` // snapshot del padrón, lo completa el service
+	Prestador         PrestadorBasico `json:"-"` // el prestador autenticado, lo completa el service
 }
 
 type CreateRecetaResponse struct {
-	ID            int           `json:"id"`
-	Tipo          TipoSolicitud `json:"tipo"`
-	Estado        EstadoReceta  `json:"estado"`
-	FechaCreacion time.Time     `json:"fechaCreacion"`
+	ID            int                 `json:"id"`
+	Tipo          TipoSolicitud       `json:"tipo"`
+	Estado        EstadoReceta        `json:"estado"`
+	FechaCreacion time.Time           `json:"fechaCreacion"`
+	Advertencias  []AdvertenciaReceta `json:"advertencias,omitempty"` // duplicados e interacciones con recetas activas
 }
 
 type UpdateRecetaRequest struct {
-	Medicamento string `json:"medicamento,omitempty"`
-	Dosis       string `json:"dosis,omitempty"`
+	MedicamentoCodigo string     `json:"medicamentoCodigo,omitempty"`
+	SugerirMarca      bool       `json:"sugerirMarca,omitempty"` // solo se considera si cambia el medicamento
+	DrogaGenerica     string     `json:"-"`
+	Medicamento       string     `json:"-"`
+	MarcaSugerida     string     `json:"-"`
+	Dosis             string     `json:"dosis,omitempty"`
+	Posologia         *Posologia `json:"posologia,omitempty"`     // reemplaza la dosis completa
+	Forzar            bool       `json:"forzar,omitempty"`        // cambia el medicamento aunque tenga advertencias bloqueantes
+	Justificacion     string     `json:"justificacion,omitempty"` // obligatoria al forzar
+	MotivoForzado     string     `json:"-"`                       // si se forzó, queda en el historial
+	Usuario           string     `json:"-"`                       // lo completa el service con el prestador autenticado
+	Rol               Rol        `json:"-"`
 }
 
 type CambioEstadoRecetaRequest struct {
-	NuevoEstado EstadoReceta `json:"nuevoEstado" binding:"required"`
-	Motivo      string       `json:"motivo,omitempty"`
-	Usuario     string       `json:"usuario" binding:"required"`
+	NuevoEstado      EstadoReceta `json:"nuevoEstado" binding:"required"`
+	Motivo           string       `json:"motivo,omitempty"`
+	Usuario          string       `json:"-"` // lo completa el service con el prestador autenticado
+	Rol              Rol          `json:"-"`
+	FechaVencimiento *time.Time   `json:"-"` // al aprobarla, según la vigencia del medicamento
 }
 
 type CambioEstadoRecetaResponse struct {
@@ -76,6 +153,8 @@ type CambioEstadoRecetaResponse struct {
 	Tipo               TipoSolicitud `json:"tipo"`
 	Estado             EstadoReceta  `json:"estado"`
 	FechaActualizacion time.Time     `json:"fechaActualizacion"`
+	CodigoVerificacion string        `json:"codigoVerificacion,omitempty"` // al aprobarla
+	FechaVencimiento   *time.Time    `json:"fechaVencimiento,omitempty"`
 }
 
 type PaginatedRecetasResponse struct {
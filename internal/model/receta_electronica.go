@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// PrestadorBasico es el prestador que carga la solicitud (en las recetas, el prescriptor), copiado del usuario que la crea
+type PrestadorBasico struct {
+	ID     int    `json:"id"`
+	CUIT   string `json:"cuit"`
+	Nombre string `json:"nombre"`
+}
+
+// DispensaReceta registra una entrega del medicamento en farmacia. Una receta con varios envases
+// se puede dispensar en más de una vez.
+type DispensaReceta struct {
+	Fecha        time.Time `json:"fecha"`
+	FarmaciaCUIT string    `json:"farmaciaCuit"`
+	Envases      int       `json:"envases"`
+}
+
+// VerificacionReceta es lo que ve una farmacia al consultar el código de una receta electrónica.
+// Del afiliado se muestra el DNI enmascarado.
+type VerificacionReceta struct {
+	Codigo             string           `json:"codigo"`
+	Valida             bool             `json:"valida"`
+	Motivo             string           `json:"motivo,omitempty"` // por qué no se puede dispensar
+	Estado             EstadoReceta     `json:"estado"`
+	FechaEmision       time.Time        `json:"fechaEmision"`
+	FechaVencimiento   *time.Time       `json:"fechaVencimiento,omitempty"`
+	Afiliado           AfiliadoBasico   `json:"afiliado"`
+	Prestador          PrestadorBasico  `json:"prestador"`
+	DrogaGenerica      string           `json:"drogaGenerica"`
+	Medicamento        string           `json:"medicamento"`
+	MarcaSugerida      string           `json:"marcaSugerida,omitempty"`
+	Dosis              string           `json:"dosis"`
+	Posologia          *Posologia       `json:"posologia,omitempty"`
+	EnvasesPrescriptos int              `json:"envasesPrescriptos"`
+	EnvasesPendientes  int              `json:"envasesPendientes"`
+	Dispensas          []DispensaReceta `json:"dispensas,omitempty"`
+}
+
+// DispensarRecetaRequest representa el request de POST /recetas/verificar/:codigo/dispensar.
+// El CUIT de la farmacia sale del access token.
+type DispensarRecetaRequest struct {
+	Envases int `json:"envases,omitempty"` // default: todos los pendientes
+}
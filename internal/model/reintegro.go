@@ -15,32 +15,76 @@ type ReintegroListItem struct {
 	Estado             EstadoAutorizacion `json:"estado"`
 	FechaCreacion      time.Time          `json:"fechaCreacion"`
 	FechaActualizacion time.Time          `json:"fechaActualizacion"`
+	PrestacionCodigo   string             `json:"prestacionCodigo"`
 	Prestacion         string             `json:"prestacion"`
-	Metodo             string             `json:"metodo"` // Efectivo | Debito | Credito (mock)
-	Monto              float64            `json:"monto"`
+	EspecialidadCodigo string             `json:"especialidadCodigo"`
+	Especialidad       string             `json:"especialidad"`
+	Metodo             MetodoReintegro    `json:"metodo"` // EFECTIVO | TRANSFERENCIA
+	Moneda             Moneda             `json:"moneda"`
+	Monto              Monto              `json:"monto"`
+	MontoReconocido    Monto              `json:"montoReconocido"`
 }
 
 // ReintegroDetalle representa el detalle completo de un reintegro
 type ReintegroDetalle struct {
-	ID                 int                `json:"id"`
-	Tipo               TipoSolicitud      `json:"tipo"` // "REINTEGRO"
-	Estado             EstadoAutorizacion `json:"estado"`
-	FechaCreacion      time.Time          `json:"fechaCreacion"`
-	FechaActualizacion time.Time          `json:"fechaActualizacion"`
-	Afiliado           AfiliadoBasico     `json:"afiliado"`
-	Prestacion         string             `json:"prestacion"`
-	Metodo             string             `json:"metodo"`
-	Monto              float64            `json:"monto"`
-	Historial          []HistorialEstado  `json:"historial"`
+	ID                 int                   `json:"id"`
+	Tipo               TipoSolicitud         `json:"tipo"` // "REINTEGRO"
+	Estado             EstadoAutorizacion    `json:"estado"`
+	FechaCreacion      time.Time             `json:"fechaCreacion"`
+	FechaActualizacion time.Time             `json:"fechaActualizacion"`
+	Afiliado           AfiliadoBasico        `json:"afiliado"`
+	Prestador          PrestadorBasico       `json:"prestador"`        // ID 0 en los cargados antes de registrarlo
+	PrestacionCodigo   string                `json:"prestacionCodigo"` // código del nomenclador
+	Prestacion         string                `json:"prestacion"`       // descripción del nomenclador
+	EspecialidadCodigo string                `json:"especialidadCodigo"`
+	Especialidad       string                `json:"especialidad"` // prestación del plan sobre la que se calcula la cobertura
+	Metodo             MetodoReintegro       `json:"metodo"`
+	CuentaCobroID      *int                  `json:"-"`                     // nil si es en EFECTIVO o se cargó antes de pedir la cuenta
+	CuentaCobro        *CuentaCobro          `json:"cuentaCobro,omitempty"` // a dónde se transfiere; la completa el service
+	Moneda             Moneda                `json:"moneda"`
+	Monto              Monto                 `json:"monto"`                 // monto presentado por el afiliado
+	MontoReconocido    Monto                 `json:"montoReconocido"`       // lo que cubre el plan: porcentaje, copago y tope anual
+	Liquidacion        *LiquidacionReintegro `json:"liquidacion,omitempty"` // sin desglose en los reintegros cargados antes de calcularlo
+	AutorizacionID     *int                  `json:"autorizacionId,omitempty"`
+	Factura            *Factura              `json:"factura,omitempty"` // sin factura en los reintegros cargados antes de exigirla
+	Historial          []HistorialEstado     `json:"historial"`
+	Adjuntos           []Adjunto             `json:"adjuntos"` // comprobantes; los completa el service
+}
+
+// LiquidacionReintegro es el desglose de cómo se llegó al monto reconocido. Se guarda al crear el
+// reintegro y al recalcularlo, con los valores del plan y del nomenclador de ese momento.
+type LiquidacionReintegro struct {
+	Fecha               time.Time `json:"fecha"`
+	MontoPresentado     Monto     `json:"montoPresentado"`
+	ValorReferencia     Monto     `json:"valorReferencia"`  // valor del nomenclador; 0 = sin valor de referencia
+	MontoReconocible    Monto     `json:"montoReconocible"` // lo presentado, hasta el valor de referencia
+	PorcentajeCobertura int       `json:"porcentajeCobertura"`
+	Coseguro            Monto     `json:"coseguro"`       // copago del plan, a cargo del afiliado
+	TopeAnual           Monto     `json:"topeAnual"`      // 0 = sin tope
+	ConsumidoAnual      Monto     `json:"consumidoAnual"` // reconocido en el año antes de este reintegro
+	TopeAplicado        Monto     `json:"topeAplicado"`   // lo que se dejó de reconocer por el tope anual
+	MontoReconocido     Monto     `json:"montoReconocido"`
 }
 
 // CreateReintegroRequest representa el request para crear un reintegro
 type CreateReintegroRequest struct {
-	AfiliadoID    int                `json:"afiliadoId" binding:"required"`
-	Prestacion    string             `json:"prestacion" binding:"required"`
-	Metodo        string             `json:"metodo" binding:"required"`
-	Monto         float64            `json:"monto" binding:"required"`
-	EstadoInicial EstadoAutorizacion `json:"estadoInicial"`
+	AfiliadoID         int                  `json:"afiliadoId" binding:"required"`
+	PrestacionCodigo   string               `json:"prestacionCodigo" binding:"required"` // código del nomenclador
+	Prestacion         string               `json:"-"`                                   // descripción y especialidad las completa el service desde el nomenclador
+	EspecialidadCodigo string               `json:"-"`
+	Especialidad       string               `json:"-"`
+	Metodo             MetodoReintegro      `json:"metodo" binding:"required"`
+	CuentaCobroID      *int                 `json:"cuentaCobroId,omitempty"`  // obligatoria para TRANSFERENCIA
+	Monto              Monto                `json:"monto" binding:"required"` // "40000.50"; se acepta también un número
+	Moneda             Moneda               `json:"moneda,omitempty"`         // default ARS, la única admitida
+	AutorizacionID     *int                 `json:"autorizacionId,omitempty"` // obligatorio si el plan exige autorización para la prestación
+	Factura            *Factura             `json:"factura" binding:"required"`
+	EstadoInicial      EstadoAutorizacion   `json:"estadoInicial"`
+	Usuario            string               `json:"-"` // lo completa el service con el prestador autenticado
+	Rol                Rol                  `json:"-"`
+	Afiliado           AfiliadoBasico       `json:"-"` // snapshot del padrón, lo completa el service
+	Prestador          PrestadorBasico      `json:"-"` // el prestador autenticado, lo completa el service
+	Liquidacion        LiquidacionReintegro `json:"-"` // la calcula el service con la cobertura del plan
 }
 
 // CreateReintegroResponse representa la respuesta al crear un reintegro
@@ -53,9 +97,14 @@ type CreateReintegroResponse struct {
 
 // UpdateReintegroRequest representa el request para actualizar datos de un reintegro
 type UpdateReintegroRequest struct {
-	Prestacion string  `json:"prestacion,omitempty"`
-	Metodo     string  `json:"metodo,omitempty"`
-	Monto      float64 `json:"monto,omitempty"`
+	PrestacionCodigo   string                `json:"prestacionCodigo,omitempty"`
+	Prestacion         string                `json:"-"` // las completa el service desde el nomenclador
+	EspecialidadCodigo string                `json:"-"`
+	Especialidad       string                `json:"-"`
+	Metodo             MetodoReintegro       `json:"metodo,omitempty"`
+	CuentaCobroID      *int                  `json:"cuentaCobroId,omitempty"` // el service la deja en nil si el método pasa a EFECTIVO
+	Monto              *Monto                `json:"monto,omitempty"`         // puntero para poder corregirlo a cero
+	Liquidacion        *LiquidacionReintegro `json:"-"`                       // la recalcula el service si cambian prestación o monto
 }
 
 // PaginatedReintegrosResponse representa la respuesta paginada de reintegros
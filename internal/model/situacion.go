@@ -17,15 +17,25 @@ const (
 
 // Situacion representa una situación terapéutica de un afiliado o miembro del grupo
 type Situacion struct {
-	ID                 int             `json:"id"`
-	AfiliadoID         int             `json:"afiliadoId"`          // titular del grupo
-	MiembroID          *int            `json:"miembroId,omitempty"` // null si es el titular
-	Descripcion        string          `json:"descripcion"`
-	FechaInicio        string          `json:"fechaInicio"`        // ISO-8601 (yyyy-mm-dd) para simplificar mock
-	FechaFin           *string         `json:"fechaFin,omitempty"` // ISO-8601 o null
-	Estado             EstadoSituacion `json:"estado"`             // ACTIVA | BAJA | ALTA
-	FechaCreacion      time.Time       `json:"fechaCreacion"`
-	FechaActualizacion time.Time       `json:"fechaActualizacion"`
+	ID                 int                        `json:"id"`
+	AfiliadoID         int                        `json:"afiliadoId"`          // titular del grupo
+	MiembroID          *int                       `json:"miembroId,omitempty"` // null si es el titular
+	Descripcion        string                     `json:"descripcion"`
+	FechaInicio        string                     `json:"fechaInicio"`        // ISO-8601 (yyyy-mm-dd) para simplificar mock
+	FechaFin           *string                    `json:"fechaFin,omitempty"` // ISO-8601 o null
+	Estado             EstadoSituacion            `json:"estado"`             // ACTIVA | BAJA | ALTA
+	FechaCreacion      time.Time                  `json:"fechaCreacion"`
+	FechaActualizacion time.Time                  `json:"fechaActualizacion"`
+	Historial          []HistorialEstadoSituacion `json:"historial,omitempty"`
+}
+
+// HistorialEstadoSituacion registra quién cambió el estado de una situación y cuándo
+type HistorialEstadoSituacion struct {
+	Estado      EstadoSituacion `json:"estado"`
+	Usuario     string          `json:"usuario"`
+	Rol         Rol             `json:"rol,omitempty"`
+	FechaCambio time.Time       `json:"fechaCambio"`
+	Motivo      string          `json:"motivo,omitempty"`
 }
 
 // IntegranteSituaciones agrupa situaciones por integrante (para vista de grupo familiar)
@@ -60,6 +70,8 @@ type CreateSituacionRequest struct {
 	Descripcion string  `json:"descripcion" binding:"required"`
 	FechaInicio string  `json:"fechaInicio" binding:"required"` // yyyy-mm-dd
 	FechaFin    *string `json:"fechaFin,omitempty"`             // opcional
+	Usuario     string  `json:"-"`                              // lo completa el service con el prestador autenticado
+	Rol         Rol     `json:"-"`
 }
 
 // CreateSituacionResponse al crear una situación
@@ -84,7 +96,8 @@ type PatchSituacionRequest struct {
 type CambioEstadoSituacionRequest struct {
 	Estado  EstadoSituacion `json:"estado" binding:"required"` // ACTIVA | BAJA
 	Motivo  string          `json:"motivo,omitempty"`          // opcional, según reglas que definan
-	Usuario string          `json:"usuario" binding:"required"`
+	Usuario string          `json:"-"`                         // lo completa el service con el prestador autenticado
+	Rol     Rol             `json:"-"`
 }
 
 // CambioEstadoSituacionResponse respuesta al cambiar estado
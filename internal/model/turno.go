@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// EstadoTurno representa el estado de un turno de la historia clínica
+type EstadoTurno string
+
+const (
+	EstadoTurnoReservado EstadoTurno = "RESERVADO"
+	EstadoTurnoAtendido  EstadoTurno = "ATENDIDO"
+	EstadoTurnoCancelado EstadoTurno = "CANCELADO"
+)
+
+// NotaTurno es una nota clínica que un prestador deja sobre el turno
+type NotaTurno struct {
+	ID          int       `json:"id"`
+	Fecha       time.Time `json:"fecha"`
+	PrestadorID int       `json:"prestadorId"`
+	Texto       string    `json:"texto"`
+}
+
+// Turno de un afiliado. La especialidad se referencia por código del catálogo;
+// Especialidad es el nombre, copiado al momento de guardar el turno
+type Turno struct {
+	ID                 int         `json:"id"`
+	AfiliadoID         int         `json:"afiliadoId"`
+	Fecha              time.Time   `json:"fecha"`
+	EspecialidadCodigo string      `json:"especialidadCodigo"`
+	Especialidad       string      `json:"especialidad"`
+	Estado             EstadoTurno `json:"estado"`
+	Notas              []NotaTurno `json:"notas"`
+}
+
+// HistoriaClinica para GET /afiliados/:afiliadoId/historia-clinica
+type HistoriaClinica struct {
+	AfiliadoID int     `json:"afiliadoId"`
+	Page       int     `json:"page"`
+	Size       int     `json:"size"`
+	Total      int     `json:"total"`
+	Turnos     []Turno `json:"turnos"`
+}
+
+// CreateTurnoRequest para POST /afiliados/:afiliadoId/turnos
+type CreateTurnoRequest struct {
+	AfiliadoID         int       `json:"-"` // lo completa el handler desde la ruta
+	Fecha              time.Time `json:"fecha" binding:"required"`
+	EspecialidadCodigo string    `json:"especialidadCodigo" binding:"required"`
+	Especialidad       string    `json:"-"` // lo completa el service desde el catálogo
+}
+
+// UpdateTurnoRequest para PATCH /afiliados/:afiliadoId/turnos/:turnoId: solo se
+// modifican los campos presentes
+type UpdateTurnoRequest struct {
+	Fecha              *time.Time  `json:"fecha,omitempty"`
+	EspecialidadCodigo string      `json:"especialidadCodigo,omitempty"`
+	Especialidad       string      `json:"-"`
+	Estado             EstadoTurno `json:"estado,omitempty"` // RESERVADO | ATENDIDO | CANCELADO
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Rol define qué puede hacer un usuario en la API
+type Rol string
+
+const (
+	RolPrestador Rol = "PRESTADOR" // carga y reenvía solicitudes
+	RolAuditor   Rol = "AUDITOR"   // auditor médico: analiza, aprueba, rechaza u observa
+	RolAdmin     Rol = "ADMIN"     // puede hacer todo
+	RolFarmacia  Rol = "FARMACIA"  // dispensa recetas electrónicas; su CUIT queda en cada entrega
+)
+
+// Usuario representa un prestador que puede iniciar sesión en la API
+type Usuario struct {
+	ID           int    `json:"id"`
+	CUIT         string `json:"cuit"`
+	Username     string `json:"username"`
+	Nombre       string `json:"nombre"`
+	Rol          Rol    `json:"rol"`
+	PasswordHash string `json:"-"`
+	Activo       bool   `json:"activo"`
+}
+
+// RefreshToken representa un refresh token emitido. Solo se guarda el hash del token.
+type RefreshToken struct {
+	Hash      string
+	UsuarioID int
+	CreadoEn  time.Time
+	ExpiraEn  time.Time
+	Revocado  bool
+}
+
+// LoginRequest representa el request de POST /login (username = CUIT del prestador)
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RefreshTokenRequest representa el request de POST /refresh y POST /logout
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// LoginResponse representa los tokens emitidos al iniciar sesión o refrescar
+type LoginResponse struct {
+	AccessToken  string  `json:"accessToken"`
+	TokenType    string  `json:"tokenType"` // "Bearer"
+	ExpiresIn    int     `json:"expiresIn"` // segundos
+	RefreshToken string  `json:"refreshToken"`
+	Usuario      Usuario `json:"usuario"`
+}
+
+// RegistrarUsuarioRequest representa el request de POST /usuarios (alta de prestadores, solo ADMIN)
+type RegistrarUsuarioRequest struct {
+	CUIT     string `json:"cuit" binding:"required"` // acepta 20251234567 o 20-25123456-7
+	Username string `json:"username,omitempty"`      // si no viene se genera, p.ej. "prestador.203"
+	Nombre   string `json:"nombre" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Rol      Rol    `json:"rol,omitempty"` // default PRESTADOR
+}
+
+// UsuarioAutenticado es la identidad del prestador que hace el request,
+// tomada del access token validado por el middleware de autenticación
+type UsuarioAutenticado struct {
+	ID       int    `json:"id"`
+	CUIT     string `json:"cuit"`
+	Username string `json:"username"`
+	Nombre   string `json:"nombre"`
+	Rol      Rol    `json:"rol"`
+}
@@ -0,0 +1,38 @@
+package model
+
+// CondicionVenta es la condición de expendio del medicamento
+type CondicionVenta string
+
+const (
+	CondicionVentaLibre               CondicionVenta = "VENTA_LIBRE"
+	CondicionVentaBajoReceta          CondicionVenta = "BAJO_RECETA"
+	CondicionVentaBajoRecetaArchivada CondicionVenta = "BAJO_RECETA_ARCHIVADA"
+)
+
+// Medicamento es un producto del vademécum. Las recetas lo referencian por código y se
+// prescriben por la droga genérica; la marca comercial es solo una sugerencia.
+type Medicamento struct {
+	Codigo         string         `json:"codigo"`
+	DrogaGenerica  string         `json:"drogaGenerica"`
+	MarcaComercial string         `json:"marcaComercial"`
+	Presentacion   string         `json:"presentacion"`
+	Concentracion  string         `json:"concentracion"`
+	Laboratorio    string         `json:"laboratorio"`
+	CondicionVenta CondicionVenta `json:"condicionVenta"`
+}
+
+// NombreGenerico arma la prescripción por nombre genérico: droga, concentración y presentación
+func (m Medicamento) NombreGenerico() string {
+	return m.DrogaGenerica + " " + m.Concentracion + " " + m.Presentacion
+}
+
+// VademecumResponse representa el resultado de una búsqueda en el vademécum
+type VademecumResponse struct {
+	Total int           `json:"total"`
+	Items []Medicamento `json:"items"`
+}
+
+// ImportacionVademecumResponse resume la carga de un CSV en el vademécum
+type ImportacionVademecumResponse struct {
+	Medicamentos int `json:"medicamentos"`
+}
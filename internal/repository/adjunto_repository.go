@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"prestadores-api/internal/model"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrAdjuntoNoEncontrado indica que el adjunto no existe o no es de la solicitud indicada
+	ErrAdjuntoNoEncontrado = errors.New("adjunto no encontrado")
+	// ErrAdjuntoDuplicado indica que la solicitud ya tiene un adjunto con el mismo contenido
+	ErrAdjuntoDuplicado = errors.New("el archivo ya está adjunto a la solicitud")
+)
+
+// AdjuntoRepository guarda los metadatos de los adjuntos; el contenido va al storage
+type AdjuntoRepository interface {
+	// GetBySolicitud devuelve los adjuntos de la solicitud en orden de carga
+	GetBySolicitud(tipo model.TipoSolicitud, solicitudID int) ([]model.Adjunto, error)
+	GetByID(tipo model.TipoSolicitud, solicitudID int, id int) (*model.Adjunto, error)
+	Create(adjunto model.Adjunto) (*model.Adjunto, error)
+}
+
+type adjuntoRepositoryImpl struct {
+	mu       sync.RWMutex
+	adjuntos map[int]*model.Adjunto
+	nextID   int
+}
+
+// NewAdjuntoRepository arranca vacío: los archivos de ejemplo no existirían en el storage
+func NewAdjuntoRepository() AdjuntoRepository {
+	return &adjuntoRepositoryImpl{
+		adjuntos: make(map[int]*model.Adjunto),
+		nextID:   1,
+	}
+}
+
+func (r *adjuntoRepositoryImpl) GetBySolicitud(tipo model.TipoSolicitud, solicitudID int) ([]model.Adjunto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	adjuntos := []model.Adjunto{}
+	for _, a := range r.adjuntos {
+		if a.TipoSolicitud == tipo && a.SolicitudID == solicitudID {
+			adjuntos = append(adjuntos, *a)
+		}
+	}
+	sort.Slice(adjuntos, func(i, j int) bool { return adjuntos[i].ID < adjuntos[j].ID })
+	return adjuntos, nil
+}
+
+func (r *adjuntoRepositoryImpl) GetByID(tipo model.TipoSolicitud, solicitudID int, id int) (*model.Adjunto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.adjuntos[id]
+	if !ok || a.TipoSolicitud != tipo || a.SolicitudID != solicitudID {
+		return nil, ErrAdjuntoNoEncontrado
+	}
+	adjunto := *a
+	return &adjunto, nil
+}
+
+func (r *adjuntoRepositoryImpl) Create(adjunto model.Adjunto) (*model.Adjunto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.adjuntos {
+		if a.TipoSolicitud == adjunto.TipoSolicitud && a.SolicitudID == adjunto.SolicitudID && a.SHA256 == adjunto.SHA256 {
+			return nil, ErrAdjuntoDuplicado
+		}
+	}
+
+	adjunto.ID = r.nextID
+	r.adjuntos[adjunto.ID] = &adjunto
+	r.nextID++
+
+	creado := adjunto
+	return &creado, nil
+}
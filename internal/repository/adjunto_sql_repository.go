@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+)
+
+type adjuntoSQLRepository struct {
+	db *sql.DB
+}
+
+// NewAdjuntoSQLRepository crea un AdjuntoRepository persistido en base de datos
+func NewAdjuntoSQLRepository(db *sql.DB) AdjuntoRepository {
+	return &adjuntoSQLRepository{db: db}
+}
+
+const columnasAdjunto = `id, tipo_solicitud, solicitud_id, categoria, nombre_archivo, content_type,
+	tamanio, sha256, clave, usuario, rol, fecha_carga`
+
+func scanAdjunto(row interface{ Scan(...any) error }, a *model.Adjunto) error {
+	return row.Scan(&a.ID, &a.TipoSolicitud, &a.SolicitudID, &a.Categoria, &a.NombreArchivo, &a.ContentType,
+		&a.Tamanio, &a.SHA256, &a.Clave, &a.Usuario, &a.Rol, &a.FechaCarga)
+}
+
+func (r *adjuntoSQLRepository) GetBySolicitud(tipo model.TipoSolicitud, solicitudID int) ([]model.Adjunto, error) {
+	rows, err := r.db.Query(`
+		SELECT `+columnasAdjunto+`
+		FROM adjuntos
+		WHERE tipo_solicitud = $1 AND solicitud_id = $2
+		ORDER BY id`, tipo, solicitudID)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar adjuntos: %w", err)
+	}
+	defer rows.Close()
+
+	adjuntos := []model.Adjunto{}
+	for rows.Next() {
+		var a model.Adjunto
+		if err := scanAdjunto(rows, &a); err != nil {
+			return nil, fmt.Errorf("error al leer adjunto: %w", err)
+		}
+		adjuntos = append(adjuntos, a)
+	}
+	return adjuntos, rows.Err()
+}
+
+func (r *adjuntoSQLRepository) GetByID(tipo model.TipoSolicitud, solicitudID int, id int) (*model.Adjunto, error) {
+	var a model.Adjunto
+	err := scanAdjunto(r.db.QueryRow(`
+		SELECT `+columnasAdjunto+`
+		FROM adjuntos
+		WHERE id = $1 AND tipo_solicitud = $2 AND solicitud_id = $3`, id, tipo, solicitudID), &a)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAdjuntoNoEncontrado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener adjunto: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *adjuntoSQLRepository) Create(adjunto model.Adjunto) (*model.Adjunto, error) {
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// El índice único también lo impide, pero así el error es el mismo en todos los drivers
+		var existentes int
+		err := tx.QueryRow(`
+			SELECT COUNT(*) FROM adjuntos
+			WHERE tipo_solicitud = $1 AND solicitud_id = $2 AND sha256 = $3`,
+			adjunto.TipoSolicitud, adjunto.SolicitudID, adjunto.SHA256).Scan(&existentes)
+		if err != nil {
+			return fmt.Errorf("error al verificar adjuntos: %w", err)
+		}
+		if existentes > 0 {
+			return ErrAdjuntoDuplicado
+		}
+
+		err = tx.QueryRow(`
+			INSERT INTO adjuntos (tipo_solicitud, solicitud_id, categoria, nombre_archivo, content_type,
+				tamanio, sha256, clave, usuario, rol, fecha_carga)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id`,
+			adjunto.TipoSolicitud, adjunto.SolicitudID, adjunto.Categoria, adjunto.NombreArchivo, adjunto.ContentType,
+			adjunto.Tamanio, adjunto.SHA256, adjunto.Clave, adjunto.Usuario, adjunto.Rol, adjunto.FechaCarga,
+		).Scan(&adjunto.ID)
+		if err != nil {
+			return fmt.Errorf("error al crear adjunto: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &adjunto, nil
+}
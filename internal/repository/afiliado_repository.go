@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"cmp"
+	"errors"
+	"prestadores-api/internal/model"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrAfiliadoNoEncontrado indica que el ID no corresponde a ningún afiliado del padrón
+var ErrAfiliadoNoEncontrado = errors.New("afiliado no encontrado")
+
+type AfiliadoRepository interface {
+	GetAll(filtro model.AfiliadoFiltro, page int, size int) ([]model.Afiliado, int, error)
+	GetByID(id int) (*model.Afiliado, error)
+	GetIntegrantes(titularID int) ([]model.Afiliado, error)
+	ContarPorPlan(planID int) (int, error)
+}
+
+type afiliadoRepositoryImpl struct {
+	mu        sync.RWMutex
+	afiliados map[int]*model.Afiliado
+}
+
+func NewAfiliadoRepository() AfiliadoRepository {
+	repo := &afiliadoRepositoryImpl{
+		afiliados: make(map[int]*model.Afiliado),
+	}
+
+	repo.initializeDummyData()
+
+	return repo
+}
+
+// initializeDummyData carga el padrón de ejemplo; la migración 0024 carga el mismo para los drivers SQL.
+// Solicitudes y situaciones resuelven contra estos IDs.
+func (r *afiliadoRepositoryImpl) initializeDummyData() {
+	titular := func(id int) *int { return &id }
+	fecha := func(anio int, mes time.Month, dia int) time.Time {
+		return time.Date(anio, mes, dia, 0, 0, 0, 0, time.UTC)
+	}
+	baja := fecha(2025, 6, 30)
+	// Alta reciente: sigue en período de carencia para las prestaciones que lo tienen
+	altaReciente := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -15)
+
+	dummyData := []model.Afiliado{
+		{
+			ID: 1, NroAfiliado: "15121231523", DNI: "43521489", Nombre: "María", Apellido: "Candia",
+			PlanMedicoID: 1, Parentesco: model.ParentescoTitular,
+			Email: "maria.candia@email.com", Telefono: "011-4567-8901", Ciudad: "Buenos Aires", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2018, 3, 1),
+		},
+		{
+			ID: 2, NroAfiliado: "15121231524", DNI: "53521489", Nombre: "Stella", Apellido: "Rodriguez",
+			PlanMedicoID: 2, Parentesco: model.ParentescoTitular,
+			Email: "stella.rodriguez@email.com", Telefono: "0341-234-5678", Ciudad: "Rosario", Provincia: "Santa Fe",
+			FechaAlta: fecha(2020, 7, 15),
+		},
+		{
+			ID: 3, NroAfiliado: "15121231525", DNI: "40456015", Nombre: "Nicolas", Apellido: "Martin",
+			PlanMedicoID: 1, TitularID: titular(1), Parentesco: model.ParentescoConyuge,
+			Email: "nicolas.martin@email.com", Telefono: "011-4567-8902", Ciudad: "Buenos Aires", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2018, 3, 1),
+		},
+		{
+			ID: 4, NroAfiliado: "15121231526", DNI: "12334555", Nombre: "Sofia", Apellido: "Lopez",
+			PlanMedicoID: 1, TitularID: titular(5), Parentesco: model.ParentescoHija,
+			Email: "sofia.lopez@email.com", Telefono: "0351-422-1100", Ciudad: "Córdoba", Provincia: "Córdoba",
+			FechaAlta: fecha(2016, 5, 10),
+		},
+		{
+			ID: 5, NroAfiliado: "15121231527", DNI: "11000189", Nombre: "Facundo", Apellido: "Gomez",
+			PlanMedicoID: 1, Parentesco: model.ParentescoTitular,
+			Email: "facundo.gomez@email.com", Telefono: "0351-422-1101", Ciudad: "Córdoba", Provincia: "Córdoba",
+			FechaAlta: fecha(2016, 5, 10),
+		},
+		{
+			ID: 6, NroAfiliado: "15121231528", DNI: "29876543", Nombre: "Julieta", Apellido: "Paz",
+			PlanMedicoID: 2, Parentesco: model.ParentescoTitular,
+			Email: "julieta.paz@email.com", Telefono: "011-4822-3344", Ciudad: "Buenos Aires", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2017, 2, 1), FechaBaja: &baja,
+		},
+		{
+			ID: 7, NroAfiliado: "15121231529", DNI: "44123987", Nombre: "Tomás", Apellido: "Ríos",
+			PlanMedicoID: 3, Parentesco: model.ParentescoTitular,
+			Email: "tomas.rios@email.com", Telefono: "0342-455-1212", Ciudad: "Santa Fe", Provincia: "Santa Fe",
+			FechaAlta: altaReciente,
+		},
+		// Afiliados referenciados por las solicitudes y situaciones de ejemplo
+		{
+			ID: 22, NroAfiliado: "15121232201", DNI: "32654708", Nombre: "Miguel", Apellido: "Osorio",
+			PlanMedicoID: 2, Parentesco: model.ParentescoTitular,
+			Email: "miguel.osorio@email.com", Telefono: "011-4780-1122", Ciudad: "Vicente López", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2019, 2, 1),
+		},
+		{
+			ID: 2201, NroAfiliado: "15121232202", DNI: "52123789", Nombre: "Ana", Apellido: "Osorio",
+			PlanMedicoID: 2, TitularID: titular(22), Parentesco: model.ParentescoHija,
+			Email: "ana.osorio@email.com", Telefono: "011-4780-1123", Ciudad: "Vicente López", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2019, 2, 1),
+		},
+		{
+			ID: 2202, NroAfiliado: "15121232203", DNI: "33987654", Nombre: "Luis", Apellido: "Osorio",
+			PlanMedicoID: 2, TitularID: titular(22), Parentesco: model.ParentescoConyuge,
+			Email: "luis.osorio@email.com", Telefono: "011-4780-1124", Ciudad: "Vicente López", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2019, 2, 1),
+		},
+		{
+			ID: 23, NroAfiliado: "15121232301", DNI: "28456123", Nombre: "Ana", Apellido: "Fernández",
+			PlanMedicoID: 3, Parentesco: model.ParentescoTitular,
+			Email: "ana.fernandez@email.com", Telefono: "0221-455-7788", Ciudad: "La Plata", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2021, 9, 1),
+		},
+		{
+			ID: 24, NroAfiliado: "15121232401", DNI: "35789456", Nombre: "Roberto", Apellido: "Díaz",
+			PlanMedicoID: 1, Parentesco: model.ParentescoTitular,
+			Email: "roberto.diaz@email.com", Telefono: "0261-423-9090", Ciudad: "Mendoza", Provincia: "Mendoza",
+			FechaAlta: fecha(2017, 11, 20),
+		},
+		{
+			ID: 31, NroAfiliado: "15121233101", DNI: "45678089", Nombre: "David", Apellido: "Queen",
+			PlanMedicoID: 3, Parentesco: model.ParentescoTitular,
+			Email: "david.queen@email.com", Telefono: "011-4311-2020", Ciudad: "Buenos Aires", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2022, 4, 1),
+		},
+		{
+			ID: 3101, NroAfiliado: "15121233102", DNI: "58456321", Nombre: "Pedro", Apellido: "Queen",
+			PlanMedicoID: 3, TitularID: titular(31), Parentesco: model.ParentescoHijo,
+			Email: "pedro.queen@email.com", Telefono: "011-4311-2021", Ciudad: "Buenos Aires", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2022, 4, 1),
+		},
+		{
+			ID: 32, NroAfiliado: "15121233201", DNI: "38567123", Nombre: "Laura", Apellido: "García",
+			PlanMedicoID: 2, Parentesco: model.ParentescoTitular,
+			Email: "laura.garcia@email.com", Telefono: "0341-448-3030", Ciudad: "Rosario", Provincia: "Santa Fe",
+			FechaAlta: fecha(2020, 1, 6),
+		},
+		{
+			ID: 33, NroAfiliado: "15121233301", DNI: "42123456", Nombre: "Carlos", Apellido: "Martínez",
+			PlanMedicoID: 1, Parentesco: model.ParentescoTitular,
+			Email: "carlos.martinez@email.com", Telefono: "0351-425-4040", Ciudad: "Córdoba", Provincia: "Córdoba",
+			FechaAlta: fecha(2015, 8, 3),
+		},
+		{
+			ID: 45, NroAfiliado: "15121234501", DNI: "21345633", Nombre: "Daniela", Apellido: "Reynoso",
+			PlanMedicoID: 3, Parentesco: model.ParentescoTitular,
+			Email: "daniela.reynoso@email.com", Telefono: "0299-443-5050", Ciudad: "Neuquén", Provincia: "Neuquén",
+			FechaAlta: fecha(2023, 6, 12),
+		},
+		{
+			ID: 46, NroAfiliado: "15121234601", DNI: "30123456", Nombre: "Marcos", Apellido: "Ledesma",
+			PlanMedicoID: 2, Parentesco: model.ParentescoTitular,
+			Email: "marcos.ledesma@email.com", Telefono: "0381-421-6060", Ciudad: "San Miguel de Tucumán", Provincia: "Tucumán",
+			FechaAlta: fecha(2019, 10, 1),
+		},
+		{
+			ID: 47, NroAfiliado: "15121234701", DNI: "34567890", Nombre: "Lucía", Apellido: "Fernández",
+			PlanMedicoID: 1, Parentesco: model.ParentescoTitular,
+			Email: "lucia.fernandez@email.com", Telefono: "0223-492-7070", Ciudad: "Mar del Plata", Provincia: "Buenos Aires",
+			FechaAlta: fecha(2024, 3, 18),
+		},
+	}
+
+	for _, a := range dummyData {
+		r.afiliados[a.ID] = &a
+	}
+}
+
+// GetAll filtra por plan, titularidad y búsqueda, ordenando por apellido, nombre e ID
+func (r *afiliadoRepositoryImpl) GetAll(filtro model.AfiliadoFiltro, page int, size int) ([]model.Afiliado, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := tokensBusqueda(filtro.Query)
+
+	var items []model.Afiliado
+	for _, a := range r.afiliados {
+		if filtro.PlanID != 0 && a.PlanMedicoID != filtro.PlanID {
+			continue
+		}
+
+		if filtro.Titular != nil && a.EsTitular() != *filtro.Titular {
+			continue
+		}
+
+		if len(tokens) > 0 && !coincideBusqueda(tokens, textoBusqueda(a.DNI, a.NroAfiliado, a.Nombre, a.Apellido)) {
+			continue
+		}
+
+		items = append(items, *a)
+	}
+
+	slices.SortFunc(items, func(a, b model.Afiliado) int {
+		return cmp.Or(
+			compararAfiliado(a.Basico(), b.Basico()),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	total := len(items)
+
+	start := page * size
+	end := start + size
+
+	if start > total {
+		return []model.Afiliado{}, total, nil
+	}
+
+	if end > total {
+		end = total
+	}
+
+	return items[start:end], total, nil
+}
+
+func (r *afiliadoRepositoryImpl) GetByID(id int) (*model.Afiliado, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, exists := r.afiliados[id]
+	if !exists {
+		return nil, ErrAfiliadoNoEncontrado
+	}
+
+	copia := *a
+	return &copia, nil
+}
+
+// GetIntegrantes devuelve los afiliados a cargo del titular, ordenados por ID
+func (r *afiliadoRepositoryImpl) GetIntegrantes(titularID int) ([]model.Afiliado, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	integrantes := []model.Afiliado{}
+	for _, a := range r.afiliados {
+		if a.TitularID != nil && *a.TitularID == titularID {
+			integrantes = append(integrantes, *a)
+		}
+	}
+
+	slices.SortFunc(integrantes, func(a, b model.Afiliado) int { return cmp.Compare(a.ID, b.ID) })
+
+	return integrantes, nil
+}
+
+// ContarPorPlan indica cuántos afiliados tienen asignado el plan
+func (r *afiliadoRepositoryImpl) ContarPorPlan(planID int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cantidad := 0
+	for _, a := range r.afiliados {
+		if a.PlanMedicoID == planID {
+			cantidad++
+		}
+	}
+	return cantidad, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+)
+
+type afiliadoSQLRepository struct {
+	db *sql.DB
+}
+
+// NewAfiliadoSQLRepository crea un AfiliadoRepository sobre la tabla afiliados
+func NewAfiliadoSQLRepository(db *sql.DB) AfiliadoRepository {
+	return &afiliadoSQLRepository{db: db}
+}
+
+const columnasAfiliado = `id, nro_afiliado, dni, nombre, apellido, plan_medico_id, titular_id, parentesco,
+	email, telefono, ciudad, provincia, fecha_alta, fecha_baja`
+
+func scanAfiliado(row interface{ Scan(...any) error }, a *model.Afiliado) error {
+	var (
+		titularID sql.NullInt64
+		baja      sql.NullTime
+	)
+	if err := row.Scan(&a.ID, &a.NroAfiliado, &a.DNI, &a.Nombre, &a.Apellido, &a.PlanMedicoID, &titularID, &a.Parentesco,
+		&a.Email, &a.Telefono, &a.Ciudad, &a.Provincia, &a.FechaAlta, &baja); err != nil {
+		return err
+	}
+	if titularID.Valid {
+		a.TitularID = intPtr(int(titularID.Int64))
+	}
+	if baja.Valid {
+		a.FechaBaja = &baja.Time
+	}
+	return nil
+}
+
+func (r *afiliadoSQLRepository) GetAll(filtro model.AfiliadoFiltro, page int, size int) ([]model.Afiliado, int, error) {
+	w := &sqlWhere{}
+	if filtro.PlanID != 0 {
+		w.add("plan_medico_id = ?", filtro.PlanID)
+	}
+	if filtro.Titular != nil {
+		if *filtro.Titular {
+			w.add("titular_id IS NULL")
+		} else {
+			w.add("titular_id IS NOT NULL")
+		}
+	}
+	w.addBusqueda("texto_busqueda", filtro.Query)
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM afiliados"+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar afiliados: %w", err)
+	}
+
+	where := w.String()
+	limit, offset := w.arg(size), w.arg(page*size)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM afiliados%s
+		ORDER BY apellido, nombre, id
+		LIMIT %s OFFSET %s`, columnasAfiliado, where, limit, offset), w.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al listar afiliados: %w", err)
+	}
+	defer rows.Close()
+
+	items := []model.Afiliado{}
+	for rows.Next() {
+		var a model.Afiliado
+		if err := scanAfiliado(rows, &a); err != nil {
+			return nil, 0, fmt.Errorf("error al leer afiliado: %w", err)
+		}
+		items = append(items, a)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *afiliadoSQLRepository) GetByID(id int) (*model.Afiliado, error) {
+	var a model.Afiliado
+	err := scanAfiliado(r.db.QueryRow(`SELECT `+columnasAfiliado+` FROM afiliados WHERE id = $1`, id), &a)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAfiliadoNoEncontrado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener afiliado: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *afiliadoSQLRepository) GetIntegrantes(titularID int) ([]model.Afiliado, error) {
+	rows, err := r.db.Query(`SELECT `+columnasAfiliado+` FROM afiliados WHERE titular_id = $1 ORDER BY id`, titularID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener integrantes: %w", err)
+	}
+	defer rows.Close()
+
+	integrantes := []model.Afiliado{}
+	for rows.Next() {
+		var a model.Afiliado
+		if err := scanAfiliado(rows, &a); err != nil {
+			return nil, fmt.Errorf("error al leer afiliado: %w", err)
+		}
+		integrantes = append(integrantes, a)
+	}
+	return integrantes, rows.Err()
+}
+
+func (r *afiliadoSQLRepository) ContarPorPlan(planID int) (int, error) {
+	var cantidad int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM afiliados WHERE plan_medico_id = $1`, planID).Scan(&cantidad); err != nil {
+		return 0, fmt.Errorf("error al contar afiliados: %w", err)
+	}
+	return cantidad, nil
+}
@@ -1,18 +1,30 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"prestadores-api/internal/model"
+	"slices"
 	"sync"
 	"time"
 )
 
+// ErrEstadoModificado indica que la solicitud ya no estaba en el estado esperado al cambiarlo:
+// otro usuario la movió entre la validación de la transición y el cambio
+var ErrEstadoModificado = errors.New("la solicitud cambió de estado")
+
+// estadosEditables son los estados en los que el prestador todavía puede corregir una autorización o un reintegro
+var estadosEditables = []model.EstadoAutorizacion{model.EstadoRecibido, model.EstadoObservado}
+
 type AutorizacionRepository interface {
-	GetAll(estado string, query string, page int, size int, sort string) ([]model.AutorizacionListItem, int, error)
+	GetAll(estado string, especialidad string, query string, page int, size int, sort string) ([]model.AutorizacionListItem, int, error)
 	GetByID(id int) (*model.AutorizacionDetalle, error)
 	Create(req model.CreateAutorizacionRequest) (*model.AutorizacionDetalle, error)
+	// Update aplica los cambios solo si la autorización sigue en un estado editable (RECIBIDO u OBSERVADO);
+	// si no devuelve ErrEstadoModificado
 	Update(id int, req model.UpdateAutorizacionRequest) error
-	CambiarEstado(id int, req model.CambioEstadoRequest) (*model.AutorizacionDetalle, error)
+	// CambiarEstado aplica el cambio solo si sigue en estadoActual; si no devuelve ErrEstadoModificado
+	CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.AutorizacionDetalle, error)
 }
 
 type autorizacionRepositoryImpl struct {
@@ -46,8 +58,15 @@ func (r *autorizacionRepositoryImpl) initializeDummyData() {
 				Nombre:   "David",
 				Apellido: "Queen",
 			},
-			Procedimiento: "Consulta de control",
-			Especialidad:  "Clínica Médica",
+			Prestador: model.PrestadorBasico{
+				ID:     201,
+				CUIT:   "20251234567",
+				Nombre: "Juan Pérez",
+			},
+			ProcedimientoCodigo: "420102",
+			Procedimiento:       "Consulta de control",
+			EspecialidadCodigo:  "CLINICA_MEDICA",
+			Especialidad:        "Clínica Médica",
 			Historial: []model.HistorialEstado{
 				{
 					Estado:      model.EstadoRecibido,
@@ -79,8 +98,15 @@ func (r *autorizacionRepositoryImpl) initializeDummyData() {
 				Nombre:   "Laura",
 				Apellido: "García",
 			},
-			Procedimiento: "Radiografía de tórax",
-			Especialidad:  "Diagnóstico por Imágenes",
+			Prestador: model.PrestadorBasico{
+				ID:     202,
+				CUIT:   "27314567892",
+				Nombre: "Laura Gómez",
+			},
+			ProcedimientoCodigo: "340201",
+			Procedimiento:       "Radiografía de tórax",
+			EspecialidadCodigo:  "DIAGNOSTICO_IMAGENES",
+			Especialidad:        "Diagnóstico por Imágenes",
 			Historial: []model.HistorialEstado{
 				{
 					Estado:      model.EstadoRecibido,
@@ -88,9 +114,10 @@ func (r *autorizacionRepositoryImpl) initializeDummyData() {
 					FechaCambio: time.Date(2025, 9, 3, 9, 0, 0, 0, time.UTC),
 				},
 				{
-					Estado:      model.EstadoAprobado,
-					Usuario:     "prestador.202",
-					FechaCambio: time.Date(2025, 9, 3, 11, 0, 0, 0, time.UTC),
+					Estado:              model.EstadoAprobado,
+					Usuario:             "prestador.202",
+					FechaCambio:         time.Date(2025, 9, 3, 11, 0, 0, 0, time.UTC),
+					ProcedimientoCodigo: "340201",
 				},
 			},
 		},
@@ -106,8 +133,15 @@ func (r *autorizacionRepositoryImpl) initializeDummyData() {
 				Nombre:   "Carlos",
 				Apellido: "Martínez",
 			},
-			Procedimiento: "Consulta cardiológica",
-			Especialidad:  "Cardiología",
+			Prestador: model.PrestadorBasico{
+				ID:     203,
+				CUIT:   "20289990001",
+				Nombre: "Gustavo Ibarra",
+			},
+			ProcedimientoCodigo: "170106",
+			Procedimiento:       "Consulta cardiológica",
+			EspecialidadCodigo:  "CARDIOLOGIA",
+			Especialidad:        "Cardiología",
 			Historial: []model.HistorialEstado{
 				{
 					Estado:      model.EstadoRecibido,
@@ -130,33 +164,47 @@ func (r *autorizacionRepositoryImpl) initializeDummyData() {
 	r.nextID = 12004
 }
 
-func (r *autorizacionRepositoryImpl) GetAll(estado string, query string, page int, size int, sort string) ([]model.AutorizacionListItem, int, error) {
+func (r *autorizacionRepositoryImpl) GetAll(estado string, especialidad string, query string, page int, size int, sort string) ([]model.AutorizacionListItem, int, error) {
+	orden, err := parseOrden(sort, ordenAutorizaciones)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	tokens := tokensBusqueda(query)
+
 	var items []model.AutorizacionListItem
 	for _, aut := range r.autorizaciones {
 		if estado != "" && string(aut.Estado) != estado {
 			continue
 		}
+		if especialidad != "" && aut.EspecialidadCodigo != especialidad {
+			continue
+		}
 
-		if query != "" {
-			// TODO: implementar búsqueda
+		if len(tokens) > 0 && !coincideBusqueda(tokens, textoBusquedaAutorizacion(aut.ID, aut.Afiliado, aut.ProcedimientoCodigo, aut.Procedimiento, aut.Especialidad)) {
+			continue
 		}
 
 		item := model.AutorizacionListItem{
-			ID:                 aut.ID,
-			Tipo:               aut.Tipo,
-			Afiliado:           aut.Afiliado,
-			Estado:             aut.Estado,
-			FechaCreacion:      aut.FechaCreacion,
-			FechaActualizacion: aut.FechaActualizacion,
-			Procedimiento:      aut.Procedimiento,
-			Especialidad:       aut.Especialidad,
+			ID:                  aut.ID,
+			Tipo:                aut.Tipo,
+			Afiliado:            aut.Afiliado,
+			Estado:              aut.Estado,
+			FechaCreacion:       aut.FechaCreacion,
+			FechaActualizacion:  aut.FechaActualizacion,
+			ProcedimientoCodigo: aut.ProcedimientoCodigo,
+			Procedimiento:       aut.Procedimiento,
+			EspecialidadCodigo:  aut.EspecialidadCodigo,
+			Especialidad:        aut.Especialidad,
 		}
 		items = append(items, item)
 	}
 
+	ordenar(items, orden, ordenAutorizaciones, func(i model.AutorizacionListItem) int { return i.ID })
+
 	total := len(items)
 
 	start := page * size
@@ -197,23 +245,22 @@ func (r *autorizacionRepositoryImpl) Create(req model.CreateAutorizacionRequest)
 	now := time.Now()
 
 	aut := &model.AutorizacionDetalle{
-		ID:                 r.nextID,
-		Tipo:               model.TipoAutorizacion,
-		Estado:             estadoInicial,
-		FechaCreacion:      now,
-		FechaActualizacion: now,
-		Afiliado: model.AfiliadoBasico{
-			ID:       req.AfiliadoID,
-			DNI:      "dummy-dni",
-			Nombre:   "Dummy",
-			Apellido: "Afiliado",
-		},
-		Procedimiento: req.Procedimiento,
-		Especialidad:  req.Especialidad,
+		ID:                  r.nextID,
+		Tipo:                model.TipoAutorizacion,
+		Estado:              estadoInicial,
+		FechaCreacion:       now,
+		FechaActualizacion:  now,
+		Afiliado:            req.Afiliado,
+		Prestador:           req.Prestador,
+		ProcedimientoCodigo: req.ProcedimientoCodigo,
+		Procedimiento:       req.Procedimiento,
+		EspecialidadCodigo:  req.EspecialidadCodigo,
+		Especialidad:        req.Especialidad,
 		Historial: []model.HistorialEstado{
 			{
 				Estado:      estadoInicial,
-				Usuario:     "sistema",
+				Usuario:     usuarioOSistema(req.Usuario),
+				Rol:         req.Rol,
 				FechaCambio: now,
 			},
 		},
@@ -233,12 +280,14 @@ func (r *autorizacionRepositoryImpl) Update(id int, req model.UpdateAutorizacion
 	if !exists {
 		return fmt.Errorf("autorización no encontrada")
 	}
-
-	if req.Procedimiento != "" {
-		aut.Procedimiento = req.Procedimiento
+	if !slices.Contains(estadosEditables, aut.Estado) {
+		return ErrEstadoModificado
 	}
 
-	if req.Especialidad != "" {
+	if req.ProcedimientoCodigo != "" {
+		aut.ProcedimientoCodigo = req.ProcedimientoCodigo
+		aut.Procedimiento = req.Procedimiento
+		aut.EspecialidadCodigo = req.EspecialidadCodigo
 		aut.Especialidad = req.Especialidad
 	}
 
@@ -247,7 +296,7 @@ func (r *autorizacionRepositoryImpl) Update(id int, req model.UpdateAutorizacion
 	return nil
 }
 
-func (r *autorizacionRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRequest) (*model.AutorizacionDetalle, error) {
+func (r *autorizacionRepositoryImpl) CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.AutorizacionDetalle, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -255,6 +304,9 @@ func (r *autorizacionRepositoryImpl) CambiarEstado(id int, req model.CambioEstad
 	if !exists {
 		return nil, fmt.Errorf("autorización no encontrada")
 	}
+	if aut.Estado != estadoActual {
+		return nil, ErrEstadoModificado
+	}
 
 	if (req.NuevoEstado == model.EstadoObservado || req.NuevoEstado == model.EstadoRechazado) && req.Motivo == "" {
 		return nil, fmt.Errorf("el motivo es obligatorio para estados OBSERVADO y RECHAZADO")
@@ -268,9 +320,13 @@ func (r *autorizacionRepositoryImpl) CambiarEstado(id int, req model.CambioEstad
 	historial := model.HistorialEstado{
 		Estado:      req.NuevoEstado,
 		Usuario:     req.Usuario,
+		Rol:         req.Rol,
 		FechaCambio: now,
 		Motivo:      req.Motivo,
 	}
+	if req.NuevoEstado == model.EstadoAprobado {
+		historial.ProcedimientoCodigo = aut.ProcedimientoCodigo
+	}
 
 	aut.Historial = append(aut.Historial, historial)
 
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"time"
+)
+
+type autorizacionSQLRepository struct {
+	db *sql.DB
+}
+
+// NewAutorizacionSQLRepository crea un AutorizacionRepository persistido en base de datos
+func NewAutorizacionSQLRepository(db *sql.DB) AutorizacionRepository {
+	return &autorizacionSQLRepository{db: db}
+}
+
+func (r *autorizacionSQLRepository) GetAll(estado string, especialidad string, query string, page int, size int, sort string) ([]model.AutorizacionListItem, int, error) {
+	orden, err := parseOrden(sort, ordenAutorizaciones)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	w := &sqlWhere{}
+	if estado != "" {
+		w.add("estado = ?", estado)
+	}
+	if especialidad != "" {
+		w.add("especialidad_codigo = ?", especialidad)
+	}
+	w.addBusqueda("texto_busqueda", query)
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM autorizaciones"+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar autorizaciones: %w", err)
+	}
+
+	where := w.String()
+	limit, offset := w.arg(size), w.arg(page*size)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       procedimiento_codigo, procedimiento, especialidad_codigo, especialidad
+		FROM autorizaciones%s%s
+		LIMIT %s OFFSET %s`, where, orderBy(orden, ordenAutorizaciones), limit, offset), w.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al listar autorizaciones: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.AutorizacionListItem
+	for rows.Next() {
+		item := model.AutorizacionListItem{Tipo: model.TipoAutorizacion}
+		if err := rows.Scan(
+			&item.ID, &item.Estado, &item.FechaCreacion, &item.FechaActualizacion,
+			&item.Afiliado.ID, &item.Afiliado.DNI, &item.Afiliado.Nombre, &item.Afiliado.Apellido,
+			&item.ProcedimientoCodigo, &item.Procedimiento, &item.EspecialidadCodigo, &item.Especialidad,
+		); err != nil {
+			return nil, 0, fmt.Errorf("error al leer autorización: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+func (r *autorizacionSQLRepository) GetByID(id int) (*model.AutorizacionDetalle, error) {
+	aut := &model.AutorizacionDetalle{Tipo: model.TipoAutorizacion}
+
+	err := r.db.QueryRow(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       prestador_id, prestador_cuit, prestador_nombre,
+		       procedimiento_codigo, procedimiento, especialidad_codigo, especialidad
+		FROM autorizaciones
+		WHERE id = $1`, id).Scan(
+		&aut.ID, &aut.Estado, &aut.FechaCreacion, &aut.FechaActualizacion,
+		&aut.Afiliado.ID, &aut.Afiliado.DNI, &aut.Afiliado.Nombre, &aut.Afiliado.Apellido,
+		&aut.Prestador.ID, &aut.Prestador.CUIT, &aut.Prestador.Nombre,
+		&aut.ProcedimientoCodigo, &aut.Procedimiento, &aut.EspecialidadCodigo, &aut.Especialidad,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("autorización no encontrada")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener autorización: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT estado, usuario, rol, fecha_cambio, motivo, procedimiento_codigo
+		FROM autorizacion_historial
+		WHERE autorizacion_id = $1
+		ORDER BY fecha_cambio, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de autorización: %w", err)
+	}
+	defer rows.Close()
+
+	aut.Historial = []model.HistorialEstado{}
+	for rows.Next() {
+		var h model.HistorialEstado
+		if err := rows.Scan(&h.Estado, &h.Usuario, &h.Rol, &h.FechaCambio, &h.Motivo, &h.ProcedimientoCodigo); err != nil {
+			return nil, fmt.Errorf("error al leer historial de autorización: %w", err)
+		}
+		aut.Historial = append(aut.Historial, h)
+	}
+
+	return aut, rows.Err()
+}
+
+func (r *autorizacionSQLRepository) Create(req model.CreateAutorizacionRequest) (*model.AutorizacionDetalle, error) {
+	estadoInicial := req.EstadoInicial
+	if estadoInicial == "" {
+		estadoInicial = model.EstadoRecibido
+	}
+
+	now := time.Now().UTC()
+	afiliado := req.Afiliado
+
+	var id int
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			INSERT INTO autorizaciones (estado, fecha_creacion, fecha_actualizacion,
+				afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+				prestador_id, prestador_cuit, prestador_nombre,
+				procedimiento_codigo, procedimiento, especialidad_codigo, especialidad)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id`,
+			estadoInicial, now, now,
+			afiliado.ID, afiliado.DNI, afiliado.Nombre, afiliado.Apellido,
+			req.Prestador.ID, req.Prestador.CUIT, req.Prestador.Nombre,
+			req.ProcedimientoCodigo, req.Procedimiento, req.EspecialidadCodigo, req.Especialidad,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error al crear autorización: %w", err)
+		}
+
+		_, err = tx.Exec("UPDATE autorizaciones SET texto_busqueda = $1 WHERE id = $2",
+			textoBusquedaAutorizacion(id, afiliado, req.ProcedimientoCodigo, req.Procedimiento, req.Especialidad), id)
+		if err != nil {
+			return fmt.Errorf("error al indexar autorización: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO autorizacion_historial (autorizacion_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, estadoInicial, usuarioOSistema(req.Usuario), req.Rol, now, "")
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de autorización: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *autorizacionSQLRepository) Update(id int, req model.UpdateAutorizacionRequest) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var (
+			afiliado           model.AfiliadoBasico
+			codigo             string
+			procedimiento      string
+			especialidadCodigo string
+			especialidad       string
+		)
+		err := tx.QueryRow(`
+			SELECT afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido, procedimiento_codigo, procedimiento, especialidad_codigo, especialidad
+			FROM autorizaciones WHERE id = $1`, id).Scan(
+			&afiliado.ID, &afiliado.DNI, &afiliado.Nombre, &afiliado.Apellido, &codigo, &procedimiento, &especialidadCodigo, &especialidad,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("autorización no encontrada")
+		}
+		if err != nil {
+			return fmt.Errorf("error al obtener autorización: %w", err)
+		}
+
+		if req.ProcedimientoCodigo != "" {
+			codigo = req.ProcedimientoCodigo
+			procedimiento = req.Procedimiento
+			especialidadCodigo = req.EspecialidadCodigo
+			especialidad = req.Especialidad
+		}
+
+		res, err := tx.Exec(`
+			UPDATE autorizaciones SET
+				procedimiento_codigo = $1,
+				procedimiento = $2,
+				especialidad_codigo = $3,
+				especialidad = $4,
+				texto_busqueda = $5,
+				fecha_actualizacion = $6
+			WHERE id = $7 AND estado IN ($8, $9)`,
+			codigo, procedimiento, especialidadCodigo, especialidad, textoBusquedaAutorizacion(id, afiliado, codigo, procedimiento, especialidad), time.Now().UTC(), id,
+			model.EstadoRecibido, model.EstadoObservado)
+		if err != nil {
+			return fmt.Errorf("error al actualizar autorización: %w", err)
+		}
+		// Si el auditor la tomó entre la lectura y la escritura no se cambia lo que está auditando
+		return checkRowsAffected(res, ErrEstadoModificado)
+	})
+}
+
+func (r *autorizacionSQLRepository) CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.AutorizacionDetalle, error) {
+	if (req.NuevoEstado == model.EstadoObservado || req.NuevoEstado == model.EstadoRechazado) && req.Motivo == "" {
+		return nil, fmt.Errorf("el motivo es obligatorio para estados OBSERVADO y RECHAZADO")
+	}
+
+	now := time.Now().UTC()
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// La condición sobre el estado evita pisar un cambio concurrente
+		res, err := tx.Exec(`
+			UPDATE autorizaciones SET estado = $1, fecha_actualizacion = $2
+			WHERE id = $3 AND estado = $4`,
+			req.NuevoEstado, now, id, estadoActual)
+		if err != nil {
+			return fmt.Errorf("error al cambiar estado de autorización: %w", err)
+		}
+		if err := checkRowsAffected(res, ErrEstadoModificado); err != nil {
+			return err
+		}
+
+		// La aprobación guarda el procedimiento aprobado, que ya no depende de ediciones posteriores
+		var procedimientoAprobado string
+		if req.NuevoEstado == model.EstadoAprobado {
+			err = tx.QueryRow(`SELECT procedimiento_codigo FROM autorizaciones WHERE id = $1`, id).Scan(&procedimientoAprobado)
+			if err != nil {
+				return fmt.Errorf("error al obtener procedimiento de autorización: %w", err)
+			}
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO autorizacion_historial (autorizacion_id, estado, usuario, rol, fecha_cambio, motivo, procedimiento_codigo)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			id, req.NuevoEstado, req.Usuario, req.Rol, now, req.Motivo, procedimientoAprobado)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de autorización: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
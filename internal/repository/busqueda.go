@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"strconv"
+	"strings"
+)
+
+// tokensBusqueda separa la query `q` en términos normalizados
+func tokensBusqueda(query string) []string {
+	return strings.Fields(validacion.NormalizarTexto(query))
+}
+
+// textoBusqueda arma el texto normalizado sobre el que se busca
+func textoBusqueda(campos ...string) string {
+	return validacion.NormalizarTexto(strings.Join(campos, " "))
+}
+
+// coincideBusqueda devuelve true si todos los tokens aparecen en el texto (semántica AND)
+func coincideBusqueda(tokens []string, texto string) bool {
+	for _, token := range tokens {
+		if !strings.Contains(texto, token) {
+			return false
+		}
+	}
+	return true
+}
+
+func textoBusquedaAutorizacion(id int, afiliado model.AfiliadoBasico, codigo, procedimiento, especialidad string) string {
+	return textoBusqueda(strconv.Itoa(id), afiliado.DNI, afiliado.Nombre, afiliado.Apellido, codigo, procedimiento, especialidad)
+}
+
+func textoBusquedaReceta(id int, afiliado model.AfiliadoBasico, codigo, medicamento, marca, dosis string) string {
+	return textoBusqueda(strconv.Itoa(id), afiliado.DNI, afiliado.Nombre, afiliado.Apellido, codigo, medicamento, marca, dosis)
+}
+
+func textoBusquedaReintegro(id int, afiliado model.AfiliadoBasico, codigo, prestacion, especialidad, metodo string) string {
+	return textoBusqueda(strconv.Itoa(id), afiliado.DNI, afiliado.Nombre, afiliado.Apellido, codigo, prestacion, especialidad, metodo)
+}
+
+// escapeLike escapa los comodines de LIKE para buscar el token literal
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
+
+// indiceBusqueda describe cómo recalcular texto_busqueda de una tabla a partir de sus columnas
+type indiceBusqueda struct {
+	tabla    string
+	columnas []string
+	texto    func(id int, valores []string) string
+}
+
+var indicesBusqueda = []indiceBusqueda{
+	{
+		tabla:    "afiliados",
+		columnas: []string{"dni", "nro_afiliado", "nombre", "apellido"},
+		texto: func(_ int, v []string) string {
+			return textoBusqueda(v[0], v[1], v[2], v[3])
+		},
+	},
+	{
+		tabla:    "autorizaciones",
+		columnas: []string{"afiliado_dni", "afiliado_nombre", "afiliado_apellido", "procedimiento_codigo", "procedimiento", "especialidad"},
+		texto: func(id int, v []string) string {
+			return textoBusquedaAutorizacion(id, model.AfiliadoBasico{DNI: v[0], Nombre: v[1], Apellido: v[2]}, v[3], v[4], v[5])
+		},
+	},
+	{
+		tabla:    "recetas",
+		columnas: []string{"afiliado_dni", "afiliado_nombre", "afiliado_apellido", "medicamento_codigo", "medicamento", "marca_sugerida", "dosis"},
+		texto: func(id int, v []string) string {
+			return textoBusquedaReceta(id, model.AfiliadoBasico{DNI: v[0], Nombre: v[1], Apellido: v[2]}, v[3], v[4], v[5], v[6])
+		},
+	},
+	{
+		tabla:    "reintegros",
+		columnas: []string{"afiliado_dni", "afiliado_nombre", "afiliado_apellido", "prestacion_codigo", "prestacion", "especialidad", "metodo"},
+		texto: func(id int, v []string) string {
+			return textoBusquedaReintegro(id, model.AfiliadoBasico{DNI: v[0], Nombre: v[1], Apellido: v[2]}, v[3], v[4], v[5], v[6])
+		},
+	},
+}
+
+// IndexarBusquedaPendiente completa texto_busqueda en las filas que lo tienen vacío (las que una migración
+// dejó para volver a indexar) y devuelve cuántas completó. Se calcula acá y no en SQL para usar la misma
+// normalización que las búsquedas.
+func IndexarBusquedaPendiente(db *sql.DB) (int, error) {
+	total := 0
+	for _, indice := range indicesBusqueda {
+		n, err := indice.indexar(db)
+		if err != nil {
+			return total, fmt.Errorf("error al indexar %s: %w", indice.tabla, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (ind indiceBusqueda) indexar(db *sql.DB) (int, error) {
+	type pendiente struct {
+		id    int
+		texto string
+	}
+
+	rows, err := db.Query(`SELECT id, ` + strings.Join(ind.columnas, ", ") + ` FROM ` + ind.tabla + ` WHERE texto_busqueda = ''`)
+	if err != nil {
+		return 0, err
+	}
+	var pendientes []pendiente
+	for rows.Next() {
+		var id int
+		valores := make([]string, len(ind.columnas))
+		dest := []any{&id}
+		for i := range valores {
+			dest = append(dest, &valores[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pendientes = append(pendientes, pendiente{id: id, texto: ind.texto(id, valores)})
+	}
+	// Se cierra antes de escribir: con SQLite la lectura abierta bloquearía los UPDATE
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(pendientes) == 0 {
+		return 0, nil
+	}
+
+	err = withTx(db, func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`UPDATE ` + ind.tabla + ` SET texto_busqueda = $1 WHERE id = $2`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, p := range pendientes {
+			if _, err := stmt.Exec(p.texto, p.id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(pendientes), err
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"prestadores-api/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCuentaCobroNoEncontrada indica que la cuenta no existe o ya fue dada de baja
+	ErrCuentaCobroNoEncontrada = errors.New("cuenta de cobro no encontrada")
+	// ErrCuentaCobroDuplicada indica que el afiliado ya tiene activa una cuenta con el mismo CBU/CVU
+	ErrCuentaCobroDuplicada = errors.New("el afiliado ya tiene registrada esa cuenta")
+)
+
+// CuentaCobroRepository guarda las cuentas donde los afiliados cobran sus reintegros
+type CuentaCobroRepository interface {
+	// GetByAfiliado devuelve las cuentas activas del afiliado en orden de alta
+	GetByAfiliado(afiliadoID int) ([]model.CuentaCobro, error)
+	// GetByID devuelve la cuenta aunque esté dada de baja
+	GetByID(id int) (*model.CuentaCobro, error)
+	Create(cuenta model.CuentaCobro) (*model.CuentaCobro, error)
+	Baja(id int, fecha time.Time) error
+}
+
+type cuentaCobroRepositoryImpl struct {
+	mu      sync.RWMutex
+	cuentas map[int]*model.CuentaCobro
+	nextID  int
+}
+
+func NewCuentaCobroRepository() CuentaCobroRepository {
+	repo := &cuentaCobroRepositoryImpl{
+		cuentas: make(map[int]*model.CuentaCobro),
+		nextID:  1,
+	}
+	repo.initializeDummyData()
+	return repo
+}
+
+// initializeDummyData carga las cuentas de los reintegros de ejemplo que se pagan por transferencia
+func (r *cuentaCobroRepositoryImpl) initializeDummyData() {
+	dummyData := []model.CuentaCobro{
+		{
+			ID:            1,
+			AfiliadoID:    45,
+			Tipo:          model.CuentaCBU,
+			CBU:           "0170099220000067797370",
+			TitularCUIT:   "23213456334",
+			TitularNombre: "Daniela Reynoso",
+			Usuario:       "prestador.202",
+			FechaAlta:     time.Date(2025, 8, 28, 8, 50, 0, 0, time.UTC),
+		},
+		{
+			ID:            2,
+			AfiliadoID:    46,
+			Tipo:          model.CuentaCVU,
+			CBU:           "0000003110000012345671",
+			Alias:         "marcos.ledesma.mp",
+			TitularCUIT:   "20301234563",
+			TitularNombre: "Marcos Ledesma",
+			Usuario:       "prestador.205",
+			FechaAlta:     time.Date(2025, 9, 3, 9, 45, 0, 0, time.UTC),
+		},
+	}
+
+	for _, cuenta := range dummyData {
+		r.cuentas[cuenta.ID] = &cuenta
+	}
+	r.nextID = 3
+}
+
+func (r *cuentaCobroRepositoryImpl) GetByAfiliado(afiliadoID int) ([]model.CuentaCobro, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cuentas := []model.CuentaCobro{}
+	for _, c := range r.cuentas {
+		if c.AfiliadoID == afiliadoID && c.Activa() {
+			cuentas = append(cuentas, *c)
+		}
+	}
+	sort.Slice(cuentas, func(i, j int) bool { return cuentas[i].ID < cuentas[j].ID })
+	return cuentas, nil
+}
+
+func (r *cuentaCobroRepositoryImpl) GetByID(id int) (*model.CuentaCobro, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.cuentas[id]
+	if !ok {
+		return nil, ErrCuentaCobroNoEncontrada
+	}
+	cuenta := *c
+	return &cuenta, nil
+}
+
+func (r *cuentaCobroRepositoryImpl) Create(cuenta model.CuentaCobro) (*model.CuentaCobro, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.cuentas {
+		if c.AfiliadoID == cuenta.AfiliadoID && c.CBU == cuenta.CBU && c.Activa() {
+			return nil, ErrCuentaCobroDuplicada
+		}
+	}
+
+	cuenta.ID = r.nextID
+	r.cuentas[cuenta.ID] = &cuenta
+	r.nextID++
+
+	creada := cuenta
+	return &creada, nil
+}
+
+func (r *cuentaCobroRepositoryImpl) Baja(id int, fecha time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.cuentas[id]
+	if !ok || !c.Activa() {
+		return ErrCuentaCobroNoEncontrada
+	}
+	c.FechaBaja = &fecha
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"time"
+)
+
+type cuentaCobroSQLRepository struct {
+	db *sql.DB
+}
+
+// NewCuentaCobroSQLRepository crea un CuentaCobroRepository persistido en base de datos
+func NewCuentaCobroSQLRepository(db *sql.DB) CuentaCobroRepository {
+	return &cuentaCobroSQLRepository{db: db}
+}
+
+const columnasCuentaCobro = `id, afiliado_id, tipo, cbu, alias, titular_cuit, titular_nombre, usuario, fecha_alta, fecha_baja`
+
+func scanCuentaCobro(row interface{ Scan(...any) error }, c *model.CuentaCobro) error {
+	var fechaBaja sql.NullTime
+	if err := row.Scan(&c.ID, &c.AfiliadoID, &c.Tipo, &c.CBU, &c.Alias, &c.TitularCUIT, &c.TitularNombre,
+		&c.Usuario, &c.FechaAlta, &fechaBaja); err != nil {
+		return err
+	}
+	if fechaBaja.Valid {
+		c.FechaBaja = &fechaBaja.Time
+	}
+	return nil
+}
+
+func (r *cuentaCobroSQLRepository) GetByAfiliado(afiliadoID int) ([]model.CuentaCobro, error) {
+	rows, err := r.db.Query(`
+		SELECT `+columnasCuentaCobro+`
+		FROM cuentas_cobro
+		WHERE afiliado_id = $1 AND fecha_baja IS NULL
+		ORDER BY id`, afiliadoID)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar cuentas de cobro: %w", err)
+	}
+	defer rows.Close()
+
+	cuentas := []model.CuentaCobro{}
+	for rows.Next() {
+		var c model.CuentaCobro
+		if err := scanCuentaCobro(rows, &c); err != nil {
+			return nil, fmt.Errorf("error al leer cuenta de cobro: %w", err)
+		}
+		cuentas = append(cuentas, c)
+	}
+	return cuentas, rows.Err()
+}
+
+func (r *cuentaCobroSQLRepository) GetByID(id int) (*model.CuentaCobro, error) {
+	var c model.CuentaCobro
+	err := scanCuentaCobro(r.db.QueryRow(`
+		SELECT `+columnasCuentaCobro+`
+		FROM cuentas_cobro
+		WHERE id = $1`, id), &c)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCuentaCobroNoEncontrada
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener cuenta de cobro: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *cuentaCobroSQLRepository) Create(cuenta model.CuentaCobro) (*model.CuentaCobro, error) {
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		var existentes int
+		err := tx.QueryRow(`
+			SELECT COUNT(*) FROM cuentas_cobro
+			WHERE afiliado_id = $1 AND cbu = $2 AND fecha_baja IS NULL`,
+			cuenta.AfiliadoID, cuenta.CBU).Scan(&existentes)
+		if err != nil {
+			return fmt.Errorf("error al verificar cuentas de cobro: %w", err)
+		}
+		if existentes > 0 {
+			return ErrCuentaCobroDuplicada
+		}
+
+		err = tx.QueryRow(`
+			INSERT INTO cuentas_cobro (afiliado_id, tipo, cbu, alias, titular_cuit, titular_nombre, usuario, fecha_alta)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			cuenta.AfiliadoID, cuenta.Tipo, cuenta.CBU, cuenta.Alias, cuenta.TitularCUIT, cuenta.TitularNombre,
+			cuenta.Usuario, cuenta.FechaAlta,
+		).Scan(&cuenta.ID)
+		if err != nil {
+			return fmt.Errorf("error al crear cuenta de cobro: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cuenta, nil
+}
+
+func (r *cuentaCobroSQLRepository) Baja(id int, fecha time.Time) error {
+	res, err := r.db.Exec(`
+		UPDATE cuentas_cobro SET fecha_baja = $1
+		WHERE id = $2 AND fecha_baja IS NULL`, fecha, id)
+	if err != nil {
+		return fmt.Errorf("error al dar de baja la cuenta de cobro: %w", err)
+	}
+	return checkRowsAffected(res, ErrCuentaCobroNoEncontrada)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"prestadores-api/internal/model"
+	"strings"
+)
+
+// ErrEspecialidadNoEncontrada indica que el código no figura en el catálogo de especialidades
+var ErrEspecialidadNoEncontrada = errors.New("especialidad no encontrada")
+
+type EspecialidadRepository interface {
+	GetAll() ([]model.Especialidad, error)
+	// GetByCodigo no distingue mayúsculas
+	GetByCodigo(codigo string) (*model.Especialidad, error)
+}
+
+// El catálogo es de solo lectura: no necesita mutex
+type especialidadRepositoryImpl struct {
+	especialidades []model.Especialidad
+	porCodigo      map[string]model.Especialidad
+}
+
+func NewEspecialidadRepository() EspecialidadRepository {
+	repo := &especialidadRepositoryImpl{
+		porCodigo: make(map[string]model.Especialidad),
+	}
+
+	repo.initializeDummyData()
+
+	return repo
+}
+
+// initializeDummyData carga las especialidades del nomenclador y de los planes, más las de consultorio
+func (r *especialidadRepositoryImpl) initializeDummyData() {
+	r.especialidades = []model.Especialidad{
+		{Codigo: "CARDIOLOGIA", Nombre: "Cardiología"},
+		{Codigo: "CLINICA_MEDICA", Nombre: "Clínica Médica"},
+		{Codigo: "DERMATOLOGIA", Nombre: "Dermatología"},
+		{Codigo: "DIAGNOSTICO_IMAGENES", Nombre: "Diagnóstico por Imágenes"},
+		{Codigo: "KINESIOLOGIA", Nombre: "Kinesiología"},
+		{Codigo: "ODONTOLOGIA", Nombre: "Odontología"},
+		{Codigo: "PEDIATRIA", Nombre: "Pediatría"},
+		{Codigo: "TRAUMATOLOGIA", Nombre: "Traumatología"},
+	}
+
+	for _, e := range r.especialidades {
+		r.porCodigo[e.Codigo] = e
+	}
+}
+
+func (r *especialidadRepositoryImpl) GetAll() ([]model.Especialidad, error) {
+	out := make([]model.Especialidad, len(r.especialidades))
+	copy(out, r.especialidades)
+	return out, nil
+}
+
+func (r *especialidadRepositoryImpl) GetByCodigo(codigo string) (*model.Especialidad, error) {
+	e, ok := r.porCodigo[strings.ToUpper(strings.TrimSpace(codigo))]
+	if !ok {
+		return nil, ErrEspecialidadNoEncontrada
+	}
+	return &e, nil
+}
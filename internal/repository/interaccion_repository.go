@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"strings"
+)
+
+// Pares de drogas que interactúan; se reemplaza con INTERACCIONES_CSV
+//
+//go:embed data/interacciones.csv
+var interaccionesDefault []byte
+
+// columnasInteracciones es el encabezado esperado del CSV
+var columnasInteracciones = []string{"droga_a", "droga_b", "severidad", "descripcion"}
+
+type InteraccionRepository interface {
+	// GetByDroga devuelve las interacciones de una droga genérica, sin distinguir mayúsculas ni tildes
+	GetByDroga(droga string) []model.InteraccionMedicamentosa
+}
+
+type interaccionRepositoryImpl struct {
+	porDroga map[string][]model.InteraccionMedicamentosa
+}
+
+// NewInteraccionRepository carga las interacciones desde el CSV de path, o el embebido si path es vacío
+func NewInteraccionRepository(path string) (InteraccionRepository, error) {
+	var r io.Reader = bytes.NewReader(interaccionesDefault)
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error al abrir interacciones: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	interacciones, err := cargarInteracciones(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cada par se indexa por las dos drogas
+	repo := &interaccionRepositoryImpl{porDroga: make(map[string][]model.InteraccionMedicamentosa)}
+	for _, i := range interacciones {
+		a, b := validacion.NormalizarTexto(i.DrogaA), validacion.NormalizarTexto(i.DrogaB)
+		repo.porDroga[a] = append(repo.porDroga[a], i)
+		repo.porDroga[b] = append(repo.porDroga[b], i)
+	}
+	return repo, nil
+}
+
+// cargarInteracciones lee un CSV con encabezado droga_a,droga_b,severidad,descripcion
+func cargarInteracciones(r io.Reader) ([]model.InteraccionMedicamentosa, error) {
+	lector := csv.NewReader(r)
+	lector.FieldsPerRecord = len(columnasInteracciones)
+	lector.TrimLeadingSpace = true
+
+	encabezado, err := lector.Read()
+	if err != nil {
+		return nil, fmt.Errorf("interacciones sin encabezado: %w", err)
+	}
+	for i, col := range columnasInteracciones {
+		if strings.ToLower(strings.TrimSpace(strings.TrimPrefix(encabezado[i], "\ufeff"))) != col {
+			return nil, fmt.Errorf("encabezado de interacciones inválido: se esperaba %s", strings.Join(columnasInteracciones, ","))
+		}
+	}
+
+	var interacciones []model.InteraccionMedicamentosa
+	for {
+		registro, err := lector.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error al leer interacciones: %w", err)
+		}
+		linea, _ := lector.FieldPos(0)
+
+		for i := range registro {
+			registro[i] = strings.TrimSpace(registro[i])
+			if registro[i] == "" {
+				return nil, fmt.Errorf("interacciones, línea %d: la columna %s es obligatoria", linea, columnasInteracciones[i])
+			}
+		}
+
+		i := model.InteraccionMedicamentosa{
+			DrogaA:      registro[0],
+			DrogaB:      registro[1],
+			Severidad:   model.SeveridadInteraccion(strings.ToUpper(registro[2])),
+			Descripcion: registro[3],
+		}
+		switch i.Severidad {
+		case model.SeveridadLeve, model.SeveridadModerada, model.SeveridadGrave:
+		default:
+			return nil, fmt.Errorf("interacciones, línea %d: severidad inválida %q", linea, registro[2])
+		}
+		if validacion.NormalizarTexto(i.DrogaA) == validacion.NormalizarTexto(i.DrogaB) {
+			return nil, fmt.Errorf("interacciones, línea %d: la droga no puede interactuar consigo misma", linea)
+		}
+
+		interacciones = append(interacciones, i)
+	}
+	return interacciones, nil
+}
+
+func (r *interaccionRepositoryImpl) GetByDroga(droga string) []model.InteraccionMedicamentosa {
+	return r.porDroga[validacion.NormalizarTexto(strings.TrimSpace(droga))]
+}
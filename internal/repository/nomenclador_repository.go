@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"bytes"
+	"cmp"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Nomenclador por defecto; se reemplaza con NOMENCLADOR_CSV
+//
+//go:embed data/nomenclador.csv
+var nomencladorDefault []byte
+
+// ErrCodigoNomenclador indica que el código no figura en el nomenclador
+var ErrCodigoNomenclador = errors.New("código no encontrado en el nomenclador")
+
+// columnasNomenclador es el encabezado esperado del CSV
+var columnasNomenclador = []string{"codigo", "descripcion", "especialidad_codigo", "valor_unitario"}
+
+type NomencladorRepository interface {
+	GetByCodigo(codigo string) (*model.PrestacionNomenclador, error)
+	// Buscar matchea el código por prefijo y la descripción por términos, sin distinguir
+	// mayúsculas ni tildes. Devuelve primero los códigos y después las descripciones, junto
+	// con el total de coincidencias antes de aplicar limit.
+	Buscar(query string, especialidadCodigo string, limit int) ([]model.PrestacionNomenclador, int, error)
+}
+
+// El nomenclador es de solo lectura una vez cargado: no necesita mutex
+type nomencladorRepositoryImpl struct {
+	prestaciones []model.PrestacionNomenclador
+	porCodigo    map[string]*model.PrestacionNomenclador
+}
+
+// NewNomencladorRepository carga el nomenclador desde el CSV de path, o el embebido si path es vacío
+func NewNomencladorRepository(path string, especialidades EspecialidadRepository) (NomencladorRepository, error) {
+	var r io.Reader = bytes.NewReader(nomencladorDefault)
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error al abrir nomenclador: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	prestaciones, err := CargarNomenclador(r, especialidades)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &nomencladorRepositoryImpl{
+		prestaciones: prestaciones,
+		porCodigo:    make(map[string]*model.PrestacionNomenclador, len(prestaciones)),
+	}
+	for i := range repo.prestaciones {
+		repo.porCodigo[repo.prestaciones[i].Codigo] = &repo.prestaciones[i]
+	}
+	return repo, nil
+}
+
+// CargarNomenclador lee un CSV con encabezado codigo,descripcion,especialidad_codigo,valor_unitario.
+// Los códigos no se pueden repetir, la especialidad debe estar en el catálogo y el valor unitario
+// usa punto decimal.
+func CargarNomenclador(r io.Reader, especialidades EspecialidadRepository) ([]model.PrestacionNomenclador, error) {
+	lector := csv.NewReader(r)
+	lector.FieldsPerRecord = len(columnasNomenclador)
+	lector.TrimLeadingSpace = true
+
+	encabezado, err := lector.Read()
+	if err != nil {
+		return nil, fmt.Errorf("nomenclador sin encabezado: %w", err)
+	}
+	for i, col := range columnasNomenclador {
+		if strings.ToLower(strings.TrimSpace(strings.TrimPrefix(encabezado[i], "\ufeff"))) != col {
+			return nil, fmt.Errorf("encabezado del nomenclador inválido: se esperaba %s", strings.Join(columnasNomenclador, ","))
+		}
+	}
+
+	var prestaciones []model.PrestacionNomenclador
+	vistos := make(map[string]int)
+	for {
+		registro, err := lector.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error al leer nomenclador: %w", err)
+		}
+		linea, _ := lector.FieldPos(0)
+
+		p := model.PrestacionNomenclador{
+			Codigo:             strings.TrimSpace(registro[0]),
+			Descripcion:        strings.TrimSpace(registro[1]),
+			EspecialidadCodigo: strings.TrimSpace(registro[2]),
+		}
+		if p.Codigo == "" || p.Descripcion == "" || p.EspecialidadCodigo == "" {
+			return nil, fmt.Errorf("nomenclador, línea %d: código, descripción y especialidad son obligatorios", linea)
+		}
+		if anterior, ok := vistos[p.Codigo]; ok {
+			return nil, fmt.Errorf("nomenclador, línea %d: código %s repetido (línea %d)", linea, p.Codigo, anterior)
+		}
+		especialidad, err := especialidades.GetByCodigo(p.EspecialidadCodigo)
+		if err != nil {
+			return nil, fmt.Errorf("nomenclador, línea %d: especialidad %s: %w", linea, p.EspecialidadCodigo, err)
+		}
+		p.EspecialidadCodigo, p.Especialidad = especialidad.Codigo, especialidad.Nombre
+
+		p.ValorUnitario, err = strconv.ParseFloat(strings.TrimSpace(registro[3]), 64)
+		if err != nil || p.ValorUnitario < 0 {
+			return nil, fmt.Errorf("nomenclador, línea %d: valor unitario inválido %q", linea, registro[3])
+		}
+
+		vistos[p.Codigo] = linea
+		prestaciones = append(prestaciones, p)
+	}
+
+	if len(prestaciones) == 0 {
+		return nil, fmt.Errorf("el nomenclador no tiene prestaciones")
+	}
+	return prestaciones, nil
+}
+
+func (r *nomencladorRepositoryImpl) GetByCodigo(codigo string) (*model.PrestacionNomenclador, error) {
+	p, ok := r.porCodigo[strings.TrimSpace(codigo)]
+	if !ok {
+		return nil, ErrCodigoNomenclador
+	}
+	copia := *p
+	return &copia, nil
+}
+
+func (r *nomencladorRepositoryImpl) Buscar(query string, especialidadCodigo string, limit int) ([]model.PrestacionNomenclador, int, error) {
+	tokens := tokensBusqueda(query)
+
+	type resultado struct {
+		prestacion model.PrestacionNomenclador
+		rango      int
+	}
+
+	var resultados []resultado
+	for _, p := range r.prestaciones {
+		if especialidadCodigo != "" && p.EspecialidadCodigo != especialidadCodigo {
+			continue
+		}
+
+		rango, ok := rangoNomenclador(tokens, p)
+		if !ok {
+			continue
+		}
+		resultados = append(resultados, resultado{prestacion: p, rango: rango})
+	}
+
+	slices.SortStableFunc(resultados, func(a, b resultado) int {
+		return cmp.Or(cmp.Compare(a.rango, b.rango), cmp.Compare(a.prestacion.Codigo, b.prestacion.Codigo))
+	})
+
+	total := len(resultados)
+	if limit > 0 && total > limit {
+		resultados = resultados[:limit]
+	}
+
+	items := make([]model.PrestacionNomenclador, 0, len(resultados))
+	for _, res := range resultados {
+		items = append(items, res.prestacion)
+	}
+	return items, total, nil
+}
+
+// rangoNomenclador ordena las coincidencias: 0 código exacto, 1 prefijo de código,
+// 2 descripción que empieza con la búsqueda, 3 resto de coincidencias por términos
+func rangoNomenclador(tokens []string, p model.PrestacionNomenclador) (int, bool) {
+	if len(tokens) == 0 {
+		return 3, true
+	}
+
+	if len(tokens) == 1 {
+		if p.Codigo == tokens[0] {
+			return 0, true
+		}
+		if strings.HasPrefix(p.Codigo, tokens[0]) {
+			return 1, true
+		}
+	}
+
+	descripcion := validacion.NormalizarTexto(p.Descripcion)
+	if !coincideBusqueda(tokens, textoBusqueda(p.Descripcion, p.Especialidad)) {
+		return 0, false
+	}
+	if strings.HasPrefix(descripcion, tokens[0]) {
+		return 2, true
+	}
+	return 3, true
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrOrdenInvalido indica que el parámetro sort referencia un campo no permitido o está mal formado
+var ErrOrdenInvalido = errors.New("parámetro sort inválido")
+
+// campoOrden es un criterio de ordenamiento: "fechaCreacion" (asc) o "-estado" (desc)
+type campoOrden struct {
+	campo string
+	desc  bool
+}
+
+// campoOrdenable define cómo ordenar un campo del listado en memoria y en SQL
+type campoOrdenable[T any] struct {
+	columnas []string
+	comparar func(a, b T) int
+}
+
+// parseOrden interpreta `sort=fechaCreacion,-estado` validando contra los campos permitidos
+func parseOrden[T any](sortParam string, permitidos map[string]campoOrdenable[T]) ([]campoOrden, error) {
+	sortParam = strings.TrimSpace(sortParam)
+	if sortParam == "" {
+		return nil, nil
+	}
+
+	var campos []campoOrden
+	for _, parte := range strings.Split(sortParam, ",") {
+		parte = strings.TrimSpace(parte)
+		c := campoOrden{}
+		switch {
+		case strings.HasPrefix(parte, "-"):
+			c.desc = true
+			parte = parte[1:]
+		case strings.HasPrefix(parte, "+"):
+			parte = parte[1:]
+		}
+
+		if parte == "" {
+			return nil, fmt.Errorf("%w: criterio vacío en %q", ErrOrdenInvalido, sortParam)
+		}
+		if _, ok := permitidos[parte]; !ok {
+			return nil, fmt.Errorf("%w: campo desconocido %q. Campos permitidos: %s",
+				ErrOrdenInvalido, parte, strings.Join(nombresOrden(permitidos), ", "))
+		}
+
+		c.campo = parte
+		campos = append(campos, c)
+	}
+	return campos, nil
+}
+
+func nombresOrden[T any](permitidos map[string]campoOrdenable[T]) []string {
+	nombres := make([]string, 0, len(permitidos))
+	for nombre := range permitidos {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	return nombres
+}
+
+// ordenar aplica los criterios sobre los items en memoria, desempatando siempre por ID
+func ordenar[T any](items []T, campos []campoOrden, permitidos map[string]campoOrdenable[T], id func(T) int) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		for _, c := range campos {
+			r := permitidos[c.campo].comparar(a, b)
+			if c.desc {
+				r = -r
+			}
+			if r != 0 {
+				return r
+			}
+		}
+		return cmp.Compare(id(a), id(b))
+	})
+}
+
+// orderBy arma la cláusula ORDER BY a partir de los criterios, desempatando por id.
+// Las columnas salen de la lista blanca, nunca del request.
+func orderBy[T any](campos []campoOrden, permitidos map[string]campoOrdenable[T]) string {
+	var partes []string
+	for _, c := range campos {
+		dir := "ASC"
+		if c.desc {
+			dir = "DESC"
+		}
+		for _, col := range permitidos[c.campo].columnas {
+			partes = append(partes, col+" "+dir)
+		}
+	}
+	partes = append(partes, "id ASC")
+	return " ORDER BY " + strings.Join(partes, ", ")
+}
+
+func compararAfiliado(a, b model.AfiliadoBasico) int {
+	return cmp.Or(strings.Compare(a.Apellido, b.Apellido), strings.Compare(a.Nombre, b.Nombre))
+}
+
+func compararFecha(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// ===== Campos permitidos por recurso =====
+
+var ordenAutorizaciones = map[string]campoOrdenable[model.AutorizacionListItem]{
+	"id": {[]string{"id"}, func(a, b model.AutorizacionListItem) int { return cmp.Compare(a.ID, b.ID) }},
+	"fechaCreacion": {[]string{"fecha_creacion"}, func(a, b model.AutorizacionListItem) int {
+		return compararFecha(a.FechaCreacion, b.FechaCreacion)
+	}},
+	"fechaActualizacion": {[]string{"fecha_actualizacion"}, func(a, b model.AutorizacionListItem) int {
+		return compararFecha(a.FechaActualizacion, b.FechaActualizacion)
+	}},
+	"estado": {[]string{"estado"}, func(a, b model.AutorizacionListItem) int { return cmp.Compare(a.Estado, b.Estado) }},
+	"afiliado": {[]string{"afiliado_apellido", "afiliado_nombre"}, func(a, b model.AutorizacionListItem) int {
+		return compararAfiliado(a.Afiliado, b.Afiliado)
+	}},
+	"procedimiento": {[]string{"procedimiento"}, func(a, b model.AutorizacionListItem) int {
+		return strings.Compare(a.Procedimiento, b.Procedimiento)
+	}},
+	"especialidad": {[]string{"especialidad"}, func(a, b model.AutorizacionListItem) int {
+		return strings.Compare(a.Especialidad, b.Especialidad)
+	}},
+}
+
+var ordenRecetas = map[string]campoOrdenable[model.RecetaListItem]{
+	"id": {[]string{"id"}, func(a, b model.RecetaListItem) int { return cmp.Compare(a.ID, b.ID) }},
+	"fechaCreacion": {[]string{"fecha_creacion"}, func(a, b model.RecetaListItem) int {
+		return compararFecha(a.FechaCreacion, b.FechaCreacion)
+	}},
+	"fechaActualizacion": {[]string{"fecha_actualizacion"}, func(a, b model.RecetaListItem) int {
+		return compararFecha(a.FechaActualizacion, b.FechaActualizacion)
+	}},
+	"estado": {[]string{"estado"}, func(a, b model.RecetaListItem) int { return cmp.Compare(a.Estado, b.Estado) }},
+	"afiliado": {[]string{"afiliado_apellido", "afiliado_nombre"}, func(a, b model.RecetaListItem) int {
+		return compararAfiliado(a.Afiliado, b.Afiliado)
+	}},
+	"medicamento": {[]string{"medicamento"}, func(a, b model.RecetaListItem) int {
+		return strings.Compare(a.Medicamento, b.Medicamento)
+	}},
+}
+
+var ordenReintegros = map[string]campoOrdenable[model.ReintegroListItem]{
+	"id": {[]string{"id"}, func(a, b model.ReintegroListItem) int { return cmp.Compare(a.ID, b.ID) }},
+	"fechaCreacion": {[]string{"fecha_creacion"}, func(a, b model.ReintegroListItem) int {
+		return compararFecha(a.FechaCreacion, b.FechaCreacion)
+	}},
+	"fechaActualizacion": {[]string{"fecha_actualizacion"}, func(a, b model.ReintegroListItem) int {
+		return compararFecha(a.FechaActualizacion, b.FechaActualizacion)
+	}},
+	"estado": {[]string{"estado"}, func(a, b model.ReintegroListItem) int { return cmp.Compare(a.Estado, b.Estado) }},
+	"afiliado": {[]string{"afiliado_apellido", "afiliado_nombre"}, func(a, b model.ReintegroListItem) int {
+		return compararAfiliado(a.Afiliado, b.Afiliado)
+	}},
+	"prestacion": {[]string{"prestacion"}, func(a, b model.ReintegroListItem) int {
+		return strings.Compare(a.Prestacion, b.Prestacion)
+	}},
+	"metodo": {[]string{"metodo"}, func(a, b model.ReintegroListItem) int { return strings.Compare(string(a.Metodo), string(b.Metodo)) }},
+	"monto":  {[]string{"monto_centavos"}, func(a, b model.ReintegroListItem) int { return cmp.Compare(a.Monto, b.Monto) }},
+	"montoReconocido": {[]string{"monto_reconocido_centavos"}, func(a, b model.ReintegroListItem) int {
+		return cmp.Compare(a.MontoReconocido, b.MontoReconocido)
+	}},
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPlanNoEncontrado indica que el ID no corresponde a ningún plan del catálogo
+	ErrPlanNoEncontrado = errors.New("plan no encontrado")
+	// ErrPlanExistente indica que ya hay un plan con ese nombre
+	ErrPlanExistente = errors.New("ya existe un plan con ese nombre")
+	// ErrPrestacionNoCubierta indica que el plan no incluye la prestación
+	ErrPrestacionNoCubierta = errors.New("prestación no cubierta por el plan")
+	// ErrCoberturaDuplicada indica que el plan repite una prestación (sin distinguir mayúsculas ni tildes)
+	ErrCoberturaDuplicada = errors.New("prestación repetida en el plan")
+)
+
+type PlanRepository interface {
+	GetAll() ([]model.PlanMedico, error)
+	GetByID(id int) (*model.PlanMedico, error)
+	// GetCobertura busca la prestación sin distinguir mayúsculas ni tildes
+	GetCobertura(planID int, prestacion string) (*model.CoberturaPrestacion, error)
+	Create(req model.PlanMedicoRequest) (*model.PlanMedico, error)
+	Update(id int, req model.PlanMedicoRequest) (*model.PlanMedico, error)
+	Delete(id int) error
+}
+
+type planRepositoryImpl struct {
+	mu     sync.RWMutex
+	planes map[int]*model.PlanMedico
+	nextID int
+}
+
+func NewPlanRepository() PlanRepository {
+	repo := &planRepositoryImpl{
+		planes: make(map[int]*model.PlanMedico),
+		nextID: 1,
+	}
+
+	repo.initializeDummyData()
+
+	return repo
+}
+
+// initializeDummyData carga los planes a los que apuntan los afiliados del padrón
+func (r *planRepositoryImpl) initializeDummyData() {
+	desde := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dummyData := []model.PlanMedico{
+		{
+			ID:            1,
+			Nombre:        "Sancor Salud",
+			VigenciaDesde: desde,
+			Coberturas: []model.CoberturaPrestacion{
+				{Prestacion: "Clínica Médica", PorcentajeCobertura: 100},
+				{Prestacion: "Cardiología", PorcentajeCobertura: 100, Copago: 2500_00},
+				{Prestacion: "Diagnóstico por Imágenes", PorcentajeCobertura: 80, RequiereAutorizacion: true, CarenciaDias: 30},
+				{Prestacion: "Kinesiología", PorcentajeCobertura: 70, TopeAnual: 150000_00, Copago: 2000_00, CarenciaDias: 60},
+				{Prestacion: "Odontología", PorcentajeCobertura: 50, TopeAnual: 120000_00, CarenciaDias: 90},
+				{Prestacion: "Traumatología", PorcentajeCobertura: 100, Copago: 2500_00},
+				{Prestacion: model.PrestacionMedicamentos, PorcentajeCobertura: 40},
+			},
+		},
+		{
+			ID:            2,
+			Nombre:        "Galeno 210",
+			VigenciaDesde: desde,
+			Coberturas: []model.CoberturaPrestacion{
+				{Prestacion: "Clínica Médica", PorcentajeCobertura: 100},
+				{Prestacion: "Cardiología", PorcentajeCobertura: 90, Copago: 3000_00},
+				{Prestacion: "Diagnóstico por Imágenes", PorcentajeCobertura: 70, RequiereAutorizacion: true, CarenciaDias: 60},
+				{Prestacion: "Kinesiología", PorcentajeCobertura: 60, TopeAnual: 100000_00, Copago: 2500_00, CarenciaDias: 90},
+				{Prestacion: model.PrestacionMedicamentos, PorcentajeCobertura: 40},
+			},
+		},
+		{
+			ID:            3,
+			Nombre:        "Swiss Medical",
+			VigenciaDesde: desde,
+			Coberturas: []model.CoberturaPrestacion{
+				{Prestacion: "Clínica Médica", PorcentajeCobertura: 100},
+				{Prestacion: "Cardiología", PorcentajeCobertura: 100},
+				{Prestacion: "Diagnóstico por Imágenes", PorcentajeCobertura: 100},
+				{Prestacion: "Kinesiología", PorcentajeCobertura: 80, TopeAnual: 250000_00, CarenciaDias: 30},
+				{Prestacion: "Odontología", PorcentajeCobertura: 70, TopeAnual: 200000_00, RequiereAutorizacion: true, CarenciaDias: 180},
+				{Prestacion: "Traumatología", PorcentajeCobertura: 100},
+				{Prestacion: model.PrestacionMedicamentos, PorcentajeCobertura: 50, CarenciaDias: 30},
+			},
+		},
+	}
+
+	for _, p := range dummyData {
+		r.planes[p.ID] = &p
+	}
+	r.nextID = 4
+}
+
+func (r *planRepositoryImpl) GetAll() ([]model.PlanMedico, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	planes := make([]model.PlanMedico, 0, len(r.planes))
+	for _, p := range r.planes {
+		planes = append(planes, copiarPlan(p))
+	}
+
+	slices.SortFunc(planes, func(a, b model.PlanMedico) int { return cmp.Compare(a.ID, b.ID) })
+
+	return planes, nil
+}
+
+func (r *planRepositoryImpl) GetByID(id int) (*model.PlanMedico, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.planes[id]
+	if !exists {
+		return nil, ErrPlanNoEncontrado
+	}
+
+	copia := copiarPlan(p)
+	return &copia, nil
+}
+
+func (r *planRepositoryImpl) GetCobertura(planID int, prestacion string) (*model.CoberturaPrestacion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.planes[planID]
+	if !exists {
+		return nil, ErrPlanNoEncontrado
+	}
+
+	buscada := validacion.NormalizarTexto(strings.TrimSpace(prestacion))
+	for _, c := range p.Coberturas {
+		if validacion.NormalizarTexto(c.Prestacion) == buscada {
+			copia := c
+			return &copia, nil
+		}
+	}
+
+	return nil, ErrPrestacionNoCubierta
+}
+
+func (r *planRepositoryImpl) Create(req model.PlanMedicoRequest) (*model.PlanMedico, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nombreEnUso(req.Nombre, 0) {
+		return nil, ErrPlanExistente
+	}
+	if err := validarCoberturasUnicas(req.Coberturas); err != nil {
+		return nil, err
+	}
+
+	p := &model.PlanMedico{ID: r.nextID}
+	aplicarPlanRequest(p, req)
+
+	r.planes[p.ID] = p
+	r.nextID++
+
+	copia := copiarPlan(p)
+	return &copia, nil
+}
+
+func (r *planRepositoryImpl) Update(id int, req model.PlanMedicoRequest) (*model.PlanMedico, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, exists := r.planes[id]
+	if !exists {
+		return nil, ErrPlanNoEncontrado
+	}
+
+	if r.nombreEnUso(req.Nombre, id) {
+		return nil, ErrPlanExistente
+	}
+	if err := validarCoberturasUnicas(req.Coberturas); err != nil {
+		return nil, err
+	}
+
+	aplicarPlanRequest(p, req)
+
+	copia := copiarPlan(p)
+	return &copia, nil
+}
+
+func (r *planRepositoryImpl) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.planes[id]; !exists {
+		return ErrPlanNoEncontrado
+	}
+	delete(r.planes, id)
+	return nil
+}
+
+// nombreEnUso compara sin tildes ni mayúsculas, ignorando el plan que se está editando
+func (r *planRepositoryImpl) nombreEnUso(nombre string, excluirID int) bool {
+	buscado := validacion.NormalizarTexto(strings.TrimSpace(nombre))
+	for _, p := range r.planes {
+		if p.ID != excluirID && validacion.NormalizarTexto(p.Nombre) == buscado {
+			return true
+		}
+	}
+	return false
+}
+
+func validarCoberturasUnicas(coberturas []model.CoberturaPrestacion) error {
+	vistas := make(map[string]bool, len(coberturas))
+	for _, c := range coberturas {
+		clave := validacion.NormalizarTexto(strings.TrimSpace(c.Prestacion))
+		if vistas[clave] {
+			return fmt.Errorf("%w: %s", ErrCoberturaDuplicada, c.Prestacion)
+		}
+		vistas[clave] = true
+	}
+	return nil
+}
+
+func aplicarPlanRequest(p *model.PlanMedico, req model.PlanMedicoRequest) {
+	p.Nombre = strings.TrimSpace(req.Nombre)
+	p.VigenciaDesde = req.VigenciaDesde
+	p.VigenciaHasta = req.VigenciaHasta
+	p.Coberturas = append([]model.CoberturaPrestacion(nil), req.Coberturas...)
+}
+
+func copiarPlan(p *model.PlanMedico) model.PlanMedico {
+	copia := *p
+	copia.Coberturas = append([]model.CoberturaPrestacion(nil), p.Coberturas...)
+	return copia
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"strings"
+)
+
+type planSQLRepository struct {
+	db *sql.DB
+}
+
+// NewPlanSQLRepository crea un PlanRepository persistido en base de datos
+func NewPlanSQLRepository(db *sql.DB) PlanRepository {
+	return &planSQLRepository{db: db}
+}
+
+const columnasCobertura = `prestacion, porcentaje_cobertura, tope_anual_centavos, copago_centavos, requiere_autorizacion, carencia_dias`
+
+// scanCobertura lee columnasCobertura seguidas de las columnas extra que haya en la consulta
+func scanCobertura(row interface{ Scan(...any) error }, c *model.CoberturaPrestacion, extra ...any) error {
+	dest := append([]any{&c.Prestacion, &c.PorcentajeCobertura, &c.TopeAnual, &c.Copago, &c.RequiereAutorizacion, &c.CarenciaDias}, extra...)
+	return row.Scan(dest...)
+}
+
+func (r *planSQLRepository) GetAll() ([]model.PlanMedico, error) {
+	rows, err := r.db.Query(`SELECT id, nombre, vigencia_desde, vigencia_hasta FROM planes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar planes: %w", err)
+	}
+	defer rows.Close()
+
+	planes := make([]model.PlanMedico, 0)
+	posicion := map[int]int{}
+	for rows.Next() {
+		var p model.PlanMedico
+		if err := scanPlan(rows, &p); err != nil {
+			return nil, fmt.Errorf("error al leer plan: %w", err)
+		}
+		p.Coberturas = []model.CoberturaPrestacion{}
+		posicion[p.ID] = len(planes)
+		planes = append(planes, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Las coberturas de todos los planes en una sola consulta
+	cob, err := r.db.Query(`SELECT ` + columnasCobertura + `, plan_id FROM plan_coberturas ORDER BY plan_id, orden`)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener coberturas: %w", err)
+	}
+	defer cob.Close()
+
+	for cob.Next() {
+		var (
+			planID int
+			c      model.CoberturaPrestacion
+		)
+		if err := scanCobertura(cob, &c, &planID); err != nil {
+			return nil, fmt.Errorf("error al leer cobertura: %w", err)
+		}
+		if i, ok := posicion[planID]; ok {
+			planes[i].Coberturas = append(planes[i].Coberturas, c)
+		}
+	}
+	return planes, cob.Err()
+}
+
+func (r *planSQLRepository) GetByID(id int) (*model.PlanMedico, error) {
+	var p model.PlanMedico
+	err := scanPlan(r.db.QueryRow(`SELECT id, nombre, vigencia_desde, vigencia_hasta FROM planes WHERE id = $1`, id), &p)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPlanNoEncontrado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener plan: %w", err)
+	}
+
+	rows, err := r.db.Query(`SELECT `+columnasCobertura+` FROM plan_coberturas WHERE plan_id = $1 ORDER BY orden`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener coberturas: %w", err)
+	}
+	defer rows.Close()
+
+	p.Coberturas = []model.CoberturaPrestacion{}
+	for rows.Next() {
+		var c model.CoberturaPrestacion
+		if err := scanCobertura(rows, &c); err != nil {
+			return nil, fmt.Errorf("error al leer cobertura: %w", err)
+		}
+		p.Coberturas = append(p.Coberturas, c)
+	}
+	return &p, rows.Err()
+}
+
+func (r *planSQLRepository) GetCobertura(planID int, prestacion string) (*model.CoberturaPrestacion, error) {
+	var c model.CoberturaPrestacion
+	err := scanCobertura(r.db.QueryRow(`
+		SELECT `+columnasCobertura+`
+		FROM plan_coberturas
+		WHERE plan_id = $1 AND prestacion_normalizada = $2`,
+		planID, validacion.NormalizarTexto(strings.TrimSpace(prestacion))), &c)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Distingue plan inexistente de prestación no cubierta, como en memoria
+		var existe int
+		if err := r.db.QueryRow(`SELECT COUNT(*) FROM planes WHERE id = $1`, planID).Scan(&existe); err != nil {
+			return nil, fmt.Errorf("error al obtener plan: %w", err)
+		}
+		if existe == 0 {
+			return nil, ErrPlanNoEncontrado
+		}
+		return nil, ErrPrestacionNoCubierta
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener cobertura: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *planSQLRepository) Create(req model.PlanMedicoRequest) (*model.PlanMedico, error) {
+	if err := validarCoberturasUnicas(req.Coberturas); err != nil {
+		return nil, err
+	}
+
+	var id int
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		if err := nombrePlanEnUso(tx, req.Nombre, 0); err != nil {
+			return err
+		}
+
+		nombre := strings.TrimSpace(req.Nombre)
+		err := tx.QueryRow(`
+			INSERT INTO planes (nombre, nombre_normalizado, vigencia_desde, vigencia_hasta)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id`,
+			nombre, validacion.NormalizarTexto(nombre), req.VigenciaDesde, req.VigenciaHasta,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error al crear plan: %w", err)
+		}
+		return insertarCoberturas(tx, id, req.Coberturas)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Update reemplaza el plan completo, incluidas las coberturas
+func (r *planSQLRepository) Update(id int, req model.PlanMedicoRequest) (*model.PlanMedico, error) {
+	if err := validarCoberturasUnicas(req.Coberturas); err != nil {
+		return nil, err
+	}
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		if err := nombrePlanEnUso(tx, req.Nombre, id); err != nil {
+			return err
+		}
+
+		nombre := strings.TrimSpace(req.Nombre)
+		res, err := tx.Exec(`
+			UPDATE planes SET nombre = $1, nombre_normalizado = $2, vigencia_desde = $3, vigencia_hasta = $4
+			WHERE id = $5`,
+			nombre, validacion.NormalizarTexto(nombre), req.VigenciaDesde, req.VigenciaHasta, id)
+		if err != nil {
+			return fmt.Errorf("error al actualizar plan: %w", err)
+		}
+		if err := checkRowsAffected(res, ErrPlanNoEncontrado); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM plan_coberturas WHERE plan_id = $1`, id); err != nil {
+			return fmt.Errorf("error al reemplazar coberturas: %w", err)
+		}
+		return insertarCoberturas(tx, id, req.Coberturas)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *planSQLRepository) Delete(id int) error {
+	// Las coberturas se borran explícitamente: SQLite solo aplica ON DELETE CASCADE con foreign_keys activado
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM plan_coberturas WHERE plan_id = $1`, id); err != nil {
+			return fmt.Errorf("error al eliminar coberturas: %w", err)
+		}
+		res, err := tx.Exec(`DELETE FROM planes WHERE id = $1`, id)
+		if err != nil {
+			return fmt.Errorf("error al eliminar plan: %w", err)
+		}
+		return checkRowsAffected(res, ErrPlanNoEncontrado)
+	})
+}
+
+func scanPlan(row interface{ Scan(...any) error }, p *model.PlanMedico) error {
+	var hasta sql.NullTime
+	if err := row.Scan(&p.ID, &p.Nombre, &p.VigenciaDesde, &hasta); err != nil {
+		return err
+	}
+	if hasta.Valid {
+		p.VigenciaHasta = &hasta.Time
+	}
+	return nil
+}
+
+// nombrePlanEnUso compara sin tildes ni mayúsculas, ignorando el plan que se está editando
+func nombrePlanEnUso(tx *sql.Tx, nombre string, excluirID int) error {
+	var existentes int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM planes WHERE nombre_normalizado = $1 AND id <> $2`,
+		validacion.NormalizarTexto(strings.TrimSpace(nombre)), excluirID).Scan(&existentes)
+	if err != nil {
+		return fmt.Errorf("error al verificar planes: %w", err)
+	}
+	if existentes > 0 {
+		return ErrPlanExistente
+	}
+	return nil
+}
+
+func insertarCoberturas(tx *sql.Tx, planID int, coberturas []model.CoberturaPrestacion) error {
+	for i, c := range coberturas {
+		prestacion := strings.TrimSpace(c.Prestacion)
+		_, err := tx.Exec(`
+			INSERT INTO plan_coberturas (plan_id, orden, prestacion, prestacion_normalizada, porcentaje_cobertura,
+				tope_anual_centavos, copago_centavos, requiere_autorizacion, carencia_dias)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			planID, i+1, prestacion, validacion.NormalizarTexto(prestacion), c.PorcentajeCobertura,
+			c.TopeAnual, c.Copago, c.RequiereAutorizacion, c.CarenciaDias)
+		if err != nil {
+			return fmt.Errorf("error al registrar cobertura %q: %w", prestacion, err)
+		}
+	}
+	return nil
+}
@@ -1,18 +1,88 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"prestadores-api/internal/model"
+	"slices"
 	"sync"
 	"time"
 )
 
+// Errores de la receta electrónica
+var (
+	ErrCodigoVerificacionNoEncontrado = errors.New("código de verificación inexistente")
+	ErrRecetaNoDispensable            = errors.New("la receta no está aprobada, ya fue dispensada o venció")
+	ErrEnvasesExcedidos               = errors.New("la cantidad de envases supera los pendientes de dispensar")
+)
+
 type RecetaRepository interface {
-	GetAll(estado string, query string, page int, size int, sort string) ([]model.RecetaListItem, int, error)
+	GetAll(estado string, vigencia model.VigenciaReceta, query string, page int, size int, sort string) ([]model.RecetaListItem, int, error)
 	GetByID(id int) (*model.RecetaDetalle, error)
+	GetByCodigoVerificacion(codigo string) (*model.RecetaDetalle, error)
+	// GetActivasByAfiliado devuelve las recetas del afiliado en curso o vigentes a la fecha (las que
+	// no fueron rechazadas, dispensadas por completo ni vencieron) y además las creadas desde
+	// creadasDesde en cualquier estado salvo RECHAZADO
+	GetActivasByAfiliado(afiliadoID int, ahora, creadasDesde time.Time) ([]model.RecetaListItem, error)
 	Create(req model.CreateRecetaRequest) (*model.RecetaDetalle, error)
+	// Update aplica los cambios solo si la receta sigue en un estado editable (RECIBIDO u OBSERVADO);
+	// si no devuelve ErrEstadoModificado
 	Update(id int, req model.UpdateRecetaRequest) error
-	CambiarEstado(id int, req model.CambioEstadoRecetaRequest) (*model.RecetaDetalle, error)
+	// CambiarEstado aplica el cambio solo si sigue en estadoActual; si no devuelve ErrEstadoModificado
+	CambiarEstado(id int, estadoActual model.EstadoReceta, req model.CambioEstadoRecetaRequest) (*model.RecetaDetalle, error)
+	// AsignarCodigoVerificacion guarda el código solo si la receta no tenía uno y devuelve la receta actualizada
+	AsignarCodigoVerificacion(id int, codigo string) (*model.RecetaDetalle, error)
+	// Dispensar registra una entrega de dispensa.Envases sobre los envasesPrescriptos y pasa la receta a
+	// DISPENSADA o PARCIALMENTE_DISPENSADA. Si la receta no admite más entregas devuelve ErrRecetaNoDispensable
+	// y si pide más envases de los pendientes, ErrEnvasesExcedidos.
+	Dispensar(id int, dispensa model.DispensaReceta, envasesPrescriptos int) (*model.RecetaDetalle, error)
+	// Vencer pasa a VENCIDA las recetas vigentes con fecha de vencimiento anterior a ahora y devuelve sus IDs
+	Vencer(ahora time.Time) ([]int, error)
+}
+
+// estadosRecetaEnCurso son los estados previos a la aprobación, que todavía pueden terminar en una receta vigente
+var estadosRecetaEnCurso = []model.EstadoReceta{model.RecetaEstadoRecibido, model.RecetaEstadoEnAnalisis, model.RecetaEstadoObservado}
+
+// estadosRecetaEditables son los estados en los que el prestador todavía puede corregir la receta
+var estadosRecetaEditables = []model.EstadoReceta{model.RecetaEstadoRecibido, model.RecetaEstadoObservado}
+
+// coincideVigencia aplica el filtro ?vigencia= del listado
+func coincideVigencia(rec *model.RecetaDetalle, vigencia model.VigenciaReceta, ahora time.Time) bool {
+	switch vigencia {
+	case model.VigenciaVigente:
+		return rec.Vigente(ahora)
+	case model.VigenciaPorVencer:
+		return rec.Vigente(ahora) && rec.FechaVencimiento != nil &&
+			!rec.FechaVencimiento.After(ahora.AddDate(0, 0, model.DiasRecetaPorVencer))
+	case model.VigenciaVencida:
+		if rec.Estado == model.RecetaEstadoVencida {
+			return true
+		}
+		// Vencida pero todavía no la procesó el job de vencimiento
+		return (rec.Estado == model.RecetaEstadoAprobado || rec.Estado == model.RecetaEstadoParcialmenteDispensada) &&
+			rec.FechaVencimiento != nil && !rec.FechaVencimiento.After(ahora)
+	}
+	return true
+}
+
+// historialDispensa es la entrada del historial que registra una entrega en farmacia
+func historialDispensa(estado model.EstadoReceta, dispensa model.DispensaReceta, envasesPrescriptos int) model.HistorialEstadoReceta {
+	return model.HistorialEstadoReceta{
+		Estado:      estado,
+		Usuario:     "farmacia." + dispensa.FarmaciaCUIT,
+		FechaCambio: dispensa.Fecha,
+		Motivo:      fmt.Sprintf("Dispensa de %d de %d envases", dispensa.Envases, envasesPrescriptos),
+	}
+}
+
+// historialVencimiento es la entrada del historial que deja el vencimiento automático
+func historialVencimiento(vencimiento time.Time, ahora time.Time) model.HistorialEstadoReceta {
+	return model.HistorialEstadoReceta{
+		Estado:      model.RecetaEstadoVencida,
+		Usuario:     usuarioOSistema(""),
+		FechaCambio: ahora,
+		Motivo:      "Vencida el " + vencimiento.Format("02/01/2006"),
+	}
 }
 
 type recetaRepositoryImpl struct {
@@ -33,6 +103,9 @@ func NewRecetaRepository() RecetaRepository {
 }
 
 func (r *recetaRepositoryImpl) initializeDummyData() {
+	// 9350 se aprobó antes de la vigencia y no vence; 9353 está vencida y la pasa a VENCIDA el job al arrancar
+	vencimiento9353 := time.Date(2025, 10, 20, 10, 0, 0, 0, time.UTC)
+
 	dummyData := []model.RecetaDetalle{
 		{
 			ID:                 9350,
@@ -46,8 +119,17 @@ func (r *recetaRepositoryImpl) initializeDummyData() {
 				Nombre:   "Miguel",
 				Apellido: "Osorio",
 			},
-			Medicamento: "Amoxicilina 500mg",
-			Dosis:       "1 cap. c/8h x 7d",
+			Prestador: model.PrestadorBasico{
+				ID:     101,
+				CUIT:   "20301112220",
+				Nombre: "Carlos Medina",
+			},
+			MedicamentoCodigo:  "100101",
+			DrogaGenerica:      "Amoxicilina",
+			Medicamento:        "Amoxicilina 500 mg cápsulas x 16",
+			MarcaSugerida:      "Amoxidal",
+			Dosis:              "1 cap. c/8h x 7d",
+			CodigoVerificacion: "7KQ2-M9XD-4HTP",
 			Historial: []model.HistorialEstadoReceta{
 				{
 					Estado:      model.RecetaEstadoRecibido,
@@ -78,8 +160,15 @@ func (r *recetaRepositoryImpl) initializeDummyData() {
 				Nombre:   "Ana",
 				Apellido: "Fernández",
 			},
-			Medicamento: "Ibuprofeno 600mg",
-			Dosis:       "1 comp. c/8h",
+			Prestador: model.PrestadorBasico{
+				ID:     102,
+				CUIT:   "27312223339",
+				Nombre: "Sofía Herrera",
+			},
+			MedicamentoCodigo: "100203",
+			DrogaGenerica:     "Ibuprofeno",
+			Medicamento:       "Ibuprofeno 600 mg comprimidos x 20",
+			Dosis:             "1 comp. c/8h",
 			Historial: []model.HistorialEstadoReceta{
 				{
 					Estado:      model.RecetaEstadoRecibido,
@@ -106,8 +195,15 @@ func (r *recetaRepositoryImpl) initializeDummyData() {
 				Nombre:   "Roberto",
 				Apellido: "Díaz",
 			},
-			Medicamento: "Omeprazol 20mg",
-			Dosis:       "1 cap. c/12h x 30d",
+			Prestador: model.PrestadorBasico{
+				ID:     103,
+				CUIT:   "20334445551",
+				Nombre: "Andrés Villalba",
+			},
+			MedicamentoCodigo: "100301",
+			DrogaGenerica:     "Omeprazol",
+			Medicamento:       "Omeprazol 20 mg cápsulas x 14",
+			Dosis:             "1 cap. c/12h x 30d",
 			Historial: []model.HistorialEstadoReceta{
 				{
 					Estado:      model.RecetaEstadoRecibido,
@@ -121,27 +217,96 @@ func (r *recetaRepositoryImpl) initializeDummyData() {
 				},
 			},
 		},
+		{
+			ID:                 9353,
+			Tipo:               model.TipoReceta,
+			Estado:             model.RecetaEstadoParcialmenteDispensada,
+			FechaCreacion:      time.Date(2025, 9, 18, 9, 0, 0, 0, time.UTC),
+			FechaActualizacion: time.Date(2025, 9, 22, 17, 45, 0, 0, time.UTC),
+			Afiliado: model.AfiliadoBasico{
+				ID:       22,
+				DNI:      "32654708",
+				Nombre:   "Miguel",
+				Apellido: "Osorio",
+			},
+			Prestador: model.PrestadorBasico{
+				ID:     101,
+				CUIT:   "20301112220",
+				Nombre: "Carlos Medina",
+			},
+			MedicamentoCodigo: "100301",
+			DrogaGenerica:     "Omeprazol",
+			Medicamento:       "Omeprazol 20 mg cápsulas x 14",
+			Dosis:             "1 cápsula c/24h x 28d vía oral",
+			Posologia: &model.Posologia{
+				Cantidad:        1,
+				Unidad:          model.UnidadCapsula,
+				FrecuenciaHoras: 24,
+				DuracionDias:    28,
+				Via:             model.ViaOral,
+				Envases:         2,
+			},
+			CodigoVerificacion: "P3WN-8RTA-J6CE",
+			FechaVencimiento:   &vencimiento9353,
+			Dispensas: []model.DispensaReceta{
+				{
+					Fecha:        time.Date(2025, 9, 22, 17, 45, 0, 0, time.UTC),
+					FarmaciaCUIT: "30708889993",
+					Envases:      1,
+				},
+			},
+			Historial: []model.HistorialEstadoReceta{
+				{
+					Estado:      model.RecetaEstadoRecibido,
+					Usuario:     "prestador.101",
+					FechaCambio: time.Date(2025, 9, 18, 9, 0, 0, 0, time.UTC),
+				},
+				{
+					Estado:      model.RecetaEstadoAprobado,
+					Usuario:     "prestador.101",
+					FechaCambio: time.Date(2025, 9, 20, 10, 0, 0, 0, time.UTC),
+				},
+				{
+					Estado:      model.RecetaEstadoParcialmenteDispensada,
+					Usuario:     "farmacia.30708889993",
+					FechaCambio: time.Date(2025, 9, 22, 17, 45, 0, 0, time.UTC),
+					Motivo:      "Dispensa de 1 de 2 envases",
+				},
+			},
+		},
 	}
 
 	for _, rec := range dummyData {
 		r.recetas[rec.ID] = &rec
 	}
 
-	r.nextID = 9353
+	r.nextID = 9354
 }
 
-func (r *recetaRepositoryImpl) GetAll(estado string, query string, page int, size int, sort string) ([]model.RecetaListItem, int, error) {
+func (r *recetaRepositoryImpl) GetAll(estado string, vigencia model.VigenciaReceta, query string, page int, size int, sort string) ([]model.RecetaListItem, int, error) {
+	orden, err := parseOrden(sort, ordenRecetas)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	tokens := tokensBusqueda(query)
+	ahora := time.Now()
+
 	var items []model.RecetaListItem
 	for _, rec := range r.recetas {
 		if estado != "" && string(rec.Estado) != estado {
 			continue
 		}
 
-		if query != "" {
-			// TODO: implementar búsqueda
+		if vigencia != "" && !coincideVigencia(rec, vigencia, ahora) {
+			continue
+		}
+
+		if len(tokens) > 0 && !coincideBusqueda(tokens, textoBusquedaReceta(rec.ID, rec.Afiliado, rec.MedicamentoCodigo, rec.Medicamento, rec.MarcaSugerida, rec.Dosis)) {
+			continue
 		}
 
 		item := model.RecetaListItem{
@@ -151,12 +316,17 @@ func (r *recetaRepositoryImpl) GetAll(estado string, query string, page int, siz
 			Estado:             rec.Estado,
 			FechaCreacion:      rec.FechaCreacion,
 			FechaActualizacion: rec.FechaActualizacion,
+			FechaVencimiento:   rec.FechaVencimiento,
+			MedicamentoCodigo:  rec.MedicamentoCodigo,
+			DrogaGenerica:      rec.DrogaGenerica,
 			Medicamento:        rec.Medicamento,
 			Dosis:              rec.Dosis,
 		}
 		items = append(items, item)
 	}
 
+	ordenar(items, orden, ordenRecetas, func(i model.RecetaListItem) int { return i.ID })
+
 	total := len(items)
 
 	start := page * size
@@ -185,6 +355,50 @@ func (r *recetaRepositoryImpl) GetByID(id int) (*model.RecetaDetalle, error) {
 	return rec, nil
 }
 
+func (r *recetaRepositoryImpl) GetByCodigoVerificacion(codigo string) (*model.RecetaDetalle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rec := range r.recetas {
+		if codigo != "" && rec.CodigoVerificacion == codigo {
+			return rec, nil
+		}
+	}
+	return nil, ErrCodigoVerificacionNoEncontrado
+}
+
+func (r *recetaRepositoryImpl) GetActivasByAfiliado(afiliadoID int, ahora, creadasDesde time.Time) ([]model.RecetaListItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var items []model.RecetaListItem
+	for _, rec := range r.recetas {
+		if rec.Afiliado.ID != afiliadoID {
+			continue
+		}
+		reciente := rec.Estado != model.RecetaEstadoRechazado && !rec.FechaCreacion.Before(creadasDesde)
+		if !slices.Contains(estadosRecetaEnCurso, rec.Estado) && !rec.Vigente(ahora) && !reciente {
+			continue
+		}
+		items = append(items, model.RecetaListItem{
+			ID:                 rec.ID,
+			Tipo:               rec.Tipo,
+			Afiliado:           rec.Afiliado,
+			Estado:             rec.Estado,
+			FechaCreacion:      rec.FechaCreacion,
+			FechaActualizacion: rec.FechaActualizacion,
+			FechaVencimiento:   rec.FechaVencimiento,
+			MedicamentoCodigo:  rec.MedicamentoCodigo,
+			DrogaGenerica:      rec.DrogaGenerica,
+			Medicamento:        rec.Medicamento,
+			Dosis:              rec.Dosis,
+		})
+	}
+
+	slices.SortFunc(items, func(a, b model.RecetaListItem) int { return a.ID - b.ID })
+	return items, nil
+}
+
 func (r *recetaRepositoryImpl) Create(req model.CreateRecetaRequest) (*model.RecetaDetalle, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -202,19 +416,21 @@ func (r *recetaRepositoryImpl) Create(req model.CreateRecetaRequest) (*model.Rec
 		Estado:             estadoInicial,
 		FechaCreacion:      now,
 		FechaActualizacion: now,
-		Afiliado: model.AfiliadoBasico{
-			ID:       req.AfiliadoID,
-			DNI:      "dummy-dni",
-			Nombre:   "Dummy",
-			Apellido: "Afiliado",
-		},
-		Medicamento: req.Medicamento,
-		Dosis:       req.Dosis,
+		Afiliado:           req.Afiliado,
+		Prestador:          req.Prestador,
+		MedicamentoCodigo:  req.MedicamentoCodigo,
+		DrogaGenerica:      req.DrogaGenerica,
+		Medicamento:        req.Medicamento,
+		MarcaSugerida:      req.MarcaSugerida,
+		Dosis:              req.Dosis,
+		Posologia:          req.Posologia,
 		Historial: []model.HistorialEstadoReceta{
 			{
 				Estado:      estadoInicial,
-				Usuario:     "sistema",
+				Usuario:     usuarioOSistema(req.Usuario),
+				Rol:         req.Rol,
 				FechaCambio: now,
+				Motivo:      req.MotivoAlta,
 			},
 		},
 	}
@@ -233,21 +449,41 @@ func (r *recetaRepositoryImpl) Update(id int, req model.UpdateRecetaRequest) err
 	if !exists {
 		return fmt.Errorf("receta no encontrada")
 	}
+	if !slices.Contains(estadosRecetaEditables, rec.Estado) {
+		return ErrEstadoModificado
+	}
 
-	if req.Medicamento != "" {
+	if req.MedicamentoCodigo != "" {
+		rec.MedicamentoCodigo = req.MedicamentoCodigo
+		rec.DrogaGenerica = req.DrogaGenerica
 		rec.Medicamento = req.Medicamento
+		rec.MarcaSugerida = req.MarcaSugerida
 	}
 
+	// La dosis y la posología cambian juntas
 	if req.Dosis != "" {
 		rec.Dosis = req.Dosis
+		rec.Posologia = req.Posologia
 	}
 
-	rec.FechaActualizacion = time.Now()
+	now := time.Now()
+	rec.FechaActualizacion = now
+
+	// Un cambio de medicamento forzado queda en el historial, sin cambiar el estado
+	if req.MotivoForzado != "" {
+		rec.Historial = append(rec.Historial, model.HistorialEstadoReceta{
+			Estado:      rec.Estado,
+			Usuario:     usuarioOSistema(req.Usuario),
+			Rol:         req.Rol,
+			FechaCambio: now,
+			Motivo:      req.MotivoForzado,
+		})
+	}
 
 	return nil
 }
 
-func (r *recetaRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRecetaRequest) (*model.RecetaDetalle, error) {
+func (r *recetaRepositoryImpl) CambiarEstado(id int, estadoActual model.EstadoReceta, req model.CambioEstadoRecetaRequest) (*model.RecetaDetalle, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -255,6 +491,9 @@ func (r *recetaRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRecet
 	if !exists {
 		return nil, fmt.Errorf("receta no encontrada")
 	}
+	if rec.Estado != estadoActual {
+		return nil, ErrEstadoModificado
+	}
 
 	if (req.NuevoEstado == model.RecetaEstadoObservado || req.NuevoEstado == model.RecetaEstadoRechazado) && req.Motivo == "" {
 		return nil, fmt.Errorf("el motivo es obligatorio para estados OBSERVADO y RECHAZADO")
@@ -264,10 +503,14 @@ func (r *recetaRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRecet
 
 	rec.Estado = req.NuevoEstado
 	rec.FechaActualizacion = now
+	if req.FechaVencimiento != nil {
+		rec.FechaVencimiento = req.FechaVencimiento
+	}
 
 	historial := model.HistorialEstadoReceta{
 		Estado:      req.NuevoEstado,
 		Usuario:     req.Usuario,
+		Rol:         req.Rol,
 		FechaCambio: now,
 		Motivo:      req.Motivo,
 	}
@@ -276,3 +519,67 @@ func (r *recetaRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRecet
 
 	return rec, nil
 }
+
+func (r *recetaRepositoryImpl) AsignarCodigoVerificacion(id int, codigo string) (*model.RecetaDetalle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, exists := r.recetas[id]
+	if !exists {
+		return nil, fmt.Errorf("receta no encontrada")
+	}
+
+	if rec.CodigoVerificacion == "" {
+		rec.CodigoVerificacion = codigo
+	}
+	return rec, nil
+}
+
+func (r *recetaRepositoryImpl) Dispensar(id int, dispensa model.DispensaReceta, envasesPrescriptos int) (*model.RecetaDetalle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, exists := r.recetas[id]
+	if !exists {
+		return nil, fmt.Errorf("receta no encontrada")
+	}
+
+	if !rec.Vigente(dispensa.Fecha) {
+		return nil, ErrRecetaNoDispensable
+	}
+	dispensados := rec.EnvasesDispensados()
+	if dispensa.Envases <= 0 || dispensados+dispensa.Envases > envasesPrescriptos {
+		return nil, ErrEnvasesExcedidos
+	}
+
+	estado := model.RecetaEstadoDispensada
+	if dispensados+dispensa.Envases < envasesPrescriptos {
+		estado = model.RecetaEstadoParcialmenteDispensada
+	}
+
+	rec.Dispensas = append(rec.Dispensas, dispensa)
+	rec.Estado = estado
+	rec.FechaActualizacion = dispensa.Fecha
+	rec.Historial = append(rec.Historial, historialDispensa(estado, dispensa, envasesPrescriptos))
+	return rec, nil
+}
+
+func (r *recetaRepositoryImpl) Vencer(ahora time.Time) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var vencidas []int
+	for _, rec := range r.recetas {
+		if rec.FechaVencimiento == nil || rec.Vigente(ahora) {
+			continue
+		}
+		if rec.Estado != model.RecetaEstadoAprobado && rec.Estado != model.RecetaEstadoParcialmenteDispensada {
+			continue
+		}
+		rec.Estado = model.RecetaEstadoVencida
+		rec.FechaActualizacion = ahora
+		rec.Historial = append(rec.Historial, historialVencimiento(*rec.FechaVencimiento, ahora))
+		vencidas = append(vencidas, rec.ID)
+	}
+	return vencidas, nil
+}
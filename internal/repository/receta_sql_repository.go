@@ -0,0 +1,543 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"time"
+)
+
+type recetaSQLRepository struct {
+	db *sql.DB
+}
+
+// NewRecetaSQLRepository crea un RecetaRepository persistido en base de datos
+func NewRecetaSQLRepository(db *sql.DB) RecetaRepository {
+	return &recetaSQLRepository{db: db}
+}
+
+func (r *recetaSQLRepository) GetAll(estado string, vigencia model.VigenciaReceta, query string, page int, size int, sort string) ([]model.RecetaListItem, int, error) {
+	orden, err := parseOrden(sort, ordenRecetas)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	w := &sqlWhere{}
+	if estado != "" {
+		w.add("estado = ?", estado)
+	}
+	addVigencia(w, vigencia, time.Now().UTC())
+	w.addBusqueda("texto_busqueda", query)
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM recetas"+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar recetas: %w", err)
+	}
+
+	where := w.String()
+	limit, offset := w.arg(size), w.arg(page*size)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion, fecha_vencimiento,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       medicamento_codigo, droga_generica, medicamento, dosis
+		FROM recetas%s%s
+		LIMIT %s OFFSET %s`, where, orderBy(orden, ordenRecetas), limit, offset), w.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al listar recetas: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.RecetaListItem
+	for rows.Next() {
+		item := model.RecetaListItem{Tipo: model.TipoReceta}
+		var vencimiento sql.NullTime
+		if err := rows.Scan(
+			&item.ID, &item.Estado, &item.FechaCreacion, &item.FechaActualizacion, &vencimiento,
+			&item.Afiliado.ID, &item.Afiliado.DNI, &item.Afiliado.Nombre, &item.Afiliado.Apellido,
+			&item.MedicamentoCodigo, &item.DrogaGenerica, &item.Medicamento, &item.Dosis,
+		); err != nil {
+			return nil, 0, fmt.Errorf("error al leer receta: %w", err)
+		}
+		if vencimiento.Valid {
+			item.FechaVencimiento = &vencimiento.Time
+		}
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+// addVigencia agrega el filtro ?vigencia=; es el equivalente SQL de coincideVigencia
+func addVigencia(w *sqlWhere, vigencia model.VigenciaReceta, ahora time.Time) {
+	dispensable := fmt.Sprintf("estado IN ('%s', '%s')", model.RecetaEstadoAprobado, model.RecetaEstadoParcialmenteDispensada)
+	switch vigencia {
+	case model.VigenciaVigente:
+		w.add(dispensable+" AND (fecha_vencimiento IS NULL OR fecha_vencimiento > ?)", ahora)
+	case model.VigenciaPorVencer:
+		w.add(dispensable+" AND fecha_vencimiento > ? AND fecha_vencimiento <= ?",
+			ahora, ahora.AddDate(0, 0, model.DiasRecetaPorVencer))
+	case model.VigenciaVencida:
+		w.add(fmt.Sprintf("(estado = '%s' OR (%s AND fecha_vencimiento <= ?))", model.RecetaEstadoVencida, dispensable), ahora)
+	}
+}
+
+func (r *recetaSQLRepository) GetByID(id int) (*model.RecetaDetalle, error) {
+	return r.getBy("id = $1", id, fmt.Errorf("receta no encontrada"))
+}
+
+func (r *recetaSQLRepository) GetByCodigoVerificacion(codigo string) (*model.RecetaDetalle, error) {
+	if codigo == "" {
+		return nil, ErrCodigoVerificacionNoEncontrado
+	}
+	return r.getBy("codigo_verificacion = $1", codigo, ErrCodigoVerificacionNoEncontrado)
+}
+
+// getBy lee la receta que cumple cond (con un único placeholder $1) y su historial
+func (r *recetaSQLRepository) getBy(cond string, arg any, notFound error) (*model.RecetaDetalle, error) {
+	rec := &model.RecetaDetalle{Tipo: model.TipoReceta}
+	var (
+		posologia   model.Posologia
+		vencimiento sql.NullTime
+	)
+
+	err := r.db.QueryRow(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       prestador_id, prestador_cuit, prestador_nombre,
+		       medicamento_codigo, droga_generica, medicamento, marca_sugerida, dosis,
+		       dosis_cantidad, dosis_unidad, frecuencia_horas, duracion_dias, via_administracion, envases,
+		       codigo_verificacion, fecha_vencimiento
+		FROM recetas
+		WHERE `+cond, arg).Scan(
+		&rec.ID, &rec.Estado, &rec.FechaCreacion, &rec.FechaActualizacion,
+		&rec.Afiliado.ID, &rec.Afiliado.DNI, &rec.Afiliado.Nombre, &rec.Afiliado.Apellido,
+		&rec.Prestador.ID, &rec.Prestador.CUIT, &rec.Prestador.Nombre,
+		&rec.MedicamentoCodigo, &rec.DrogaGenerica, &rec.Medicamento, &rec.MarcaSugerida, &rec.Dosis,
+		&posologia.Cantidad, &posologia.Unidad, &posologia.FrecuenciaHoras, &posologia.DuracionDias, &posologia.Via, &posologia.Envases,
+		&rec.CodigoVerificacion, &vencimiento,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener receta: %w", err)
+	}
+	if posologia.Unidad != "" {
+		rec.Posologia = &posologia
+	}
+	if vencimiento.Valid {
+		rec.FechaVencimiento = &vencimiento.Time
+	}
+
+	dispensas, err := r.db.Query(`
+		SELECT fecha, farmacia_cuit, envases
+		FROM receta_dispensas
+		WHERE receta_id = $1
+		ORDER BY fecha, id`, rec.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener dispensas de receta: %w", err)
+	}
+	defer dispensas.Close()
+
+	for dispensas.Next() {
+		var d model.DispensaReceta
+		if err := dispensas.Scan(&d.Fecha, &d.FarmaciaCUIT, &d.Envases); err != nil {
+			return nil, fmt.Errorf("error al leer dispensa de receta: %w", err)
+		}
+		rec.Dispensas = append(rec.Dispensas, d)
+	}
+	if err := dispensas.Err(); err != nil {
+		return nil, fmt.Errorf("error al leer dispensas de receta: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT estado, usuario, rol, fecha_cambio, motivo
+		FROM receta_historial
+		WHERE receta_id = $1
+		ORDER BY fecha_cambio, id`, rec.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de receta: %w", err)
+	}
+	defer rows.Close()
+
+	rec.Historial = []model.HistorialEstadoReceta{}
+	for rows.Next() {
+		var h model.HistorialEstadoReceta
+		if err := rows.Scan(&h.Estado, &h.Usuario, &h.Rol, &h.FechaCambio, &h.Motivo); err != nil {
+			return nil, fmt.Errorf("error al leer historial de receta: %w", err)
+		}
+		rec.Historial = append(rec.Historial, h)
+	}
+
+	return rec, rows.Err()
+}
+
+func (r *recetaSQLRepository) GetActivasByAfiliado(afiliadoID int, ahora, creadasDesde time.Time) ([]model.RecetaListItem, error) {
+	w := &sqlWhere{}
+	w.add("afiliado_id = ?", afiliadoID)
+	w.add(fmt.Sprintf("(estado IN ('%s', '%s', '%s') OR (estado IN ('%s', '%s') AND (fecha_vencimiento IS NULL OR fecha_vencimiento > ?))"+
+		" OR (estado <> '%s' AND fecha_creacion >= ?))",
+		model.RecetaEstadoRecibido, model.RecetaEstadoEnAnalisis, model.RecetaEstadoObservado,
+		model.RecetaEstadoAprobado, model.RecetaEstadoParcialmenteDispensada, model.RecetaEstadoRechazado),
+		ahora.UTC(), creadasDesde.UTC())
+
+	rows, err := r.db.Query(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion, fecha_vencimiento,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       medicamento_codigo, droga_generica, medicamento, dosis
+		FROM recetas`+w.String()+`
+		ORDER BY id`, w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener recetas activas: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.RecetaListItem
+	for rows.Next() {
+		item := model.RecetaListItem{Tipo: model.TipoReceta}
+		var vencimiento sql.NullTime
+		if err := rows.Scan(
+			&item.ID, &item.Estado, &item.FechaCreacion, &item.FechaActualizacion, &vencimiento,
+			&item.Afiliado.ID, &item.Afiliado.DNI, &item.Afiliado.Nombre, &item.Afiliado.Apellido,
+			&item.MedicamentoCodigo, &item.DrogaGenerica, &item.Medicamento, &item.Dosis,
+		); err != nil {
+			return nil, fmt.Errorf("error al leer receta: %w", err)
+		}
+		if vencimiento.Valid {
+			item.FechaVencimiento = &vencimiento.Time
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *recetaSQLRepository) Create(req model.CreateRecetaRequest) (*model.RecetaDetalle, error) {
+	estadoInicial := req.EstadoInicial
+	if estadoInicial == "" {
+		estadoInicial = model.RecetaEstadoRecibido
+	}
+
+	now := time.Now().UTC()
+	afiliado := req.Afiliado
+	p := columnasPosologia(req.Posologia)
+
+	var id int
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			INSERT INTO recetas (estado, fecha_creacion, fecha_actualizacion,
+				afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+				prestador_id, prestador_cuit, prestador_nombre,
+				medicamento_codigo, droga_generica, medicamento, marca_sugerida, dosis,
+				dosis_cantidad, dosis_unidad, frecuencia_horas, duracion_dias, via_administracion, envases)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+			RETURNING id`,
+			estadoInicial, now, now,
+			afiliado.ID, afiliado.DNI, afiliado.Nombre, afiliado.Apellido,
+			req.Prestador.ID, req.Prestador.CUIT, req.Prestador.Nombre,
+			req.MedicamentoCodigo, req.DrogaGenerica, req.Medicamento, req.MarcaSugerida, req.Dosis,
+			p.Cantidad, p.Unidad, p.FrecuenciaHoras, p.DuracionDias, p.Via, p.Envases,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error al crear receta: %w", err)
+		}
+
+		_, err = tx.Exec("UPDATE recetas SET texto_busqueda = $1 WHERE id = $2",
+			textoBusquedaReceta(id, afiliado, req.MedicamentoCodigo, req.Medicamento, req.MarcaSugerida, req.Dosis), id)
+		if err != nil {
+			return fmt.Errorf("error al indexar receta: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO receta_historial (receta_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, estadoInicial, usuarioOSistema(req.Usuario), req.Rol, now, req.MotivoAlta)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de receta: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *recetaSQLRepository) Update(id int, req model.UpdateRecetaRequest) error {
+	now := time.Now().UTC()
+
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var (
+			estado      model.EstadoReceta
+			afiliado    model.AfiliadoBasico
+			codigo      string
+			droga       string
+			medicamento string
+			marca       string
+			dosis       string
+			posologia   model.Posologia
+		)
+		err := tx.QueryRow(`
+			SELECT estado, afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+			       medicamento_codigo, droga_generica, medicamento, marca_sugerida, dosis,
+			       dosis_cantidad, dosis_unidad, frecuencia_horas, duracion_dias, via_administracion, envases
+			FROM recetas WHERE id = $1`, id).Scan(
+			&estado, &afiliado.ID, &afiliado.DNI, &afiliado.Nombre, &afiliado.Apellido,
+			&codigo, &droga, &medicamento, &marca, &dosis,
+			&posologia.Cantidad, &posologia.Unidad, &posologia.FrecuenciaHoras, &posologia.DuracionDias, &posologia.Via, &posologia.Envases,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("receta no encontrada")
+		}
+		if err != nil {
+			return fmt.Errorf("error al obtener receta: %w", err)
+		}
+
+		if req.MedicamentoCodigo != "" {
+			codigo = req.MedicamentoCodigo
+			droga = req.DrogaGenerica
+			medicamento = req.Medicamento
+			marca = req.MarcaSugerida
+		}
+		if req.Dosis != "" {
+			// La dosis y la posología cambian juntas
+			dosis = req.Dosis
+			posologia = columnasPosologia(req.Posologia)
+		}
+
+		res, err := tx.Exec(`
+			UPDATE recetas SET
+				medicamento_codigo = $1,
+				droga_generica = $2,
+				medicamento = $3,
+				marca_sugerida = $4,
+				dosis = $5,
+				dosis_cantidad = $6,
+				dosis_unidad = $7,
+				frecuencia_horas = $8,
+				duracion_dias = $9,
+				via_administracion = $10,
+				envases = $11,
+				texto_busqueda = $12,
+				fecha_actualizacion = $13
+			WHERE id = $14 AND estado IN ($15, $16)`,
+			codigo, droga, medicamento, marca, dosis,
+			posologia.Cantidad, posologia.Unidad, posologia.FrecuenciaHoras, posologia.DuracionDias, posologia.Via, posologia.Envases,
+			textoBusquedaReceta(id, afiliado, codigo, medicamento, marca, dosis), now, id,
+			model.RecetaEstadoRecibido, model.RecetaEstadoObservado)
+		if err != nil {
+			return fmt.Errorf("error al actualizar receta: %w", err)
+		}
+		// Si el auditor la tomó entre la lectura y la escritura no se pisa lo que está auditando
+		if err := checkRowsAffected(res, ErrEstadoModificado); err != nil {
+			return err
+		}
+
+		// Un cambio de medicamento forzado queda en el historial, sin cambiar el estado
+		if req.MotivoForzado == "" {
+			return nil
+		}
+		_, err = tx.Exec(`
+			INSERT INTO receta_historial (receta_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, estado, usuarioOSistema(req.Usuario), req.Rol, now, req.MotivoForzado)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de receta: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *recetaSQLRepository) CambiarEstado(id int, estadoActual model.EstadoReceta, req model.CambioEstadoRecetaRequest) (*model.RecetaDetalle, error) {
+	if (req.NuevoEstado == model.RecetaEstadoObservado || req.NuevoEstado == model.RecetaEstadoRechazado) && req.Motivo == "" {
+		return nil, fmt.Errorf("el motivo es obligatorio para estados OBSERVADO y RECHAZADO")
+	}
+
+	now := time.Now().UTC()
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// Sin fecha de vencimiento nueva se conserva la que tenía
+		res, err := tx.Exec(`
+			UPDATE recetas SET estado = $1, fecha_actualizacion = $2,
+				fecha_vencimiento = COALESCE($3, fecha_vencimiento)
+			WHERE id = $4 AND estado = $5`,
+			req.NuevoEstado, now, req.FechaVencimiento, id, estadoActual)
+		if err != nil {
+			return fmt.Errorf("error al cambiar estado de receta: %w", err)
+		}
+		if err := checkRowsAffected(res, ErrEstadoModificado); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO receta_historial (receta_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, req.NuevoEstado, req.Usuario, req.Rol, now, req.Motivo)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de receta: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// columnasPosologia devuelve los valores a guardar; sin posología quedan en cero y la unidad vacía
+func columnasPosologia(p *model.Posologia) model.Posologia {
+	if p == nil {
+		return model.Posologia{}
+	}
+	return *p
+}
+
+func (r *recetaSQLRepository) AsignarCodigoVerificacion(id int, codigo string) (*model.RecetaDetalle, error) {
+	// Si ya tenía código no se pisa: se devuelve la receta con el código existente
+	_, err := r.db.Exec(`
+		UPDATE recetas SET codigo_verificacion = $1
+		WHERE id = $2 AND codigo_verificacion = ''`, codigo, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al asignar código de verificación: %w", err)
+	}
+	return r.GetByID(id)
+}
+
+func (r *recetaSQLRepository) Dispensar(id int, dispensa model.DispensaReceta, envasesPrescriptos int) (*model.RecetaDetalle, error) {
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		var (
+			estado      model.EstadoReceta
+			vencimiento sql.NullTime
+			dispensados int
+		)
+		err := tx.QueryRow(`
+			SELECT r.estado, r.fecha_vencimiento,
+			       COALESCE((SELECT SUM(d.envases) FROM receta_dispensas d WHERE d.receta_id = r.id), 0)
+			FROM recetas r WHERE r.id = $1`, id).Scan(&estado, &vencimiento, &dispensados)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("receta no encontrada")
+		}
+		if err != nil {
+			return fmt.Errorf("error al obtener receta: %w", err)
+		}
+
+		rec := &model.RecetaDetalle{Estado: estado}
+		if vencimiento.Valid {
+			rec.FechaVencimiento = &vencimiento.Time
+		}
+		if !rec.Vigente(dispensa.Fecha) {
+			return ErrRecetaNoDispensable
+		}
+		if dispensa.Envases <= 0 || dispensados+dispensa.Envases > envasesPrescriptos {
+			return ErrEnvasesExcedidos
+		}
+
+		nuevoEstado := model.RecetaEstadoDispensada
+		if dispensados+dispensa.Envases < envasesPrescriptos {
+			nuevoEstado = model.RecetaEstadoParcialmenteDispensada
+		}
+
+		// La condición sobre el estado evita pisar una dispensa o un vencimiento concurrente
+		res, err := tx.Exec(`
+			UPDATE recetas SET estado = $1, fecha_actualizacion = $2
+			WHERE id = $3 AND estado = $4`,
+			nuevoEstado, dispensa.Fecha, id, estado)
+		if err != nil {
+			return fmt.Errorf("error al dispensar receta: %w", err)
+		}
+		if err := checkRowsAffected(res, ErrRecetaNoDispensable); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO receta_dispensas (receta_id, fecha, farmacia_cuit, envases)
+			VALUES ($1, $2, $3, $4)`,
+			id, dispensa.Fecha, dispensa.FarmaciaCUIT, dispensa.Envases)
+		if err != nil {
+			return fmt.Errorf("error al registrar dispensa de receta: %w", err)
+		}
+
+		h := historialDispensa(nuevoEstado, dispensa, envasesPrescriptos)
+		_, err = tx.Exec(`
+			INSERT INTO receta_historial (receta_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, h.Estado, h.Usuario, h.Rol, h.FechaCambio, h.Motivo)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de receta: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *recetaSQLRepository) Vencer(ahora time.Time) ([]int, error) {
+	ahora = ahora.UTC()
+	var (
+		candidatas []int
+		vencidas   []int
+	)
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT id, fecha_vencimiento FROM recetas
+			WHERE estado IN ($1, $2) AND fecha_vencimiento <= $3`,
+			model.RecetaEstadoAprobado, model.RecetaEstadoParcialmenteDispensada, ahora)
+		if err != nil {
+			return fmt.Errorf("error al buscar recetas vencidas: %w", err)
+		}
+		vencimientos := map[int]time.Time{}
+		for rows.Next() {
+			var (
+				id          int
+				vencimiento time.Time
+			)
+			if err := rows.Scan(&id, &vencimiento); err != nil {
+				rows.Close()
+				return fmt.Errorf("error al leer receta vencida: %w", err)
+			}
+			candidatas = append(candidatas, id)
+			vencimientos[id] = vencimiento
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error al buscar recetas vencidas: %w", err)
+		}
+
+		for _, id := range candidatas {
+			// Una dispensa o un cambio de estado concurrente pudo sacarla de vigencia después del SELECT:
+			// en ese caso no se toca ni se registra en el historial
+			res, err := tx.Exec(`
+				UPDATE recetas SET estado = $1, fecha_actualizacion = $2
+				WHERE id = $3 AND estado IN ($4, $5)`,
+				model.RecetaEstadoVencida, ahora, id, model.RecetaEstadoAprobado, model.RecetaEstadoParcialmenteDispensada)
+			if err != nil {
+				return fmt.Errorf("error al vencer receta: %w", err)
+			}
+			if err := checkRowsAffected(res, ErrEstadoModificado); errors.Is(err, ErrEstadoModificado) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("error al vencer receta: %w", err)
+			}
+			vencidas = append(vencidas, id)
+
+			h := historialVencimiento(vencimientos[id], ahora)
+			_, err = tx.Exec(`
+				INSERT INTO receta_historial (receta_id, estado, usuario, rol, fecha_cambio, motivo)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				id, h.Estado, h.Usuario, h.Rol, h.FechaCambio, h.Motivo)
+			if err != nil {
+				return fmt.Errorf("error al registrar historial de receta: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vencidas, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"sync"
+	"time"
+)
+
+// ErrRefreshTokenRevocado indica que el token no existe o ya fue revocado por otro request
+var ErrRefreshTokenRevocado = errors.New("refresh token inexistente o ya revocado")
+
+type RefreshTokenRepository interface {
+	Save(token model.RefreshToken) error
+	GetByHash(hash string) (*model.RefreshToken, error)
+	// Revoke revoca el token solo si seguía vigente; si no devuelve ErrRefreshTokenRevocado. Así dos
+	// refresh simultáneos con el mismo token no pueden emitir dos pares de tokens.
+	Revoke(hash string) error
+}
+
+type refreshTokenRepositoryImpl struct {
+	mu     sync.Mutex
+	tokens map[string]*model.RefreshToken
+}
+
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{
+		tokens: make(map[string]*model.RefreshToken),
+	}
+}
+
+func (r *refreshTokenRepositoryImpl) Save(token model.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Aprovechamos para descartar tokens vencidos
+	now := time.Now()
+	for hash, t := range r.tokens {
+		if now.After(t.ExpiraEn) {
+			delete(r.tokens, hash)
+		}
+	}
+
+	r.tokens[token.Hash] = &token
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) GetByHash(hash string) (*model.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, exists := r.tokens[hash]
+	if !exists {
+		return nil, fmt.Errorf("refresh token no encontrado")
+	}
+
+	copia := *t
+	return &copia, nil
+}
+
+func (r *refreshTokenRepositoryImpl) Revoke(hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, exists := r.tokens[hash]
+	if !exists || t.Revocado {
+		return ErrRefreshTokenRevocado
+	}
+
+	t.Revocado = true
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"time"
+)
+
+type refreshTokenSQLRepository struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenSQLRepository crea un RefreshTokenRepository persistido en base de datos
+func NewRefreshTokenSQLRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenSQLRepository{db: db}
+}
+
+func (r *refreshTokenSQLRepository) Save(token model.RefreshToken) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		// Aprovechamos para descartar tokens vencidos
+		if _, err := tx.Exec(`DELETE FROM refresh_tokens WHERE expira_en < $1`, time.Now().UTC()); err != nil {
+			return fmt.Errorf("error al depurar refresh tokens: %w", err)
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO refresh_tokens (hash, usuario_id, creado_en, expira_en)
+			VALUES ($1, $2, $3, $4)`,
+			token.Hash, token.UsuarioID, token.CreadoEn.UTC(), token.ExpiraEn.UTC())
+		if err != nil {
+			return fmt.Errorf("error al guardar refresh token: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *refreshTokenSQLRepository) GetByHash(hash string) (*model.RefreshToken, error) {
+	var (
+		t          model.RefreshToken
+		revocadoEn sql.NullTime
+	)
+	err := r.db.QueryRow(`
+		SELECT hash, usuario_id, creado_en, expira_en, revocado_en
+		FROM refresh_tokens WHERE hash = $1`, hash).Scan(&t.Hash, &t.UsuarioID, &t.CreadoEn, &t.ExpiraEn, &revocadoEn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("refresh token no encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener refresh token: %w", err)
+	}
+	t.Revocado = revocadoEn.Valid
+	return &t, nil
+}
+
+func (r *refreshTokenSQLRepository) Revoke(hash string) error {
+	res, err := r.db.Exec(`
+		UPDATE refresh_tokens SET revocado_en = $1
+		WHERE hash = $2 AND revocado_en IS NULL`, time.Now().UTC(), hash)
+	if err != nil {
+		return fmt.Errorf("error al revocar refresh token: %w", err)
+	}
+	return checkRowsAffected(res, ErrRefreshTokenRevocado)
+}
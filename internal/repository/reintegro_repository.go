@@ -3,16 +3,37 @@ package repository
 import (
 	"fmt"
 	"prestadores-api/internal/model"
+	"slices"
 	"sync"
 	"time"
 )
 
+// FacturaEnUsoError indica que otro reintegro no RECHAZADO ya presentó la factura
+type FacturaEnUsoError struct {
+	ReintegroID int
+}
+
+func (e *FacturaEnUsoError) Error() string {
+	return fmt.Sprintf("la factura ya se presentó en el reintegro %d", e.ReintegroID)
+}
+
 type ReintegroRepository interface {
-	GetAll(estado string, query string, page int, size int, sort string) ([]model.ReintegroListItem, int, error)
+	GetAll(estado string, especialidad string, query string, page int, size int, sort string) ([]model.ReintegroListItem, int, error)
 	GetByID(id int) (*model.ReintegroDetalle, error)
+	// Create devuelve *FacturaEnUsoError si la factura ya está en uso, controlado en la misma operación
+	// que el alta para que dos pedidos simultáneos no puedan presentar la misma factura
 	Create(req model.CreateReintegroRequest) (*model.ReintegroDetalle, error)
+	// Update aplica los cambios solo si el reintegro sigue en un estado editable (RECIBIDO u OBSERVADO);
+	// si no devuelve ErrEstadoModificado
 	Update(id int, req model.UpdateReintegroRequest) error
-	CambiarEstado(id int, req model.CambioEstadoRequest) (*model.ReintegroDetalle, error)
+	// CambiarEstado aplica el cambio solo si sigue en estadoActual; si no devuelve ErrEstadoModificado
+	CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.ReintegroDetalle, error)
+	// MontoReconocidoAnual suma lo reconocido al afiliado por la especialidad en el año,
+	// sin contar los RECHAZADOS ni el reintegro excluirID (0 = ninguno)
+	MontoReconocidoAnual(afiliadoID int, especialidad string, anio int, excluirID int) (model.Monto, error)
+	// FacturaEnUso devuelve el reintegro no RECHAZADO que ya usa la factura (mismo emisor, tipo,
+	// punto de venta y número), o 0 si no hay ninguno
+	FacturaEnUso(factura model.Factura) (int, error)
 }
 
 type reintegroRepositoryImpl struct {
@@ -26,7 +47,7 @@ func NewReintegroRepository() ReintegroRepository {
 		reintegros: make(map[int]*model.ReintegroDetalle),
 		nextID:     8801,
 	}
-	
+
 	repo.initializeDummyData()
 
 	return repo
@@ -46,9 +67,29 @@ func (r *reintegroRepositoryImpl) initializeDummyData() {
 				Nombre:   "Daniela",
 				Apellido: "Reynoso",
 			},
-			Prestacion: "Kinesiología",
-			Metodo:     "Credito",
-			Monto:      40000,
+			Prestador: model.PrestadorBasico{
+				ID:     202,
+				CUIT:   "27314567892",
+				Nombre: "Laura Gómez",
+			},
+			PrestacionCodigo:   "250101",
+			Prestacion:         "Sesión de kinesiología",
+			EspecialidadCodigo: "KINESIOLOGIA",
+			Especialidad:       "Kinesiología",
+			Metodo:             model.MetodoTransferencia,
+			CuentaCobroID:      intPtr(1),
+			Moneda:             model.MonedaARS,
+			Monto:              40000_00,
+			MontoReconocido:    32000_00,
+			Factura: &model.Factura{
+				Tipo:       model.FacturaB,
+				PuntoVenta: 3,
+				Numero:     12345,
+				CUITEmisor: "30712345671",
+				Fecha:      "2025-08-27",
+				CAE:        "75341234567890",
+				Importe:    40000_00,
+			},
 			Historial: []model.HistorialEstado{
 				{
 					Estado:      model.EstadoRecibido,
@@ -75,9 +116,29 @@ func (r *reintegroRepositoryImpl) initializeDummyData() {
 				Nombre:   "Marcos",
 				Apellido: "Ledesma",
 			},
-			Prestacion: "Estudio diagnóstico",
-			Metodo:     "Debito",
-			Monto:      55000,
+			Prestador: model.PrestadorBasico{
+				ID:     205,
+				CUIT:   "27295556667",
+				Nombre: "Paula Acosta",
+			},
+			PrestacionCodigo:   "340201",
+			Prestacion:         "Radiografía de tórax",
+			EspecialidadCodigo: "DIAGNOSTICO_IMAGENES",
+			Especialidad:       "Diagnóstico por Imágenes",
+			Metodo:             model.MetodoTransferencia,
+			CuentaCobroID:      intPtr(2),
+			Moneda:             model.MonedaARS,
+			Monto:              55000_00,
+			MontoReconocido:    38500_00,
+			Factura: &model.Factura{
+				Tipo:       model.FacturaB,
+				PuntoVenta: 1,
+				Numero:     884,
+				CUITEmisor: "30709988774",
+				Fecha:      "2025-09-02",
+				CAE:        "75359876543210",
+				Importe:    55000_00,
+			},
 			Historial: []model.HistorialEstado{
 				{
 					Estado:      model.EstadoRecibido,
@@ -108,9 +169,28 @@ func (r *reintegroRepositoryImpl) initializeDummyData() {
 				Nombre:   "Lucía",
 				Apellido: "Fernández",
 			},
-			Prestacion: "Consulta clínica",
-			Metodo:     "Efectivo",
-			Monto:      12000,
+			Prestador: model.PrestadorBasico{
+				ID:     206,
+				CUIT:   "20367778884",
+				Nombre: "Martín Sosa",
+			},
+			PrestacionCodigo:   "420101",
+			Prestacion:         "Consulta médica en consultorio",
+			EspecialidadCodigo: "CLINICA_MEDICA",
+			Especialidad:       "Clínica Médica",
+			Metodo:             model.MetodoEfectivo,
+			Moneda:             model.MonedaARS,
+			Monto:              12000_00,
+			MontoReconocido:    10500_00,
+			Factura: &model.Factura{
+				Tipo:       model.FacturaC,
+				PuntoVenta: 2,
+				Numero:     431,
+				CUITEmisor: "27284567892",
+				Fecha:      "2025-09-05",
+				CAE:        "75361122334455",
+				Importe:    12000_00,
+			},
 			Historial: []model.HistorialEstado{
 				{
 					Estado:      model.EstadoRecibido,
@@ -127,17 +207,27 @@ func (r *reintegroRepositoryImpl) initializeDummyData() {
 	r.nextID = 8804
 }
 
-func (r *reintegroRepositoryImpl) GetAll(estado string, query string, page int, size int, sort string) ([]model.ReintegroListItem, int, error) {
+func (r *reintegroRepositoryImpl) GetAll(estado string, especialidad string, query string, page int, size int, sort string) ([]model.ReintegroListItem, int, error) {
+	orden, err := parseOrden(sort, ordenReintegros)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	tokens := tokensBusqueda(query)
+
 	var items []model.ReintegroListItem
 	for _, rgt := range r.reintegros {
 		if estado != "" && string(rgt.Estado) != estado {
 			continue
 		}
-		if query != "" {
-			// TODO: implementar búsqueda por DNI/nombre/apellido/nro
+		if especialidad != "" && rgt.EspecialidadCodigo != especialidad {
+			continue
+		}
+		if len(tokens) > 0 && !coincideBusqueda(tokens, textoBusquedaReintegro(rgt.ID, rgt.Afiliado, rgt.PrestacionCodigo, rgt.Prestacion, rgt.Especialidad, string(rgt.Metodo))) {
+			continue
 		}
 
 		item := model.ReintegroListItem{
@@ -147,13 +237,20 @@ func (r *reintegroRepositoryImpl) GetAll(estado string, query string, page int,
 			Estado:             rgt.Estado,
 			FechaCreacion:      rgt.FechaCreacion,
 			FechaActualizacion: rgt.FechaActualizacion,
+			PrestacionCodigo:   rgt.PrestacionCodigo,
 			Prestacion:         rgt.Prestacion,
+			EspecialidadCodigo: rgt.EspecialidadCodigo,
+			Especialidad:       rgt.Especialidad,
 			Metodo:             rgt.Metodo,
+			Moneda:             rgt.Moneda,
 			Monto:              rgt.Monto,
+			MontoReconocido:    rgt.MontoReconocido,
 		}
 		items = append(items, item)
 	}
 
+	ordenar(items, orden, ordenReintegros, func(i model.ReintegroListItem) int { return i.ID })
+
 	total := len(items)
 	start := page * size
 	end := start + size
@@ -182,11 +279,18 @@ func (r *reintegroRepositoryImpl) Create(req model.CreateReintegroRequest) (*mod
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if req.Factura != nil {
+		if usadoEn := r.facturaEnUso(*req.Factura); usadoEn != 0 {
+			return nil, &FacturaEnUsoError{ReintegroID: usadoEn}
+		}
+	}
+
 	estadoInicial := req.EstadoInicial
 	if estadoInicial == "" {
 		estadoInicial = model.EstadoRecibido
 	}
 	now := time.Now()
+	liquidacion := req.Liquidacion
 
 	rgt := &model.ReintegroDetalle{
 		ID:                 r.nextID,
@@ -194,19 +298,25 @@ func (r *reintegroRepositoryImpl) Create(req model.CreateReintegroRequest) (*mod
 		Estado:             estadoInicial,
 		FechaCreacion:      now,
 		FechaActualizacion: now,
-		Afiliado: model.AfiliadoBasico{
-			ID:       req.AfiliadoID,
-			DNI:      "dummy-dni",
-			Nombre:   "Dummy",
-			Apellido: "Afiliado",
-		},
-		Prestacion: req.Prestacion,
-		Metodo:     req.Metodo,
-		Monto:      req.Monto,
+		Afiliado:           req.Afiliado,
+		Prestador:          req.Prestador,
+		PrestacionCodigo:   req.PrestacionCodigo,
+		Prestacion:         req.Prestacion,
+		EspecialidadCodigo: req.EspecialidadCodigo,
+		Especialidad:       req.Especialidad,
+		Metodo:             req.Metodo,
+		CuentaCobroID:      req.CuentaCobroID,
+		Moneda:             req.Moneda,
+		Monto:              req.Monto,
+		MontoReconocido:    liquidacion.MontoReconocido,
+		Liquidacion:        &liquidacion,
+		AutorizacionID:     req.AutorizacionID,
+		Factura:            req.Factura,
 		Historial: []model.HistorialEstado{
 			{
 				Estado:      estadoInicial,
-				Usuario:     "sistema",
+				Usuario:     usuarioOSistema(req.Usuario),
+				Rol:         req.Rol,
 				FechaCambio: now,
 			},
 		},
@@ -226,22 +336,33 @@ func (r *reintegroRepositoryImpl) Update(id int, req model.UpdateReintegroReques
 	if !exists {
 		return fmt.Errorf("reintegro no encontrado")
 	}
+	if !slices.Contains(estadosEditables, rgt.Estado) {
+		return ErrEstadoModificado
+	}
 
-	if req.Prestacion != "" {
+	if req.PrestacionCodigo != "" {
+		rgt.PrestacionCodigo = req.PrestacionCodigo
 		rgt.Prestacion = req.Prestacion
+		rgt.EspecialidadCodigo = req.EspecialidadCodigo
+		rgt.Especialidad = req.Especialidad
 	}
 	if req.Metodo != "" {
 		rgt.Metodo = req.Metodo
+		rgt.CuentaCobroID = req.CuentaCobroID
+	}
+	if req.Monto != nil {
+		rgt.Monto = *req.Monto
 	}
-	if req.Monto != 0 {
-		rgt.Monto = req.Monto
+	if req.Liquidacion != nil {
+		rgt.MontoReconocido = req.Liquidacion.MontoReconocido
+		rgt.Liquidacion = req.Liquidacion
 	}
 
 	rgt.FechaActualizacion = time.Now()
 	return nil
 }
 
-func (r *reintegroRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRequest) (*model.ReintegroDetalle, error) {
+func (r *reintegroRepositoryImpl) CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.ReintegroDetalle, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -249,6 +370,9 @@ func (r *reintegroRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRe
 	if !exists {
 		return nil, fmt.Errorf("reintegro no encontrado")
 	}
+	if rgt.Estado != estadoActual {
+		return nil, ErrEstadoModificado
+	}
 
 	// Validación mínima de motivo (el service también valida)
 	if (req.NuevoEstado == model.EstadoObservado || req.NuevoEstado == model.EstadoRechazado) && req.Motivo == "" {
@@ -262,6 +386,7 @@ func (r *reintegroRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRe
 	h := model.HistorialEstado{
 		Estado:      req.NuevoEstado,
 		Usuario:     req.Usuario,
+		Rol:         req.Rol,
 		FechaCambio: now,
 		Motivo:      req.Motivo,
 	}
@@ -269,3 +394,40 @@ func (r *reintegroRepositoryImpl) CambiarEstado(id int, req model.CambioEstadoRe
 
 	return rgt, nil
 }
+
+func (r *reintegroRepositoryImpl) MontoReconocidoAnual(afiliadoID int, especialidad string, anio int, excluirID int) (model.Monto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total model.Monto
+	for _, rgt := range r.reintegros {
+		if rgt.ID == excluirID || rgt.Afiliado.ID != afiliadoID || rgt.Estado == model.EstadoRechazado {
+			continue
+		}
+		if rgt.Especialidad == especialidad && rgt.FechaCreacion.Year() == anio {
+			total += rgt.MontoReconocido
+		}
+	}
+	return total, nil
+}
+
+func (r *reintegroRepositoryImpl) FacturaEnUso(factura model.Factura) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.facturaEnUso(factura), nil
+}
+
+// facturaEnUso requiere tener tomado el mutex
+func (r *reintegroRepositoryImpl) facturaEnUso(factura model.Factura) int {
+	for _, rgt := range r.reintegros {
+		f := rgt.Factura
+		if f == nil || rgt.Estado == model.EstadoRechazado {
+			continue
+		}
+		if f.CUITEmisor == factura.CUITEmisor && f.Tipo == factura.Tipo && f.PuntoVenta == factura.PuntoVenta && f.Numero == factura.Numero {
+			return rgt.ID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"time"
+)
+
+type reintegroSQLRepository struct {
+	db *sql.DB
+}
+
+// NewReintegroSQLRepository crea un ReintegroRepository persistido en base de datos
+func NewReintegroSQLRepository(db *sql.DB) ReintegroRepository {
+	return &reintegroSQLRepository{db: db}
+}
+
+func (r *reintegroSQLRepository) GetAll(estado string, especialidad string, query string, page int, size int, sort string) ([]model.ReintegroListItem, int, error) {
+	orden, err := parseOrden(sort, ordenReintegros)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	w := &sqlWhere{}
+	if estado != "" {
+		w.add("estado = ?", estado)
+	}
+	if especialidad != "" {
+		w.add("especialidad_codigo = ?", especialidad)
+	}
+	w.addBusqueda("texto_busqueda", query)
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM reintegros"+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar reintegros: %w", err)
+	}
+
+	where := w.String()
+	limit, offset := w.arg(size), w.arg(page*size)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       prestacion_codigo, prestacion, especialidad_codigo, especialidad, metodo, moneda, monto_centavos, monto_reconocido_centavos
+		FROM reintegros%s%s
+		LIMIT %s OFFSET %s`, where, orderBy(orden, ordenReintegros), limit, offset), w.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al listar reintegros: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.ReintegroListItem
+	for rows.Next() {
+		item := model.ReintegroListItem{Tipo: model.TipoReintegro}
+		if err := rows.Scan(
+			&item.ID, &item.Estado, &item.FechaCreacion, &item.FechaActualizacion,
+			&item.Afiliado.ID, &item.Afiliado.DNI, &item.Afiliado.Nombre, &item.Afiliado.Apellido,
+			&item.PrestacionCodigo, &item.Prestacion, &item.EspecialidadCodigo, &item.Especialidad, &item.Metodo, &item.Moneda, &item.Monto, &item.MontoReconocido,
+		); err != nil {
+			return nil, 0, fmt.Errorf("error al leer reintegro: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+func (r *reintegroSQLRepository) GetByID(id int) (*model.ReintegroDetalle, error) {
+	rgt := &model.ReintegroDetalle{Tipo: model.TipoReintegro}
+
+	var (
+		fechaLiquidacion sql.NullTime
+		liq              model.LiquidacionReintegro
+	)
+	err := r.db.QueryRow(`
+		SELECT id, estado, fecha_creacion, fecha_actualizacion,
+		       afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+		       prestador_id, prestador_cuit, prestador_nombre,
+		       prestacion_codigo, prestacion, especialidad_codigo, especialidad, metodo, cuenta_cobro_id, moneda, monto_centavos, monto_reconocido_centavos, autorizacion_id,
+		       fecha_liquidacion, valor_referencia_centavos, monto_reconocible_centavos, porcentaje_cobertura,
+		       coseguro_centavos, tope_anual_centavos, consumido_anual_centavos, tope_aplicado_centavos
+		FROM reintegros
+		WHERE id = $1`, id).Scan(
+		&rgt.ID, &rgt.Estado, &rgt.FechaCreacion, &rgt.FechaActualizacion,
+		&rgt.Afiliado.ID, &rgt.Afiliado.DNI, &rgt.Afiliado.Nombre, &rgt.Afiliado.Apellido,
+		&rgt.Prestador.ID, &rgt.Prestador.CUIT, &rgt.Prestador.Nombre,
+		&rgt.PrestacionCodigo, &rgt.Prestacion, &rgt.EspecialidadCodigo, &rgt.Especialidad, &rgt.Metodo, &rgt.CuentaCobroID, &rgt.Moneda, &rgt.Monto, &rgt.MontoReconocido, &rgt.AutorizacionID,
+		&fechaLiquidacion, &liq.ValorReferencia, &liq.MontoReconocible, &liq.PorcentajeCobertura,
+		&liq.Coseguro, &liq.TopeAnual, &liq.ConsumidoAnual, &liq.TopeAplicado,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reintegro no encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener reintegro: %w", err)
+	}
+	if fechaLiquidacion.Valid {
+		liq.Fecha = fechaLiquidacion.Time
+		liq.MontoPresentado = rgt.Monto
+		liq.MontoReconocido = rgt.MontoReconocido
+		rgt.Liquidacion = &liq
+	}
+
+	var factura model.Factura
+	err = r.db.QueryRow(`
+		SELECT tipo, punto_venta, numero, cuit_emisor, fecha, cae, importe_centavos
+		FROM reintegro_facturas
+		WHERE reintegro_id = $1`, id).Scan(
+		&factura.Tipo, &factura.PuntoVenta, &factura.Numero, &factura.CUITEmisor, &factura.Fecha, &factura.CAE, &factura.Importe,
+	)
+	switch {
+	case err == nil:
+		rgt.Factura = &factura
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("error al obtener factura de reintegro: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT estado, usuario, rol, fecha_cambio, motivo
+		FROM reintegro_historial
+		WHERE reintegro_id = $1
+		ORDER BY fecha_cambio, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de reintegro: %w", err)
+	}
+	defer rows.Close()
+
+	rgt.Historial = []model.HistorialEstado{}
+	for rows.Next() {
+		var h model.HistorialEstado
+		if err := rows.Scan(&h.Estado, &h.Usuario, &h.Rol, &h.FechaCambio, &h.Motivo); err != nil {
+			return nil, fmt.Errorf("error al leer historial de reintegro: %w", err)
+		}
+		rgt.Historial = append(rgt.Historial, h)
+	}
+
+	return rgt, rows.Err()
+}
+
+func (r *reintegroSQLRepository) Create(req model.CreateReintegroRequest) (*model.ReintegroDetalle, error) {
+	estadoInicial := req.EstadoInicial
+	if estadoInicial == "" {
+		estadoInicial = model.EstadoRecibido
+	}
+
+	now := time.Now().UTC()
+	afiliado := req.Afiliado
+	liq := req.Liquidacion
+
+	var (
+		id             int
+		errFacturaAlta bool
+	)
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			INSERT INTO reintegros (estado, fecha_creacion, fecha_actualizacion,
+				afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+				prestador_id, prestador_cuit, prestador_nombre,
+				prestacion_codigo, prestacion, especialidad_codigo, especialidad, metodo, cuenta_cobro_id, moneda, monto_centavos, monto_reconocido_centavos, autorizacion_id,
+				fecha_liquidacion, valor_referencia_centavos, monto_reconocible_centavos, porcentaje_cobertura,
+				coseguro_centavos, tope_anual_centavos, consumido_anual_centavos, tope_aplicado_centavos)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)
+			RETURNING id`,
+			estadoInicial, now, now,
+			afiliado.ID, afiliado.DNI, afiliado.Nombre, afiliado.Apellido,
+			req.Prestador.ID, req.Prestador.CUIT, req.Prestador.Nombre,
+			req.PrestacionCodigo, req.Prestacion, req.EspecialidadCodigo, req.Especialidad, req.Metodo, req.CuentaCobroID, req.Moneda, req.Monto, liq.MontoReconocido, req.AutorizacionID,
+			liq.Fecha, liq.ValorReferencia, liq.MontoReconocible, liq.PorcentajeCobertura,
+			liq.Coseguro, liq.TopeAnual, liq.ConsumidoAnual, liq.TopeAplicado,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error al crear reintegro: %w", err)
+		}
+
+		_, err = tx.Exec("UPDATE reintegros SET texto_busqueda = $1 WHERE id = $2",
+			textoBusquedaReintegro(id, afiliado, req.PrestacionCodigo, req.Prestacion, req.Especialidad, string(req.Metodo)), id)
+		if err != nil {
+			return fmt.Errorf("error al indexar reintegro: %w", err)
+		}
+
+		if f := req.Factura; f != nil {
+			_, err = tx.Exec(`
+				INSERT INTO reintegro_facturas (reintegro_id, tipo, punto_venta, numero, cuit_emisor, fecha, cae, importe_centavos)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				id, f.Tipo, f.PuntoVenta, f.Numero, f.CUITEmisor, f.Fecha, f.CAE, f.Importe)
+			if err != nil {
+				errFacturaAlta = true
+				return fmt.Errorf("error al registrar factura de reintegro: %w", err)
+			}
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO reintegro_historial (reintegro_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, estadoInicial, usuarioOSistema(req.Usuario), req.Rol, now, "")
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de reintegro: %w", err)
+		}
+		return nil
+	})
+	if err != nil && errFacturaAlta {
+		// El índice único rechaza la factura si un alta simultáneo la registró primero
+		if usadoEn, errUso := r.FacturaEnUso(*req.Factura); errUso == nil && usadoEn != 0 {
+			return nil, &FacturaEnUsoError{ReintegroID: usadoEn}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *reintegroSQLRepository) Update(id int, req model.UpdateReintegroRequest) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var (
+			afiliado           model.AfiliadoBasico
+			codigo             string
+			prestacion         string
+			especialidadCodigo string
+			especialidad       string
+			metodo             model.MetodoReintegro
+			cuentaCobroID      *int
+			monto              model.Monto
+			reconocido         model.Monto
+		)
+		err := tx.QueryRow(`
+			SELECT afiliado_id, afiliado_dni, afiliado_nombre, afiliado_apellido,
+			       prestacion_codigo, prestacion, especialidad_codigo, especialidad, metodo, cuenta_cobro_id, monto_centavos, monto_reconocido_centavos
+			FROM reintegros WHERE id = $1`, id).Scan(
+			&afiliado.ID, &afiliado.DNI, &afiliado.Nombre, &afiliado.Apellido,
+			&codigo, &prestacion, &especialidadCodigo, &especialidad, &metodo, &cuentaCobroID, &monto, &reconocido,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("reintegro no encontrado")
+		}
+		if err != nil {
+			return fmt.Errorf("error al obtener reintegro: %w", err)
+		}
+
+		if req.PrestacionCodigo != "" {
+			codigo = req.PrestacionCodigo
+			prestacion = req.Prestacion
+			especialidadCodigo = req.EspecialidadCodigo
+			especialidad = req.Especialidad
+		}
+		if req.Metodo != "" {
+			metodo = req.Metodo
+			cuentaCobroID = req.CuentaCobroID
+		}
+		if req.Monto != nil {
+			monto = *req.Monto
+		}
+		if req.Liquidacion != nil {
+			reconocido = req.Liquidacion.MontoReconocido
+		}
+
+		res, err := tx.Exec(`
+			UPDATE reintegros SET
+				prestacion_codigo = $1,
+				prestacion = $2,
+				especialidad_codigo = $3,
+				especialidad = $4,
+				metodo = $5,
+				cuenta_cobro_id = $6,
+				monto_centavos = $7,
+				monto_reconocido_centavos = $8,
+				texto_busqueda = $9,
+				fecha_actualizacion = $10
+			WHERE id = $11 AND estado IN ($12, $13)`,
+			codigo, prestacion, especialidadCodigo, especialidad, metodo, cuentaCobroID, monto, reconocido,
+			textoBusquedaReintegro(id, afiliado, codigo, prestacion, especialidad, string(metodo)), time.Now().UTC(), id,
+			model.EstadoRecibido, model.EstadoObservado)
+		if err != nil {
+			return fmt.Errorf("error al actualizar reintegro: %w", err)
+		}
+		// Si el auditor lo tomó entre la lectura y la escritura no se cambian montos ni forma de pago
+		if err := checkRowsAffected(res, ErrEstadoModificado); err != nil {
+			return err
+		}
+
+		if liq := req.Liquidacion; liq != nil {
+			_, err = tx.Exec(`
+				UPDATE reintegros SET
+					fecha_liquidacion = $1,
+					valor_referencia_centavos = $2,
+					monto_reconocible_centavos = $3,
+					porcentaje_cobertura = $4,
+					coseguro_centavos = $5,
+					tope_anual_centavos = $6,
+					consumido_anual_centavos = $7,
+					tope_aplicado_centavos = $8
+				WHERE id = $9`,
+				liq.Fecha, liq.ValorReferencia, liq.MontoReconocible, liq.PorcentajeCobertura,
+				liq.Coseguro, liq.TopeAnual, liq.ConsumidoAnual, liq.TopeAplicado, id)
+			if err != nil {
+				return fmt.Errorf("error al guardar liquidación de reintegro: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *reintegroSQLRepository) CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.ReintegroDetalle, error) {
+	if (req.NuevoEstado == model.EstadoObservado || req.NuevoEstado == model.EstadoRechazado) && req.Motivo == "" {
+		return nil, fmt.Errorf("el motivo es obligatorio para estados OBSERVADO y RECHAZADO")
+	}
+
+	now := time.Now().UTC()
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`
+			UPDATE reintegros SET estado = $1, fecha_actualizacion = $2
+			WHERE id = $3 AND estado = $4`,
+			req.NuevoEstado, now, id, estadoActual)
+		if err != nil {
+			return fmt.Errorf("error al cambiar estado de reintegro: %w", err)
+		}
+		if err := checkRowsAffected(res, ErrEstadoModificado); err != nil {
+			return err
+		}
+
+		// La factura de un reintegro rechazado se puede presentar en otro
+		if req.NuevoEstado == model.EstadoRechazado {
+			if _, err := tx.Exec(`UPDATE reintegro_facturas SET liberada = TRUE WHERE reintegro_id = $1`, id); err != nil {
+				return fmt.Errorf("error al liberar factura de reintegro: %w", err)
+			}
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO reintegro_historial (reintegro_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, req.NuevoEstado, req.Usuario, req.Rol, now, req.Motivo)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de reintegro: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *reintegroSQLRepository) MontoReconocidoAnual(afiliadoID int, especialidad string, anio int, excluirID int) (model.Monto, error) {
+	desde := time.Date(anio, time.January, 1, 0, 0, 0, 0, time.UTC)
+	hasta := desde.AddDate(1, 0, 0)
+
+	var total model.Monto
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(monto_reconocido_centavos), 0)
+		FROM reintegros
+		WHERE afiliado_id = $1 AND especialidad = $2
+		  AND fecha_creacion >= $3 AND fecha_creacion < $4
+		  AND estado <> $5 AND id <> $6`,
+		afiliadoID, especialidad, desde, hasta, model.EstadoRechazado, excluirID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("error al sumar montos reconocidos: %w", err)
+	}
+	return total, nil
+}
+
+func (r *reintegroSQLRepository) FacturaEnUso(factura model.Factura) (int, error) {
+	var id int
+	err := r.db.QueryRow(`
+		SELECT reintegro_id
+		FROM reintegro_facturas
+		WHERE cuit_emisor = $1 AND tipo = $2 AND punto_venta = $3 AND numero = $4
+		  AND NOT liberada`,
+		factura.CUITEmisor, factura.Tipo, factura.PuntoVenta, factura.Numero).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error al buscar factura de reintegro: %w", err)
+	}
+	return id, nil
+}
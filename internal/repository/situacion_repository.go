@@ -9,7 +9,6 @@ import (
 
 type SituacionRepository interface {
 	GetByAfiliado(afiliadoID int) ([]model.Situacion, error)
-	GetByAfiliadoGrupo(afiliadoID int) ([]model.IntegranteSituaciones, error)
 	Create(req model.CreateSituacionRequest) (*model.Situacion, error)
 	Patch(situacionID int, req model.PatchSituacionRequest) error
 	CambiarEstado(situacionID int, req model.CambioEstadoSituacionRequest) (*model.Situacion, error)
@@ -20,34 +19,12 @@ type situacionRepositoryImpl struct {
 	mu          sync.RWMutex
 	situaciones map[int]*model.Situacion
 	nextID      int
-
-	// Mapa de grupo familiar (mock): titular -> integrantes
-	// Para demo: nombres y parentescos fijos por afiliado.
-	grupoFamiliar map[int][]struct {
-		MiembroID  int
-		Nombre     string
-		Parentesco string
-	}
 }
 
 func NewSituacionRepository() SituacionRepository {
 	repo := &situacionRepositoryImpl{
 		situaciones: make(map[int]*model.Situacion),
 		nextID:      7001,
-		grupoFamiliar: map[int][]struct {
-			MiembroID  int
-			Nombre     string
-			Parentesco string
-		}{
-			// Ejemplo: afiliado 22 tiene 2 integrantes
-			22: {
-				{MiembroID: 2201, Nombre: "Ana Osorio", Parentesco: "Hija"},
-				{MiembroID: 2202, Nombre: "Luis Osorio", Parentesco: "Cónyuge"},
-			},
-			31: {
-				{MiembroID: 3101, Nombre: "Pedro Queen", Parentesco: "Hijo"},
-			},
-		},
 	}
 	repo.initializeDummyData()
 	return repo
@@ -125,46 +102,6 @@ func (r *situacionRepositoryImpl) GetByAfiliado(afiliadoID int) ([]model.Situaci
 	return out, nil
 }
 
-func (r *situacionRepositoryImpl) GetByAfiliadoGrupo(afiliadoID int) ([]model.IntegranteSituaciones, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	// 1) Titular
-	titular := model.IntegranteSituaciones{
-		MiembroID:   afiliadoID,
-		Nombre:      mockNombreTitular(afiliadoID),
-		Parentesco:  "Titular",
-		Situaciones: []model.Situacion{},
-	}
-	for _, s := range r.situaciones {
-		if s.AfiliadoID == afiliadoID && s.MiembroID == nil {
-			titular.Situaciones = append(titular.Situaciones, *s)
-		}
-	}
-
-	// 2) Integrantes
-	integrantesMeta := r.grupoFamiliar[afiliadoID]
-	integrantes := make([]model.IntegranteSituaciones, 0, len(integrantesMeta)+1)
-	integrantes = append(integrantes, titular)
-
-	for _, meta := range integrantesMeta {
-		miembro := model.IntegranteSituaciones{
-			MiembroID:   meta.MiembroID,
-			Nombre:      meta.Nombre,
-			Parentesco:  meta.Parentesco,
-			Situaciones: []model.Situacion{},
-		}
-		for _, s := range r.situaciones {
-			if s.AfiliadoID == afiliadoID && s.MiembroID != nil && *s.MiembroID == meta.MiembroID {
-				miembro.Situaciones = append(miembro.Situaciones, *s)
-			}
-		}
-		integrantes = append(integrantes, miembro)
-	}
-
-	return integrantes, nil
-}
-
 func (r *situacionRepositoryImpl) Create(req model.CreateSituacionRequest) (*model.Situacion, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -180,6 +117,14 @@ func (r *situacionRepositoryImpl) Create(req model.CreateSituacionRequest) (*mod
 		Estado:             model.EstadoSituacionActiva, // alta -> ACTIVA
 		FechaCreacion:      now,
 		FechaActualizacion: now,
+		Historial: []model.HistorialEstadoSituacion{
+			{
+				Estado:      model.EstadoSituacionActiva,
+				Usuario:     usuarioOSistema(req.Usuario),
+				Rol:         req.Rol,
+				FechaCambio: now,
+			},
+		},
 	}
 
 	r.situaciones[r.nextID] = s
@@ -232,8 +177,16 @@ func (r *situacionRepositoryImpl) CambiarEstado(situacionID int, req model.Cambi
 		s.FechaFin = &hoy
 	}
 
+	now := time.Now().UTC()
 	s.Estado = req.Estado
-	s.FechaActualizacion = time.Now().UTC()
+	s.FechaActualizacion = now
+	s.Historial = append(s.Historial, model.HistorialEstadoSituacion{
+		Estado:      req.Estado,
+		Usuario:     req.Usuario,
+		Rol:         req.Rol,
+		FechaCambio: now,
+		Motivo:      req.Motivo,
+	})
 	return s, nil
 }
 
@@ -251,15 +204,3 @@ func (r *situacionRepositoryImpl) Delete(situacionID int) error {
 // ===== Helpers =====
 
 func intPtr(v int) *int { return &v }
-
-// Nombre “dummy” para titulares según afiliadoID (mock)
-func mockNombreTitular(afiliadoID int) string {
-	switch afiliadoID {
-	case 22:
-		return "Miguel Osorio"
-	case 31:
-		return "David Queen"
-	default:
-		return fmt.Sprintf("Titular %d", afiliadoID)
-	}
-}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"time"
+)
+
+type situacionSQLRepository struct {
+	db *sql.DB
+}
+
+// NewSituacionSQLRepository crea un SituacionRepository persistido en base de datos
+func NewSituacionSQLRepository(db *sql.DB) SituacionRepository {
+	return &situacionSQLRepository{db: db}
+}
+
+func (r *situacionSQLRepository) GetByAfiliado(afiliadoID int) ([]model.Situacion, error) {
+	rows, err := r.db.Query(`
+		SELECT id, afiliado_id, miembro_id, descripcion, fecha_inicio, fecha_fin, estado, fecha_creacion, fecha_actualizacion
+		FROM situaciones
+		WHERE afiliado_id = $1
+		ORDER BY id`, afiliadoID)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar situaciones: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]model.Situacion, 0)
+	posicion := map[int]int{}
+	for rows.Next() {
+		var (
+			s         model.Situacion
+			miembroID sql.NullInt64
+			fechaFin  sql.NullString
+		)
+		if err := rows.Scan(&s.ID, &s.AfiliadoID, &miembroID, &s.Descripcion, &s.FechaInicio, &fechaFin,
+			&s.Estado, &s.FechaCreacion, &s.FechaActualizacion); err != nil {
+			return nil, fmt.Errorf("error al leer situación: %w", err)
+		}
+		if miembroID.Valid {
+			s.MiembroID = intPtr(int(miembroID.Int64))
+		}
+		if fechaFin.Valid {
+			s.FechaFin = &fechaFin.String
+		}
+		posicion[s.ID] = len(out)
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// El historial de todas las situaciones del afiliado en una sola consulta
+	hist, err := r.db.Query(`
+		SELECT h.situacion_id, h.estado, h.usuario, h.rol, h.fecha_cambio, h.motivo
+		FROM situacion_historial h
+		JOIN situaciones s ON s.id = h.situacion_id
+		WHERE s.afiliado_id = $1
+		ORDER BY h.fecha_cambio, h.id`, afiliadoID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de situaciones: %w", err)
+	}
+	defer hist.Close()
+
+	for hist.Next() {
+		var (
+			situacionID int
+			h           model.HistorialEstadoSituacion
+		)
+		if err := hist.Scan(&situacionID, &h.Estado, &h.Usuario, &h.Rol, &h.FechaCambio, &h.Motivo); err != nil {
+			return nil, fmt.Errorf("error al leer historial de situación: %w", err)
+		}
+		if i, ok := posicion[situacionID]; ok {
+			out[i].Historial = append(out[i].Historial, h)
+		}
+	}
+	return out, hist.Err()
+}
+
+func (r *situacionSQLRepository) getByID(id int) (*model.Situacion, error) {
+	var (
+		s         model.Situacion
+		miembroID sql.NullInt64
+		fechaFin  sql.NullString
+	)
+	err := r.db.QueryRow(`
+		SELECT id, afiliado_id, miembro_id, descripcion, fecha_inicio, fecha_fin, estado, fecha_creacion, fecha_actualizacion
+		FROM situaciones WHERE id = $1`, id).Scan(
+		&s.ID, &s.AfiliadoID, &miembroID, &s.Descripcion, &s.FechaInicio, &fechaFin,
+		&s.Estado, &s.FechaCreacion, &s.FechaActualizacion,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("situación no encontrada")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener situación: %w", err)
+	}
+	if miembroID.Valid {
+		s.MiembroID = intPtr(int(miembroID.Int64))
+	}
+	if fechaFin.Valid {
+		s.FechaFin = &fechaFin.String
+	}
+
+	rows, err := r.db.Query(`
+		SELECT estado, usuario, rol, fecha_cambio, motivo
+		FROM situacion_historial
+		WHERE situacion_id = $1
+		ORDER BY fecha_cambio, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de situación: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h model.HistorialEstadoSituacion
+		if err := rows.Scan(&h.Estado, &h.Usuario, &h.Rol, &h.FechaCambio, &h.Motivo); err != nil {
+			return nil, fmt.Errorf("error al leer historial de situación: %w", err)
+		}
+		s.Historial = append(s.Historial, h)
+	}
+	return &s, rows.Err()
+}
+
+func (r *situacionSQLRepository) Create(req model.CreateSituacionRequest) (*model.Situacion, error) {
+	now := time.Now().UTC()
+
+	var id int
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			INSERT INTO situaciones (afiliado_id, miembro_id, descripcion, fecha_inicio, fecha_fin, estado, fecha_creacion, fecha_actualizacion)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			req.AfiliadoID, req.MiembroID, req.Descripcion, req.FechaInicio, req.FechaFin,
+			model.EstadoSituacionActiva, now, now,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error al crear situación: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO situacion_historial (situacion_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, model.EstadoSituacionActiva, usuarioOSistema(req.Usuario), req.Rol, now, "")
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de situación: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getByID(id)
+}
+
+func (r *situacionSQLRepository) Patch(situacionID int, req model.PatchSituacionRequest) error {
+	// Los campos que no vienen conservan su valor; fechaFin "" la borra
+	var fechaFin any
+	limpiarFin := false
+	if req.FechaFin != nil {
+		if *req.FechaFin == "" {
+			limpiarFin = true
+		} else {
+			fechaFin = *req.FechaFin
+		}
+	}
+
+	res, err := r.db.Exec(`
+		UPDATE situaciones SET
+			descripcion = COALESCE($1, descripcion),
+			fecha_inicio = COALESCE($2, fecha_inicio),
+			fecha_fin = CASE WHEN $3 THEN NULL ELSE COALESCE($4, fecha_fin) END,
+			fecha_actualizacion = $5
+		WHERE id = $6`,
+		req.Descripcion, req.FechaInicio, limpiarFin, fechaFin, time.Now().UTC(), situacionID)
+	if err != nil {
+		return fmt.Errorf("error al actualizar situación: %w", err)
+	}
+	return checkRowsAffected(res, fmt.Errorf("situación no encontrada"))
+}
+
+func (r *situacionSQLRepository) CambiarEstado(situacionID int, req model.CambioEstadoSituacionRequest) (*model.Situacion, error) {
+	now := time.Now().UTC()
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// Al pasar a BAJA sin fecha de fin se completa con la fecha del día, igual que en memoria
+		var hoy any
+		if req.Estado == model.EstadoSituacionBaja {
+			hoy = now.Format("2006-01-02")
+		}
+		res, err := tx.Exec(`
+			UPDATE situaciones SET estado = $1, fecha_actualizacion = $2,
+				fecha_fin = CASE WHEN fecha_fin IS NULL OR fecha_fin = '' THEN $3 ELSE fecha_fin END
+			WHERE id = $4`,
+			req.Estado, now, hoy, situacionID)
+		if err != nil {
+			return fmt.Errorf("error al cambiar estado de situación: %w", err)
+		}
+		if err := checkRowsAffected(res, fmt.Errorf("situación no encontrada")); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO situacion_historial (situacion_id, estado, usuario, rol, fecha_cambio, motivo)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			situacionID, req.Estado, req.Usuario, req.Rol, now, req.Motivo)
+		if err != nil {
+			return fmt.Errorf("error al registrar historial de situación: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getByID(situacionID)
+}
+
+func (r *situacionSQLRepository) Delete(situacionID int) error {
+	// El historial se borra explícitamente: SQLite solo aplica ON DELETE CASCADE con foreign_keys activado
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM situacion_historial WHERE situacion_id = $1`, situacionID); err != nil {
+			return fmt.Errorf("error al eliminar historial de situación: %w", err)
+		}
+		res, err := tx.Exec(`DELETE FROM situaciones WHERE id = $1`, situacionID)
+		if err != nil {
+			return fmt.Errorf("error al eliminar situación: %w", err)
+		}
+		return checkRowsAffected(res, fmt.Errorf("situación no encontrada"))
+	})
+}
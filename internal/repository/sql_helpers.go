@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// withTx ejecuta fn dentro de una transacción, haciendo rollback si devuelve error
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// checkRowsAffected devuelve notFound si la sentencia no modificó ninguna fila
+func checkRowsAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// sqlWhere acumula condiciones y argumentos numerando los placeholders $1, $2...
+type sqlWhere struct {
+	conds []string
+	args  []any
+}
+
+// arg agrega un argumento y devuelve su placeholder
+func (w *sqlWhere) arg(v any) string {
+	w.args = append(w.args, v)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+// add agrega una condición; cada "?" se reemplaza por el placeholder del argumento correspondiente
+func (w *sqlWhere) add(cond string, args ...any) {
+	for _, a := range args {
+		cond = strings.Replace(cond, "?", w.arg(a), 1)
+	}
+	w.conds = append(w.conds, cond)
+}
+
+// addBusqueda agrega un LIKE por cada token sobre la columna de texto normalizado
+func (w *sqlWhere) addBusqueda(columna string, query string) {
+	for _, token := range tokensBusqueda(query) {
+		w.add(columna+` LIKE ? ESCAPE '\'`, "%"+escapeLike(token)+"%")
+	}
+}
+
+func (w *sqlWhere) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// usuarioOSistema devuelve el usuario que origina el cambio, o "sistema" si no hay uno
+func usuarioOSistema(usuario string) string {
+	if usuario == "" {
+		return "sistema"
+	}
+	return usuario
+}
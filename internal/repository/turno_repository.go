@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"errors"
+	"prestadores-api/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrTurnoNoEncontrado indica que el turno no existe o no es del afiliado indicado
+var ErrTurnoNoEncontrado = errors.New("turno no encontrado")
+
+type TurnoRepository interface {
+	// GetByAfiliado devuelve los turnos del afiliado ordenados por fecha; especialidad
+	// es un código del catálogo y vacío no filtra
+	GetByAfiliado(afiliadoID int, especialidad string) ([]model.Turno, error)
+	Create(req model.CreateTurnoRequest) (*model.Turno, error)
+	Update(afiliadoID int, turnoID int, req model.UpdateTurnoRequest) (*model.Turno, error)
+}
+
+type turnoRepositoryImpl struct {
+	mu     sync.RWMutex
+	turnos map[int]*model.Turno
+	nextID int
+}
+
+func NewTurnoRepository() TurnoRepository {
+	repo := &turnoRepositoryImpl{
+		turnos: make(map[int]*model.Turno),
+		nextID: 500,
+	}
+
+	repo.initializeDummyData()
+
+	return repo
+}
+
+func (r *turnoRepositoryImpl) initializeDummyData() {
+	dummyData := []model.Turno{
+		{
+			ID:                 500,
+			AfiliadoID:         1,
+			Fecha:              time.Date(2025, 9, 20, 10, 0, 0, 0, time.UTC),
+			EspecialidadCodigo: "CLINICA_MEDICA",
+			Especialidad:       "Clínica Médica",
+			Estado:             model.EstadoTurnoReservado,
+			Notas: []model.NotaTurno{
+				{ID: 10, Fecha: time.Date(2025, 9, 20, 10, 30, 0, 0, time.UTC), PrestadorID: 45, Texto: "Control general"},
+			},
+		},
+		{
+			ID:                 501,
+			AfiliadoID:         1,
+			Fecha:              time.Date(2025, 9, 25, 15, 0, 0, 0, time.UTC),
+			EspecialidadCodigo: "KINESIOLOGIA",
+			Especialidad:       "Kinesiología",
+			Estado:             model.EstadoTurnoAtendido,
+			Notas: []model.NotaTurno{
+				{ID: 10, Fecha: time.Date(2025, 9, 20, 10, 30, 0, 0, time.UTC), PrestadorID: 45, Texto: "Control general"},
+				{ID: 12, Fecha: time.Date(2025, 9, 25, 15, 45, 0, 0, time.UTC), PrestadorID: 55, Texto: "Ejercicios domiciliarios"},
+				{ID: 14, Fecha: time.Date(2025, 9, 25, 15, 50, 0, 0, time.UTC), PrestadorID: 55, Texto: "Se pudo notar un leve problema en la rodilla izquierda"},
+			},
+		},
+	}
+
+	for _, t := range dummyData {
+		r.turnos[t.ID] = &t
+	}
+
+	r.nextID = 502
+}
+
+func (r *turnoRepositoryImpl) GetByAfiliado(afiliadoID int, especialidad string) ([]model.Turno, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]model.Turno, 0)
+	for _, t := range r.turnos {
+		if t.AfiliadoID != afiliadoID {
+			continue
+		}
+		if especialidad != "" && t.EspecialidadCodigo != especialidad {
+			continue
+		}
+		out = append(out, copiarTurno(t))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Fecha.Equal(out[j].Fecha) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].Fecha.Before(out[j].Fecha)
+	})
+	return out, nil
+}
+
+func (r *turnoRepositoryImpl) Create(req model.CreateTurnoRequest) (*model.Turno, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := &model.Turno{
+		ID:                 r.nextID,
+		AfiliadoID:         req.AfiliadoID,
+		Fecha:              req.Fecha,
+		EspecialidadCodigo: req.EspecialidadCodigo,
+		Especialidad:       req.Especialidad,
+		Estado:             model.EstadoTurnoReservado,
+		Notas:              []model.NotaTurno{},
+	}
+
+	r.turnos[r.nextID] = t
+	r.nextID++
+
+	out := copiarTurno(t)
+	return &out, nil
+}
+
+func (r *turnoRepositoryImpl) Update(afiliadoID int, turnoID int, req model.UpdateTurnoRequest) (*model.Turno, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.turnos[turnoID]
+	if !ok || t.AfiliadoID != afiliadoID {
+		return nil, ErrTurnoNoEncontrado
+	}
+
+	if req.Fecha != nil {
+		t.Fecha = *req.Fecha
+	}
+	if req.EspecialidadCodigo != "" {
+		t.EspecialidadCodigo = req.EspecialidadCodigo
+		t.Especialidad = req.Especialidad
+	}
+	if req.Estado != "" {
+		t.Estado = req.Estado
+	}
+
+	out := copiarTurno(t)
+	return &out, nil
+}
+
+// copiarTurno evita que quien recibe el turno modifique las notas guardadas
+func copiarTurno(t *model.Turno) model.Turno {
+	out := *t
+	out.Notas = append([]model.NotaTurno{}, t.Notas...)
+	return out
+}
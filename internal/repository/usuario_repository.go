@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"strings"
+	"sync"
+)
+
+// ErrUsuarioExistente indica que ya hay un usuario con el mismo CUIT o username
+var ErrUsuarioExistente = errors.New("ya existe un usuario con ese CUIT o username")
+
+type UsuarioRepository interface {
+	GetByID(id int) (*model.Usuario, error)
+	GetByCUIT(cuit string) (*model.Usuario, error)
+	Create(u model.Usuario) (*model.Usuario, error)
+}
+
+type usuarioRepositoryImpl struct {
+	mu       sync.RWMutex
+	usuarios map[int]*model.Usuario
+	nextID   int
+}
+
+func NewUsuarioRepository() UsuarioRepository {
+	repo := &usuarioRepositoryImpl{
+		usuarios: make(map[int]*model.Usuario),
+		nextID:   1000,
+	}
+
+	repo.initializeDummyData()
+
+	return repo
+}
+
+func (r *usuarioRepositoryImpl) initializeDummyData() {
+	// Contraseñas de ejemplo (hash bcrypt): "prestador123", "auditor123", "admin123" y "farmacia123"
+	dummyData := []model.Usuario{
+		{
+			ID:           201,
+			CUIT:         "20251234567",
+			Username:     "prestador.201",
+			Nombre:       "Juan Pérez",
+			Rol:          model.RolPrestador,
+			PasswordHash: "$2a$10$J2LoL/uMFXAhnZnrZkBAAO5Ny805BU0nvCJK6k708APjldF6eJ5Iu",
+			Activo:       true,
+		},
+		{
+			ID:           202,
+			CUIT:         "27314567892",
+			Username:     "prestador.202",
+			Nombre:       "Laura Gómez",
+			Rol:          model.RolPrestador,
+			PasswordHash: "$2a$10$J2LoL/uMFXAhnZnrZkBAAO5Ny805BU0nvCJK6k708APjldF6eJ5Iu",
+			Activo:       true,
+		},
+		{
+			ID:           301,
+			CUIT:         "20287654325",
+			Username:     "auditor.301",
+			Nombre:       "Marta Ruiz",
+			Rol:          model.RolAuditor,
+			PasswordHash: "$2a$10$5A3TjScZOI0348A/DdPQVe9653PVGoc4AwXUUhj0QCp2hR5niDfRy",
+			Activo:       true,
+		},
+		{
+			ID:           401,
+			CUIT:         "20334455662",
+			Username:     "admin.401",
+			Nombre:       "Administrador",
+			Rol:          model.RolAdmin,
+			PasswordHash: "$2a$10$mwJPry2WW771cAK56hZ5AuYMEe51hvloFe5AJdryt6qYbWlpKLwFW",
+			Activo:       true,
+		},
+		{
+			ID:           501,
+			CUIT:         "30708889993",
+			Username:     "farmacia.501",
+			Nombre:       "Farmacia Central",
+			Rol:          model.RolFarmacia,
+			PasswordHash: "$2a$10$S/RwsERJHevU7gyQ5zgYNeDK4aqsEBM0G0kxz4t9nW1Xf91CZ5a6q",
+			Activo:       true,
+		},
+	}
+
+	for _, u := range dummyData {
+		r.usuarios[u.ID] = &u
+	}
+}
+
+func (r *usuarioRepositoryImpl) GetByID(id int) (*model.Usuario, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, exists := r.usuarios[id]
+	if !exists {
+		return nil, fmt.Errorf("usuario no encontrado")
+	}
+
+	return u, nil
+}
+
+func (r *usuarioRepositoryImpl) GetByCUIT(cuit string) (*model.Usuario, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.usuarios {
+		if u.CUIT == cuit {
+			return u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("usuario no encontrado")
+}
+
+// Create da de alta el usuario asignándole ID y, si no trae, un username "<rol>.<id>"
+func (r *usuarioRepositoryImpl) Create(u model.Usuario) (*model.Usuario, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u.ID = r.nextID
+	if u.Username == "" {
+		u.Username = fmt.Sprintf("%s.%d", strings.ToLower(string(u.Rol)), u.ID)
+	}
+
+	for _, existente := range r.usuarios {
+		if existente.CUIT == u.CUIT || strings.EqualFold(existente.Username, u.Username) {
+			return nil, ErrUsuarioExistente
+		}
+	}
+
+	r.usuarios[u.ID] = &u
+	r.nextID++
+
+	return &u, nil
+}
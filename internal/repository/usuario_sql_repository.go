@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"strings"
+)
+
+type usuarioSQLRepository struct {
+	db *sql.DB
+}
+
+// NewUsuarioSQLRepository crea un UsuarioRepository persistido en base de datos
+func NewUsuarioSQLRepository(db *sql.DB) UsuarioRepository {
+	return &usuarioSQLRepository{db: db}
+}
+
+const columnasUsuario = `id, cuit, username, nombre, rol, password_hash, activo`
+
+func (r *usuarioSQLRepository) get(where string, arg any) (*model.Usuario, error) {
+	var u model.Usuario
+	err := r.db.QueryRow(`SELECT `+columnasUsuario+` FROM usuarios WHERE `+where, arg).Scan(
+		&u.ID, &u.CUIT, &u.Username, &u.Nombre, &u.Rol, &u.PasswordHash, &u.Activo,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("usuario no encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener usuario: %w", err)
+	}
+	return &u, nil
+}
+
+func (r *usuarioSQLRepository) GetByID(id int) (*model.Usuario, error) {
+	return r.get("id = $1", id)
+}
+
+func (r *usuarioSQLRepository) GetByCUIT(cuit string) (*model.Usuario, error) {
+	return r.get("cuit = $1", cuit)
+}
+
+// Create da de alta el usuario; sin username se genera "<rol>.<id>" una vez asignado el ID
+func (r *usuarioSQLRepository) Create(u model.Usuario) (*model.Usuario, error) {
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// El username se compara sin distinguir mayúsculas, igual que en memoria
+		var existentes int
+		err := tx.QueryRow(`
+			SELECT COUNT(*) FROM usuarios
+			WHERE cuit = $1 OR (LOWER(username) = LOWER($2) AND $2 <> '')`,
+			u.CUIT, u.Username).Scan(&existentes)
+		if err != nil {
+			return fmt.Errorf("error al verificar usuarios: %w", err)
+		}
+		if existentes > 0 {
+			return ErrUsuarioExistente
+		}
+
+		// Mientras no tenga ID el username provisorio es el CUIT, que es único
+		username := u.Username
+		if username == "" {
+			username = u.CUIT
+		}
+		err = tx.QueryRow(`
+			INSERT INTO usuarios (cuit, username, nombre, rol, password_hash, activo)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			u.CUIT, username, u.Nombre, u.Rol, u.PasswordHash, u.Activo,
+		).Scan(&u.ID)
+		if err != nil {
+			return fmt.Errorf("error al crear usuario: %w", err)
+		}
+
+		if u.Username == "" {
+			u.Username = fmt.Sprintf("%s.%d", strings.ToLower(string(u.Rol)), u.ID)
+			if _, err := tx.Exec(`UPDATE usuarios SET username = $1 WHERE id = $2`, u.Username, u.ID); err != nil {
+				return fmt.Errorf("error al asignar username: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"bytes"
+	"cmp"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Vademécum por defecto; se reemplaza con VADEMECUM_CSV o importando un CSV
+//
+//go:embed data/vademecum.csv
+var vademecumDefault []byte
+
+// ErrMedicamentoNoEncontrado indica que el código no figura en el vademécum
+var ErrMedicamentoNoEncontrado = errors.New("medicamento no encontrado en el vademécum")
+
+// columnasVademecum es el encabezado esperado del CSV
+var columnasVademecum = []string{"codigo", "droga_generica", "marca_comercial", "presentacion", "concentracion", "laboratorio", "condicion_venta"}
+
+type VademecumRepository interface {
+	GetByCodigo(codigo string) (*model.Medicamento, error)
+	// Buscar matchea el código por prefijo y droga, marca y laboratorio por términos, sin
+	// distinguir mayúsculas ni tildes. Devuelve el total de coincidencias antes de aplicar limit.
+	Buscar(query string, condicion model.CondicionVenta, limit int) ([]model.Medicamento, int, error)
+	// Reemplazar cambia el vademécum completo por el de un CSV ya validado
+	Reemplazar(medicamentos []model.Medicamento) error
+}
+
+type vademecumRepositoryImpl struct {
+	mu           sync.RWMutex
+	medicamentos []model.Medicamento
+	porCodigo    map[string]*model.Medicamento
+}
+
+// NewVademecumRepository carga el vademécum desde el CSV de path, o el embebido si path es vacío
+func NewVademecumRepository(path string) (VademecumRepository, error) {
+	var r io.Reader = bytes.NewReader(vademecumDefault)
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error al abrir vademécum: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	medicamentos, err := CargarVademecum(r)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &vademecumRepositoryImpl{}
+	if err := repo.Reemplazar(medicamentos); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// CargarVademecum lee un CSV con encabezado
+// codigo,droga_generica,marca_comercial,presentacion,concentracion,laboratorio,condicion_venta.
+// Todas las columnas son obligatorias y los códigos no se pueden repetir.
+func CargarVademecum(r io.Reader) ([]model.Medicamento, error) {
+	lector := csv.NewReader(r)
+	lector.FieldsPerRecord = len(columnasVademecum)
+	lector.TrimLeadingSpace = true
+
+	encabezado, err := lector.Read()
+	if err != nil {
+		return nil, fmt.Errorf("vademécum sin encabezado: %w", err)
+	}
+	for i, col := range columnasVademecum {
+		if strings.ToLower(strings.TrimSpace(strings.TrimPrefix(encabezado[i], "\ufeff"))) != col {
+			return nil, fmt.Errorf("encabezado del vademécum inválido: se esperaba %s", strings.Join(columnasVademecum, ","))
+		}
+	}
+
+	var medicamentos []model.Medicamento
+	vistos := make(map[string]int)
+	for {
+		registro, err := lector.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error al leer vademécum: %w", err)
+		}
+		linea, _ := lector.FieldPos(0)
+
+		for i := range registro {
+			registro[i] = strings.TrimSpace(registro[i])
+			if registro[i] == "" {
+				return nil, fmt.Errorf("vademécum, línea %d: la columna %s es obligatoria", linea, columnasVademecum[i])
+			}
+		}
+
+		m := model.Medicamento{
+			Codigo:         registro[0],
+			DrogaGenerica:  registro[1],
+			MarcaComercial: registro[2],
+			Presentacion:   registro[3],
+			Concentracion:  registro[4],
+			Laboratorio:    registro[5],
+			CondicionVenta: model.CondicionVenta(strings.ToUpper(registro[6])),
+		}
+		if anterior, ok := vistos[m.Codigo]; ok {
+			return nil, fmt.Errorf("vademécum, línea %d: código %s repetido (línea %d)", linea, m.Codigo, anterior)
+		}
+		switch m.CondicionVenta {
+		case model.CondicionVentaLibre, model.CondicionVentaBajoReceta, model.CondicionVentaBajoRecetaArchivada:
+		default:
+			return nil, fmt.Errorf("vademécum, línea %d: condición de venta inválida %q", linea, registro[6])
+		}
+
+		vistos[m.Codigo] = linea
+		medicamentos = append(medicamentos, m)
+	}
+
+	if len(medicamentos) == 0 {
+		return nil, fmt.Errorf("el vademécum no tiene medicamentos")
+	}
+	return medicamentos, nil
+}
+
+func (r *vademecumRepositoryImpl) Reemplazar(medicamentos []model.Medicamento) error {
+	porCodigo := make(map[string]*model.Medicamento, len(medicamentos))
+	for i := range medicamentos {
+		porCodigo[medicamentos[i].Codigo] = &medicamentos[i]
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.medicamentos = medicamentos
+	r.porCodigo = porCodigo
+	return nil
+}
+
+func (r *vademecumRepositoryImpl) GetByCodigo(codigo string) (*model.Medicamento, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.porCodigo[strings.TrimSpace(codigo)]
+	if !ok {
+		return nil, ErrMedicamentoNoEncontrado
+	}
+	copia := *m
+	return &copia, nil
+}
+
+func (r *vademecumRepositoryImpl) Buscar(query string, condicion model.CondicionVenta, limit int) ([]model.Medicamento, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := tokensBusqueda(query)
+
+	type resultado struct {
+		medicamento model.Medicamento
+		rango       int
+	}
+
+	var resultados []resultado
+	for _, m := range r.medicamentos {
+		if condicion != "" && m.CondicionVenta != condicion {
+			continue
+		}
+
+		rango, ok := rangoVademecum(tokens, m)
+		if !ok {
+			continue
+		}
+		resultados = append(resultados, resultado{medicamento: m, rango: rango})
+	}
+
+	slices.SortStableFunc(resultados, func(a, b resultado) int {
+		return cmp.Or(
+			cmp.Compare(a.rango, b.rango),
+			cmp.Compare(validacion.NormalizarTexto(a.medicamento.DrogaGenerica), validacion.NormalizarTexto(b.medicamento.DrogaGenerica)),
+			cmp.Compare(a.medicamento.Codigo, b.medicamento.Codigo),
+		)
+	})
+
+	total := len(resultados)
+	if limit > 0 && total > limit {
+		resultados = resultados[:limit]
+	}
+
+	items := make([]model.Medicamento, 0, len(resultados))
+	for _, res := range resultados {
+		items = append(items, res.medicamento)
+	}
+	return items, total, nil
+}
+
+// rangoVademecum ordena las coincidencias: 0 código exacto, 1 prefijo de código, 2 droga
+// genérica que empieza con la búsqueda, 3 marca que empieza con la búsqueda, 4 resto
+func rangoVademecum(tokens []string, m model.Medicamento) (int, bool) {
+	if len(tokens) == 0 {
+		return 4, true
+	}
+
+	if len(tokens) == 1 {
+		if m.Codigo == tokens[0] {
+			return 0, true
+		}
+		if strings.HasPrefix(m.Codigo, tokens[0]) {
+			return 1, true
+		}
+	}
+
+	if !coincideBusqueda(tokens, textoBusqueda(m.DrogaGenerica, m.MarcaComercial, m.Concentracion, m.Laboratorio)) {
+		return 0, false
+	}
+	if strings.HasPrefix(validacion.NormalizarTexto(m.DrogaGenerica), tokens[0]) {
+		return 2, true
+	}
+	if strings.HasPrefix(validacion.NormalizarTexto(m.MarcaComercial), tokens[0]) {
+		return 3, true
+	}
+	return 4, true
+}
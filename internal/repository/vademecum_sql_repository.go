@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"prestadores-api/internal/model"
+)
+
+// vademecumSQLRepository guarda en la base el último vademécum importado. Las consultas se
+// resuelven con el índice en memoria, que se recarga al iniciar desde la tabla.
+type vademecumSQLRepository struct {
+	*vademecumRepositoryImpl
+	db *sql.DB
+}
+
+// NewVademecumSQLRepository carga el vademécum importado; si nunca se importó uno, el CSV de
+// path o el embebido, igual que NewVademecumRepository
+func NewVademecumSQLRepository(db *sql.DB, path string) (VademecumRepository, error) {
+	medicamentos, err := cargarVademecumImportado(db)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &vademecumSQLRepository{vademecumRepositoryImpl: &vademecumRepositoryImpl{}, db: db}
+	if len(medicamentos) == 0 {
+		inicial, err := NewVademecumRepository(path)
+		if err != nil {
+			return nil, err
+		}
+		repo.vademecumRepositoryImpl = inicial.(*vademecumRepositoryImpl)
+		return repo, nil
+	}
+
+	if err := repo.vademecumRepositoryImpl.Reemplazar(medicamentos); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func cargarVademecumImportado(db *sql.DB) ([]model.Medicamento, error) {
+	rows, err := db.Query(`
+		SELECT codigo, droga_generica, marca_comercial, presentacion, concentracion, laboratorio, condicion_venta
+		FROM vademecum
+		ORDER BY orden`)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer vademécum importado: %w", err)
+	}
+	defer rows.Close()
+
+	var medicamentos []model.Medicamento
+	for rows.Next() {
+		var m model.Medicamento
+		if err := rows.Scan(&m.Codigo, &m.DrogaGenerica, &m.MarcaComercial, &m.Presentacion, &m.Concentracion,
+			&m.Laboratorio, &m.CondicionVenta); err != nil {
+			return nil, fmt.Errorf("error al leer medicamento: %w", err)
+		}
+		medicamentos = append(medicamentos, m)
+	}
+	return medicamentos, rows.Err()
+}
+
+// Reemplazar guarda el vademécum en la base y recién después cambia el índice en memoria
+func (r *vademecumSQLRepository) Reemplazar(medicamentos []model.Medicamento) error {
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM vademecum`); err != nil {
+			return fmt.Errorf("error al borrar el vademécum anterior: %w", err)
+		}
+
+		stmt, err := tx.Prepare(`
+			INSERT INTO vademecum (codigo, orden, droga_generica, marca_comercial, presentacion, concentracion, laboratorio, condicion_venta)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+		if err != nil {
+			return fmt.Errorf("error al preparar la importación del vademécum: %w", err)
+		}
+		defer stmt.Close()
+
+		for i, m := range medicamentos {
+			if _, err := stmt.Exec(m.Codigo, i+1, m.DrogaGenerica, m.MarcaComercial, m.Presentacion, m.Concentracion,
+				m.Laboratorio, m.CondicionVenta); err != nil {
+				return fmt.Errorf("error al guardar medicamento %s: %w", m.Codigo, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.vademecumRepositoryImpl.Reemplazar(medicamentos)
+}
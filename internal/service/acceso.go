@@ -0,0 +1,18 @@
+package service
+
+import (
+	"errors"
+	"prestadores-api/internal/model"
+)
+
+// ErrSolicitudAjena indica que un prestador intenta operar sobre una solicitud cargada por otro prestador
+var ErrSolicitudAjena = errors.New("la solicitud pertenece a otro prestador")
+
+// verificarPropietario deja operar a un prestador solo sobre sus propias solicitudes. Auditores y
+// ADMIN trabajan sobre todas; qué operación puede hacer cada rol lo controla el middleware de permisos.
+func verificarPropietario(prestadorID int, usuario model.UsuarioAutenticado) error {
+	if usuario.Rol == model.RolPrestador && prestadorID != usuario.ID {
+		return ErrSolicitudAjena
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"prestadores-api/internal/storage"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAdjuntoNoEncontrado = repository.ErrAdjuntoNoEncontrado
+	ErrAdjuntoDuplicado    = repository.ErrAdjuntoDuplicado
+	// ErrSolicitudNoEncontrada indica que la autorización o el reintegro del path no existe
+	ErrSolicitudNoEncontrada = errors.New("solicitud no encontrada")
+	// ErrChecksumAdjunto indica que el contenido leído del storage no coincide con el SHA-256 guardado
+	ErrChecksumAdjunto = errors.New("el contenido del adjunto no coincide con su checksum")
+)
+
+// contentTypesAdjunto son los formatos admitidos, detectados a partir del contenido
+var contentTypesAdjunto = []string{"application/pdf", "image/jpeg", "image/png"}
+
+// ConfigAdjuntos define los límites de los archivos adjuntos
+type ConfigAdjuntos struct {
+	MaxBytes int64
+}
+
+// ConfigAdjuntosFromEnv lee ADJUNTO_MAX_MB (10 por defecto)
+func ConfigAdjuntosFromEnv() (ConfigAdjuntos, error) {
+	cfg := ConfigAdjuntos{MaxBytes: 10 << 20}
+
+	if v := os.Getenv("ADJUNTO_MAX_MB"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, fmt.Errorf("ADJUNTO_MAX_MB inválido: %q", v)
+		}
+		cfg.MaxBytes = int64(n) << 20
+	}
+
+	return cfg, nil
+}
+
+// AdjuntoDemasiadoGrandeError indica que el archivo supera ADJUNTO_MAX_MB
+type AdjuntoDemasiadoGrandeError struct {
+	MaxBytes int64
+}
+
+func (e *AdjuntoDemasiadoGrandeError) Error() string {
+	return fmt.Sprintf("El archivo supera el máximo de %d MB", e.MaxBytes>>20)
+}
+
+// SolicitudCerradaError indica que la solicitud está en un estado final y ya no admite adjuntos
+type SolicitudCerradaError struct {
+	Estado model.EstadoAutorizacion
+}
+
+func (e *SolicitudCerradaError) Error() string {
+	return fmt.Sprintf("La solicitud está %s: no admite nuevos adjuntos", e.Estado)
+}
+
+type AdjuntoService interface {
+	Adjuntar(tipo model.TipoSolicitud, solicitudID int, req model.SubirAdjuntoRequest, usuario model.UsuarioAutenticado) (*model.Adjunto, error)
+	GetAdjuntos(tipo model.TipoSolicitud, solicitudID int, usuario model.UsuarioAutenticado) ([]model.Adjunto, error)
+	// Descargar devuelve los metadatos y el contenido; el reader devuelve ErrChecksumAdjunto al
+	// terminar si el contenido no coincide con el SHA-256 guardado
+	Descargar(tipo model.TipoSolicitud, solicitudID int, adjuntoID int, usuario model.UsuarioAutenticado) (*model.Adjunto, io.ReadCloser, error)
+}
+
+type adjuntoServiceImpl struct {
+	repo           repository.AdjuntoRepository
+	storage        storage.Storage
+	autorizaciones repository.AutorizacionRepository
+	reintegros     repository.ReintegroRepository
+	config         ConfigAdjuntos
+	logger         *zap.Logger
+}
+
+func NewAdjuntoService(repo repository.AdjuntoRepository, storage storage.Storage, autorizaciones repository.AutorizacionRepository, reintegros repository.ReintegroRepository, config ConfigAdjuntos, logger *zap.Logger) AdjuntoService {
+	return &adjuntoServiceImpl{
+		repo:           repo,
+		storage:        storage,
+		autorizaciones: autorizaciones,
+		reintegros:     reintegros,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+func (s *adjuntoServiceImpl) Adjuntar(tipo model.TipoSolicitud, solicitudID int, req model.SubirAdjuntoRequest, usuario model.UsuarioAutenticado) (*model.Adjunto, error) {
+	s.logger.Info("Adjuntando archivo",
+		zap.String("tipo", string(tipo)),
+		zap.Int("solicitudId", solicitudID),
+		zap.String("categoria", string(req.Categoria)),
+		zap.String("nombreArchivo", req.NombreArchivo),
+		zap.Int("tamanio", len(req.Contenido)),
+		zap.String("usuario", usuario.Username),
+	)
+
+	estado, err := s.estadoSolicitud(tipo, solicitudID, usuario)
+	if err != nil {
+		return nil, err
+	}
+	// Los estados finales no tienen transiciones de salida
+	if len(transicionesAutorizacion[estado]) == 0 {
+		return nil, &SolicitudCerradaError{Estado: estado}
+	}
+
+	if req.Categoria == "" {
+		req.Categoria = model.CategoriaOtro
+	}
+	if !req.Categoria.Valida() {
+		return nil, &ServiceError{Message: fmt.Sprintf("Categoría inválida: %s. Valores posibles: %s, %s, %s, %s",
+			req.Categoria, model.CategoriaFactura, model.CategoriaTicket, model.CategoriaOrdenMedica, model.CategoriaOtro)}
+	}
+
+	if len(req.Contenido) == 0 {
+		return nil, &ServiceError{Message: "El archivo está vacío"}
+	}
+	if int64(len(req.Contenido)) > s.config.MaxBytes {
+		return nil, &AdjuntoDemasiadoGrandeError{MaxBytes: s.config.MaxBytes}
+	}
+
+	// El tipo se detecta del contenido: el Content-Type y la extensión los decide el cliente
+	contentType, _, _ := strings.Cut(http.DetectContentType(req.Contenido), ";")
+	if !slices.Contains(contentTypesAdjunto, contentType) {
+		return nil, &ServiceError{Message: fmt.Sprintf("Tipo de archivo no admitido (%s). Se aceptan PDF, JPEG y PNG", contentType)}
+	}
+
+	suma := sha256.Sum256(req.Contenido)
+	checksum := hex.EncodeToString(suma[:])
+
+	adjunto := model.Adjunto{
+		TipoSolicitud: tipo,
+		SolicitudID:   solicitudID,
+		Categoria:     req.Categoria,
+		NombreArchivo: nombreArchivoSeguro(req.NombreArchivo, contentType),
+		ContentType:   contentType,
+		Tamanio:       int64(len(req.Contenido)),
+		SHA256:        checksum,
+		Clave:         fmt.Sprintf("%s/%d/%s", strings.ToLower(string(tipo)), solicitudID, checksum),
+		Usuario:       usuario.Username,
+		Rol:           usuario.Rol,
+		FechaCarga:    time.Now().UTC(),
+	}
+
+	// Antes de subir, para no pisar el archivo de un adjunto existente con el mismo contenido
+	existentes, err := s.repo.GetBySolicitud(tipo, solicitudID)
+	if err != nil {
+		s.logger.Error("Error al listar adjuntos", zap.Int("solicitudId", solicitudID), zap.Error(err))
+		return nil, err
+	}
+	for _, e := range existentes {
+		if e.SHA256 == checksum {
+			return nil, ErrAdjuntoDuplicado
+		}
+	}
+
+	if err := s.storage.Guardar(adjunto.Clave, bytes.NewReader(req.Contenido), adjunto.Tamanio, contentType); err != nil {
+		s.logger.Error("Error al guardar adjunto en el storage", zap.String("clave", adjunto.Clave), zap.Error(err))
+		return nil, err
+	}
+
+	creado, err := s.repo.Create(adjunto)
+	if err != nil {
+		// Si otro request subió el mismo archivo a la vez, la clave es la misma y el archivo debe quedar
+		if !errors.Is(err, ErrAdjuntoDuplicado) {
+			if err := s.storage.Eliminar(adjunto.Clave); err != nil {
+				s.logger.Warn("No se pudo eliminar el archivo huérfano", zap.String("clave", adjunto.Clave), zap.Error(err))
+			}
+		}
+		s.logger.Error("Error al registrar adjunto", zap.Int("solicitudId", solicitudID), zap.Error(err))
+		return nil, err
+	}
+
+	return creado, nil
+}
+
+func (s *adjuntoServiceImpl) GetAdjuntos(tipo model.TipoSolicitud, solicitudID int, usuario model.UsuarioAutenticado) ([]model.Adjunto, error) {
+	s.logger.Info("Obteniendo adjuntos",
+		zap.String("tipo", string(tipo)),
+		zap.Int("solicitudId", solicitudID),
+		zap.String("usuario", usuario.Username),
+	)
+
+	if _, err := s.estadoSolicitud(tipo, solicitudID, usuario); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBySolicitud(tipo, solicitudID)
+}
+
+func (s *adjuntoServiceImpl) Descargar(tipo model.TipoSolicitud, solicitudID int, adjuntoID int, usuario model.UsuarioAutenticado) (*model.Adjunto, io.ReadCloser, error) {
+	s.logger.Info("Descargando adjunto",
+		zap.String("tipo", string(tipo)),
+		zap.Int("solicitudId", solicitudID),
+		zap.Int("adjuntoId", adjuntoID),
+		zap.String("usuario", usuario.Username),
+	)
+
+	if _, err := s.estadoSolicitud(tipo, solicitudID, usuario); err != nil {
+		return nil, nil, err
+	}
+
+	adjunto, err := s.repo.GetByID(tipo, solicitudID, adjuntoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contenido, err := s.storage.Abrir(adjunto.Clave)
+	if err != nil {
+		s.logger.Error("Error al abrir adjunto del storage", zap.String("clave", adjunto.Clave), zap.Error(err))
+		return nil, nil, err
+	}
+	defer contenido.Close()
+
+	// Se lee entero y se verifica antes de responder, para no mandar un 200 con un archivo dañado.
+	// El tamaño está acotado por ADJUNTO_MAX_MB al subirlo.
+	datos, err := io.ReadAll(io.LimitReader(contenido, adjunto.Tamanio+1))
+	if err != nil {
+		s.logger.Error("Error al leer adjunto del storage", zap.String("clave", adjunto.Clave), zap.Error(err))
+		return nil, nil, err
+	}
+	suma := sha256.Sum256(datos)
+	if int64(len(datos)) != adjunto.Tamanio || hex.EncodeToString(suma[:]) != adjunto.SHA256 {
+		s.logger.Error("El contenido del adjunto no coincide con su checksum",
+			zap.Int("adjuntoId", adjuntoID),
+			zap.String("clave", adjunto.Clave),
+			zap.String("sha256Esperado", adjunto.SHA256),
+			zap.String("sha256Leido", hex.EncodeToString(suma[:])),
+			zap.Int("bytesLeidos", len(datos)),
+		)
+		return nil, nil, ErrChecksumAdjunto
+	}
+
+	return adjunto, io.NopCloser(bytes.NewReader(datos)), nil
+}
+
+// estadoSolicitud verifica que la solicitud del path exista y que el usuario pueda ver sus adjuntos
+// (un prestador, solo los de las solicitudes que cargó), y devuelve su estado
+func (s *adjuntoServiceImpl) estadoSolicitud(tipo model.TipoSolicitud, solicitudID int, usuario model.UsuarioAutenticado) (model.EstadoAutorizacion, error) {
+	switch tipo {
+	case model.TipoAutorizacion:
+		aut, err := s.autorizaciones.GetByID(solicitudID)
+		if err != nil {
+			s.logger.Warn("Autorización no encontrada", zap.Int("id", solicitudID), zap.Error(err))
+			return "", ErrSolicitudNoEncontrada
+		}
+		if err := verificarPropietario(aut.Prestador.ID, usuario); err != nil {
+			s.logger.Warn("Acceso a adjuntos de una autorización ajena", zap.Int("id", solicitudID), zap.Int("usuarioId", usuario.ID))
+			return "", err
+		}
+		return aut.Estado, nil
+	case model.TipoReintegro:
+		rgt, err := s.reintegros.GetByID(solicitudID)
+		if err != nil {
+			s.logger.Warn("Reintegro no encontrado", zap.Int("id", solicitudID), zap.Error(err))
+			return "", ErrSolicitudNoEncontrada
+		}
+		if err := verificarPropietario(rgt.Prestador.ID, usuario); err != nil {
+			s.logger.Warn("Acceso a adjuntos de un reintegro ajeno", zap.Int("id", solicitudID), zap.Int("usuarioId", usuario.ID))
+			return "", err
+		}
+		return rgt.Estado, nil
+	default:
+		return "", fmt.Errorf("tipo de solicitud sin adjuntos: %s", tipo)
+	}
+}
+
+// nombreArchivoSeguro se queda con el nombre base (sin rutas ni caracteres de control) y usa uno
+// genérico si no queda nada
+func nombreArchivoSeguro(nombre string, contentType string) string {
+	nombre = filepath.Base(strings.ReplaceAll(nombre, `\`, "/"))
+	nombre = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, nombre)
+	if len(nombre) > 200 {
+		nombre = nombre[:200]
+	}
+	nombre = strings.ToValidUTF8(nombre, "")
+	if nombre == "" || nombre == "." || nombre == "/" {
+		_, ext, _ := strings.Cut(contentType, "/")
+		nombre = "adjunto." + ext
+	}
+	return nombre
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"prestadores-api/internal/validacion"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// justificacionMinima es el largo mínimo de la justificación para forzar una receta con advertencias
+const justificacionMinima = 10
+
+// ventanaRecetasRecientes: las recetas creadas en este plazo se comparan aunque ya no estén activas,
+// para detectar la misma droga recetada dos veces en la semana aunque la primera ya se haya dispensado
+const ventanaRecetasRecientes = 7 * 24 * time.Hour
+
+// RecetaConAdvertenciasError indica que la receta duplica o interactúa gravemente con otra receta activa
+// del afiliado y no se pidió forzarla
+type RecetaConAdvertenciasError struct {
+	Advertencias []model.AdvertenciaReceta
+}
+
+func (e *RecetaConAdvertenciasError) Error() string {
+	mensajes := make([]string, 0, len(e.Advertencias))
+	for _, a := range e.Advertencias {
+		if a.Bloqueante {
+			mensajes = append(mensajes, a.Mensaje)
+		}
+	}
+	return fmt.Sprintf("La receta tiene advertencias: %s. Para guardarla igual enviar forzar con una justificación",
+		strings.Join(mensajes, "; "))
+}
+
+// advertenciasReceta compara la droga de la receta nueva con las recetas activas del afiliado.
+// Los duplicados y las interacciones graves bloquean el alta; las leves y moderadas solo se informan.
+func advertenciasReceta(droga string, activas []model.RecetaListItem, interacciones repository.InteraccionRepository) []model.AdvertenciaReceta {
+	var advertencias []model.AdvertenciaReceta
+	pares := interacciones.GetByDroga(droga)
+	// Las drogas se comparan sin mayúsculas ni tildes ("Losartán" = "losartan")
+	nueva := validacion.NormalizarTexto(droga)
+
+	for _, activa := range activas {
+		existente := validacion.NormalizarTexto(activa.DrogaGenerica)
+		if existente == nueva {
+			advertencias = append(advertencias, model.AdvertenciaReceta{
+				Tipo:          model.AdvertenciaDuplicado,
+				Bloqueante:    true,
+				RecetaID:      activa.ID,
+				DrogaGenerica: activa.DrogaGenerica,
+				Medicamento:   activa.Medicamento,
+				Mensaje: fmt.Sprintf("El afiliado ya tiene la receta %d de %s en estado %s",
+					activa.ID, activa.DrogaGenerica, activa.Estado),
+			})
+			continue
+		}
+
+		for _, par := range pares {
+			otra := par.DrogaB
+			if validacion.NormalizarTexto(otra) == nueva {
+				otra = par.DrogaA
+			}
+			if validacion.NormalizarTexto(otra) != existente {
+				continue
+			}
+			advertencias = append(advertencias, model.AdvertenciaReceta{
+				Tipo:          model.AdvertenciaInteraccion,
+				Severidad:     par.Severidad,
+				Bloqueante:    par.Severidad == model.SeveridadGrave,
+				RecetaID:      activa.ID,
+				DrogaGenerica: activa.DrogaGenerica,
+				Medicamento:   activa.Medicamento,
+				Mensaje: fmt.Sprintf("Interacción %s con %s (receta %d): %s",
+					par.Severidad, activa.DrogaGenerica, activa.ID, par.Descripcion),
+			})
+		}
+	}
+	return advertencias
+}
+
+func tieneBloqueantes(advertencias []model.AdvertenciaReceta) bool {
+	for _, a := range advertencias {
+		if a.Bloqueante {
+			return true
+		}
+	}
+	return false
+}
+
+// controlarForzado deja pasar una receta con advertencias bloqueantes solo si se pidió forzarla con una
+// justificación. Devuelve el motivo que queda en el historial, vacío si no hubo que forzar nada.
+// operacion es "Creada" o "Modificada", según se esté creando la receta o cambiando su medicamento.
+func controlarForzado(advertencias []model.AdvertenciaReceta, forzar bool, justificacion string, operacion string) (string, error) {
+	if !tieneBloqueantes(advertencias) {
+		return "", nil
+	}
+	if !forzar {
+		return "", &RecetaConAdvertenciasError{Advertencias: advertencias}
+	}
+
+	justificacion = strings.TrimSpace(justificacion)
+	if utf8.RuneCountInString(justificacion) < justificacionMinima {
+		return "", &ServiceError{Message: fmt.Sprintf("Para forzar la receta se requiere una justificación de al menos %d caracteres", justificacionMinima)}
+	}
+
+	var forzadas []string
+	for _, a := range advertencias {
+		if a.Bloqueante {
+			forzadas = append(forzadas, fmt.Sprintf("%s con receta %d", a.Tipo, a.RecetaID))
+		}
+	}
+	return fmt.Sprintf("%s forzando advertencias (%s). Justificación: %s", operacion, strings.Join(forzadas, ", "), justificacion), nil
+}
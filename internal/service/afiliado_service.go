@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// ErrAfiliadoNoEncontrado indica que el afiliado no existe en el padrón
+var ErrAfiliadoNoEncontrado = repository.ErrAfiliadoNoEncontrado
+
+// AfiliadoInexistenteError indica que se referenció un afiliado que no está en el padrón
+type AfiliadoInexistenteError struct {
+	ID int
+}
+
+func (e *AfiliadoInexistenteError) Error() string {
+	return fmt.Sprintf("El afiliado %d no existe en el padrón", e.ID)
+}
+
+// resolverAfiliado busca el afiliado en el padrón, devolviendo AfiliadoInexistenteError si no está
+func resolverAfiliado(repo repository.AfiliadoRepository, id int) (*model.Afiliado, error) {
+	afiliado, err := repo.GetByID(id)
+	if errors.Is(err, repository.ErrAfiliadoNoEncontrado) {
+		return nil, &AfiliadoInexistenteError{ID: id}
+	}
+	return afiliado, err
+}
+
+type AfiliadoService interface {
+	GetAfiliados(filtro model.AfiliadoFiltro, page int, size int) (*model.PaginatedAfiliadosResponse, error)
+	GetAfiliadoDetalle(id int) (*model.AfiliadoDetalle, error)
+}
+
+type afiliadoServiceImpl struct {
+	repo   repository.AfiliadoRepository
+	planes repository.PlanRepository
+	logger *zap.Logger
+}
+
+func NewAfiliadoService(repo repository.AfiliadoRepository, planes repository.PlanRepository, logger *zap.Logger) AfiliadoService {
+	return &afiliadoServiceImpl{
+		repo:   repo,
+		planes: planes,
+		logger: logger,
+	}
+}
+
+func (s *afiliadoServiceImpl) GetAfiliados(filtro model.AfiliadoFiltro, page int, size int) (*model.PaginatedAfiliadosResponse, error) {
+	s.logger.Info("Obteniendo afiliados",
+		zap.Int("planId", filtro.PlanID),
+		zap.Boolp("titular", filtro.Titular),
+		zap.String("query", filtro.Query),
+		zap.Int("page", page),
+		zap.Int("size", size),
+	)
+
+	afiliados, total, err := s.repo.GetAll(filtro, page, size)
+	if err != nil {
+		s.logger.Error("Error al obtener afiliados", zap.Error(err))
+		return nil, err
+	}
+
+	nombres, err := s.nombresPlanes()
+	if err != nil {
+		s.logger.Error("Error al obtener planes", zap.Error(err))
+		return nil, err
+	}
+
+	items := make([]model.AfiliadoListItem, 0, len(afiliados))
+	for _, a := range afiliados {
+		items = append(items, model.AfiliadoListItem{
+			ID:           a.ID,
+			NroAfiliado:  a.NroAfiliado,
+			DNI:          a.DNI,
+			Nombre:       a.Nombre,
+			Apellido:     a.Apellido,
+			PlanMedicoID: a.PlanMedicoID,
+			PlanMedico:   nombres[a.PlanMedicoID],
+			Titular:      a.EsTitular(),
+		})
+	}
+
+	response := &model.PaginatedAfiliadosResponse{
+		Page:  page,
+		Size:  size,
+		Total: total,
+		Items: items,
+	}
+
+	return response, nil
+}
+
+// GetAfiliadoDetalle devuelve el afiliado con su grupo familiar completo,
+// sea el afiliado el titular o uno de los integrantes
+func (s *afiliadoServiceImpl) GetAfiliadoDetalle(id int) (*model.AfiliadoDetalle, error) {
+	s.logger.Info("Obteniendo detalle de afiliado", zap.Int("id", id))
+
+	afiliado, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener afiliado", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	titular := afiliado
+	if !afiliado.EsTitular() {
+		titular, err = s.repo.GetByID(*afiliado.TitularID)
+		if err != nil {
+			s.logger.Error("Titular del grupo familiar inexistente",
+				zap.Int("id", id),
+				zap.Int("titularId", *afiliado.TitularID),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	}
+
+	integrantes, err := s.repo.GetIntegrantes(titular.ID)
+	if err != nil {
+		s.logger.Error("Error al obtener grupo familiar", zap.Int("titularId", titular.ID), zap.Error(err))
+		return nil, err
+	}
+
+	nombres, err := s.nombresPlanes()
+	if err != nil {
+		s.logger.Error("Error al obtener planes", zap.Error(err))
+		return nil, err
+	}
+	afiliado.PlanMedico = nombres[afiliado.PlanMedicoID]
+
+	grupo := model.GrupoFamiliar{
+		Titular:     integranteGrupo(*titular, nombres),
+		Integrantes: make([]model.IntegranteGrupo, 0, len(integrantes)),
+	}
+	for _, i := range integrantes {
+		grupo.Integrantes = append(grupo.Integrantes, integranteGrupo(i, nombres))
+	}
+
+	detalle := &model.AfiliadoDetalle{
+		Afiliado:      *afiliado,
+		Titular:       afiliado.EsTitular(),
+		GrupoFamiliar: grupo,
+	}
+
+	return detalle, nil
+}
+
+// nombresPlanes arma el mapa ID -> nombre del catálogo para completar las respuestas
+func (s *afiliadoServiceImpl) nombresPlanes() (map[int]string, error) {
+	planes, err := s.planes.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	nombres := make(map[int]string, len(planes))
+	for _, p := range planes {
+		nombres[p.ID] = p.Nombre
+	}
+	return nombres, nil
+}
+
+func integranteGrupo(a model.Afiliado, nombresPlanes map[int]string) model.IntegranteGrupo {
+	return model.IntegranteGrupo{
+		ID:           a.ID,
+		NroAfiliado:  a.NroAfiliado,
+		DNI:          a.DNI,
+		Nombre:       a.Nombre,
+		Apellido:     a.Apellido,
+		PlanMedicoID: a.PlanMedicoID,
+		PlanMedico:   nombresPlanes[a.PlanMedicoID],
+		Parentesco:   a.Parentesco,
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/auth"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"prestadores-api/internal/validacion"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type AuthService interface {
+	Login(req model.LoginRequest) (*model.LoginResponse, error)
+	Refresh(refreshToken string) (*model.LoginResponse, error)
+	// Logout revoca el refresh token, que debe ser del usuario autenticado
+	Logout(refreshToken string, usuario model.UsuarioAutenticado) error
+}
+
+type authServiceImpl struct {
+	usuarios      repository.UsuarioRepository
+	refreshTokens repository.RefreshTokenRepository
+	tokens        *auth.TokenManager
+	limitador     *auth.LimitadorIntentos
+	refreshTTL    time.Duration
+	logger        *zap.Logger
+}
+
+func NewAuthService(
+	usuarios repository.UsuarioRepository,
+	refreshTokens repository.RefreshTokenRepository,
+	tokens *auth.TokenManager,
+	limitador *auth.LimitadorIntentos,
+	refreshTTL time.Duration,
+	logger *zap.Logger,
+) AuthService {
+	return &authServiceImpl{
+		usuarios:      usuarios,
+		refreshTokens: refreshTokens,
+		tokens:        tokens,
+		limitador:     limitador,
+		refreshTTL:    refreshTTL,
+		logger:        logger,
+	}
+}
+
+// Errores de autenticación
+var (
+	ErrCredencialesInvalidas = &ServiceError{Message: "CUIT o contraseña incorrectos"}
+	ErrRefreshTokenInvalido  = &ServiceError{Message: "Refresh token inválido o expirado"}
+)
+
+// UsuarioBloqueadoError indica que el CUIT superó la cantidad de intentos fallidos
+type UsuarioBloqueadoError struct {
+	Hasta time.Time
+}
+
+func (e *UsuarioBloqueadoError) Error() string {
+	return fmt.Sprintf("Usuario bloqueado por intentos fallidos hasta %s", e.Hasta.Format(time.RFC3339))
+}
+
+func (s *authServiceImpl) Login(req model.LoginRequest) (*model.LoginResponse, error) {
+	s.logger.Info("Login de prestador", zap.String("cuit", req.Username))
+
+	// El bloqueo y la búsqueda usan el CUIT normalizado: "20-25123456-7" y "20251234567" son el mismo usuario
+	cuit, err := validacion.NormalizarCUIT(req.Username)
+	if err != nil {
+		s.logger.Warn("CUIT inválido en login", zap.String("cuit", req.Username), zap.Error(err))
+		return nil, &ServiceError{Message: err.Error()}
+	}
+
+	if bloqueado, hasta := s.limitador.Bloqueado(cuit); bloqueado {
+		s.logger.Warn("Login de usuario bloqueado", zap.String("cuit", cuit), zap.Time("hasta", hasta))
+		return nil, &UsuarioBloqueadoError{Hasta: hasta}
+	}
+
+	usuario, err := s.usuarios.GetByCUIT(cuit)
+	hash := ""
+	if err == nil && usuario.Activo {
+		hash = usuario.PasswordHash
+	}
+
+	if !auth.CheckPassword(hash, req.Password) {
+		if bloqueado, hasta := s.limitador.RegistrarFallo(cuit); bloqueado {
+			s.logger.Warn("Usuario bloqueado por intentos fallidos", zap.String("cuit", cuit), zap.Time("hasta", hasta))
+			return nil, &UsuarioBloqueadoError{Hasta: hasta}
+		}
+		s.logger.Warn("Credenciales inválidas", zap.String("cuit", cuit))
+		return nil, ErrCredencialesInvalidas
+	}
+
+	s.limitador.Reset(cuit)
+
+	return s.emitirTokens(usuario)
+}
+
+func (s *authServiceImpl) Refresh(refreshToken string) (*model.LoginResponse, error) {
+	hash := auth.HashRefreshToken(refreshToken)
+
+	token, err := s.refreshTokens.GetByHash(hash)
+	if err != nil || token.Revocado || time.Now().After(token.ExpiraEn) {
+		s.logger.Warn("Refresh token inválido")
+		return nil, ErrRefreshTokenInvalido
+	}
+
+	usuario, err := s.usuarios.GetByID(token.UsuarioID)
+	if err != nil || !usuario.Activo {
+		s.logger.Warn("Refresh token de usuario inexistente o inactivo", zap.Int("usuarioId", token.UsuarioID))
+		return nil, ErrRefreshTokenInvalido
+	}
+
+	// Rotación: el refresh token usado deja de ser válido. La revocación es condicional: si otro
+	// request lo usó primero, este no emite tokens.
+	if err := s.refreshTokens.Revoke(hash); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenRevocado) {
+			s.logger.Warn("Refresh token reutilizado", zap.Int("usuarioId", token.UsuarioID))
+			return nil, ErrRefreshTokenInvalido
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Refresh de tokens", zap.Int("usuarioId", usuario.ID))
+	return s.emitirTokens(usuario)
+}
+
+func (s *authServiceImpl) Logout(refreshToken string, usuario model.UsuarioAutenticado) error {
+	hash := auth.HashRefreshToken(refreshToken)
+
+	// Un token de otro usuario se trata como inválido para no revelar que existe
+	token, err := s.refreshTokens.GetByHash(hash)
+	if err != nil || token.Revocado || token.UsuarioID != usuario.ID {
+		s.logger.Warn("Logout con refresh token inválido", zap.Int("usuarioId", usuario.ID))
+		return ErrRefreshTokenInvalido
+	}
+
+	if err := s.refreshTokens.Revoke(hash); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenRevocado) {
+			return ErrRefreshTokenInvalido
+		}
+		s.logger.Error("Error al revocar refresh token", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Logout de prestador", zap.Int("usuarioId", token.UsuarioID))
+	return nil
+}
+
+func (s *authServiceImpl) emitirTokens(usuario *model.Usuario) (*model.LoginResponse, error) {
+	accessToken, _, err := s.tokens.GenerarAccessToken(*usuario)
+	if err != nil {
+		s.logger.Error("Error al generar access token", zap.Error(err))
+		return nil, err
+	}
+
+	refreshToken, hash, err := auth.GenerarRefreshToken()
+	if err != nil {
+		s.logger.Error("Error al generar refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.refreshTokens.Save(model.RefreshToken{
+		Hash:      hash,
+		UsuarioID: usuario.ID,
+		CreadoEn:  now,
+		ExpiraEn:  now.Add(s.refreshTTL),
+	})
+	if err != nil {
+		s.logger.Error("Error al guardar refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.AccessTTL().Seconds()),
+		RefreshToken: refreshToken,
+		Usuario:      *usuario,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/repository"
 
@@ -8,36 +9,57 @@ import (
 )
 
 type AutorizacionService interface {
-	GetAutorizaciones(estado string, query string, page int, size int, sort string) (*model.PaginatedAutorizacionesResponse, error)
+	GetAutorizaciones(estado string, especialidad string, query string, page int, size int, sort string) (*model.PaginatedAutorizacionesResponse, error)
 	GetAutorizacionByID(id int) (*model.AutorizacionDetalle, error)
-	CreateAutorizacion(req model.CreateAutorizacionRequest) (*model.CreateAutorizacionResponse, error)
+	CreateAutorizacion(req model.CreateAutorizacionRequest, usuario model.UsuarioAutenticado) (*model.CreateAutorizacionResponse, error)
 	UpdateAutorizacion(id int, req model.UpdateAutorizacionRequest) error
-	CambiarEstadoAutorizacion(id int, req model.CambioEstadoRequest) (*model.CambioEstadoResponse, error)
+	CambiarEstadoAutorizacion(id int, req model.CambioEstadoRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoResponse, error)
 }
 
 type autorizacionServiceImpl struct {
-	repo   repository.AutorizacionRepository
-	logger *zap.Logger
+	repo           repository.AutorizacionRepository
+	afiliados      repository.AfiliadoRepository
+	nomenclador    repository.NomencladorRepository
+	especialidades repository.EspecialidadRepository
+	elegibilidad   ElegibilidadService
+	adjuntos       repository.AdjuntoRepository
+	logger         *zap.Logger
 }
 
-func NewAutorizacionService(repo repository.AutorizacionRepository, logger *zap.Logger) AutorizacionService {
+func NewAutorizacionService(repo repository.AutorizacionRepository, afiliados repository.AfiliadoRepository, nomenclador repository.NomencladorRepository, especialidades repository.EspecialidadRepository, elegibilidad ElegibilidadService, adjuntos repository.AdjuntoRepository, logger *zap.Logger) AutorizacionService {
 	return &autorizacionServiceImpl{
-		repo:   repo,
-		logger: logger,
+		repo:           repo,
+		afiliados:      afiliados,
+		nomenclador:    nomenclador,
+		especialidades: especialidades,
+		elegibilidad:   elegibilidad,
+		adjuntos:       adjuntos,
+		logger:         logger,
 	}
 }
 
-func (s *autorizacionServiceImpl) GetAutorizaciones(estado string, query string, page int, size int, sort string) (*model.PaginatedAutorizacionesResponse, error) {
+func (s *autorizacionServiceImpl) GetAutorizaciones(estado string, especialidad string, query string, page int, size int, sort string) (*model.PaginatedAutorizacionesResponse, error) {
 	s.logger.Info("Obteniendo autorizaciones",
 		zap.String("estado", estado),
+		zap.String("especialidad", especialidad),
 		zap.String("query", query),
 		zap.Int("page", page),
 		zap.Int("size", size),
 		zap.String("sort", sort),
 	)
 
-	items, total, err := s.repo.GetAll(estado, query, page, size, sort)
+	especialidadCodigo, err := validarFiltroEspecialidad(s.especialidades, especialidad)
 	if err != nil {
+		s.logger.Warn("Filtro de especialidad inválido", zap.String("especialidad", especialidad), zap.Error(err))
+		return nil, err
+	}
+
+	items, total, err := s.repo.GetAll(estado, especialidadCodigo, query, page, size, sort)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrdenInvalido) {
+			s.logger.Warn("Parámetro sort inválido", zap.String("sort", sort), zap.Error(err))
+			return nil, &ServiceError{Message: err.Error()}
+		}
 		s.logger.Error("Error al obtener autorizaciones", zap.Error(err))
 		return nil, err
 	}
@@ -61,16 +83,61 @@ func (s *autorizacionServiceImpl) GetAutorizacionByID(id int) (*model.Autorizaci
 		return nil, err
 	}
 
-	return detalle, nil
+	// Copia para no tocar el detalle que guarda el repositorio en memoria
+	conAdjuntos := *detalle
+	conAdjuntos.Adjuntos, err = s.adjuntos.GetBySolicitud(model.TipoAutorizacion, id)
+	if err != nil {
+		s.logger.Error("Error al obtener adjuntos", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &conAdjuntos, nil
 }
 
-func (s *autorizacionServiceImpl) CreateAutorizacion(req model.CreateAutorizacionRequest) (*model.CreateAutorizacionResponse, error) {
+func (s *autorizacionServiceImpl) CreateAutorizacion(req model.CreateAutorizacionRequest, usuario model.UsuarioAutenticado) (*model.CreateAutorizacionResponse, error) {
+	// El historial registra al prestador autenticado, nunca un dato del body
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+	req.Prestador = model.PrestadorBasico{ID: usuario.ID, CUIT: usuario.CUIT, Nombre: usuario.Nombre}
+
 	s.logger.Info("Creando autorización",
 		zap.Int("afiliadoId", req.AfiliadoID),
-		zap.String("procedimiento", req.Procedimiento),
-		zap.String("especialidad", req.Especialidad),
+		zap.String("procedimientoCodigo", req.ProcedimientoCodigo),
 	)
 
+	if err := validarEstadoInicial(req.EstadoInicial, model.EstadoRecibido); err != nil {
+		s.logger.Warn("Estado inicial inválido", zap.String("estadoInicial", string(req.EstadoInicial)))
+		return nil, err
+	}
+
+	// Descripción y especialidad salen del nomenclador, no del texto que mande el prestador
+	procedimiento, err := resolverPrestacion(s.nomenclador, req.ProcedimientoCodigo)
+	if err != nil {
+		s.logger.Warn("Código de procedimiento inválido", zap.String("procedimientoCodigo", req.ProcedimientoCodigo), zap.Error(err))
+		return nil, err
+	}
+	if err := verificarEspecialidadPrestacion(s.especialidades, req.EspecialidadCodigo, procedimiento); err != nil {
+		s.logger.Warn("Especialidad inválida", zap.String("especialidadCodigo", req.EspecialidadCodigo), zap.Error(err))
+		return nil, err
+	}
+	req.ProcedimientoCodigo = procedimiento.Codigo
+	req.Procedimiento = procedimiento.Descripcion
+	req.EspecialidadCodigo = procedimiento.EspecialidadCodigo
+	req.Especialidad = procedimiento.Especialidad
+
+	// Los datos del afiliado se copian del padrón al momento de crear la solicitud
+	afiliado, err := resolverAfiliado(s.afiliados, req.AfiliadoID)
+	if err != nil {
+		s.logger.Warn("Afiliado inexistente", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := verificarElegible(s.elegibilidad, *afiliado, req.Especialidad); err != nil {
+		s.logger.Warn("Afiliado no elegible", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+	req.Afiliado = afiliado.Basico()
+
 	detalle, err := s.repo.Create(req)
 	if err != nil {
 		s.logger.Error("Error al crear autorización", zap.Error(err))
@@ -90,11 +157,41 @@ func (s *autorizacionServiceImpl) CreateAutorizacion(req model.CreateAutorizacio
 func (s *autorizacionServiceImpl) UpdateAutorizacion(id int, req model.UpdateAutorizacionRequest) error {
 	s.logger.Info("Actualizando autorización",
 		zap.Int("id", id),
-		zap.String("procedimiento", req.Procedimiento),
-		zap.String("especialidad", req.Especialidad),
+		zap.String("procedimientoCodigo", req.ProcedimientoCodigo),
 	)
 
-	err := s.repo.Update(id, req)
+	actual, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener autorización", zap.Int("id", id), zap.Error(err))
+		return err
+	}
+	if err := validarEditable(actual.Estado, model.EstadoRecibido, model.EstadoObservado); err != nil {
+		s.logger.Warn("Autorización no editable", zap.Int("id", id), zap.String("estado", string(actual.Estado)))
+		return err
+	}
+
+	// La especialidad sigue al procedimiento: enviada sola, se valida contra el procedimiento actual
+	if req.EspecialidadCodigo != "" && req.ProcedimientoCodigo == "" {
+		req.ProcedimientoCodigo = actual.ProcedimientoCodigo
+	}
+
+	// Un cambio de procedimiento puede cambiar la especialidad: se vuelve a verificar la elegibilidad
+	if req.ProcedimientoCodigo != "" {
+		if err := s.resolverCambioProcedimiento(actual, &req); err != nil {
+			s.logger.Warn("No se pudo cambiar el procedimiento", zap.Int("id", id), zap.Error(err))
+			return err
+		}
+	}
+
+	err = s.repo.Update(id, req)
+	if errors.Is(err, repository.ErrEstadoModificado) {
+		// El auditor la tomó después de validar que era editable: se responde con el estado nuevo
+		s.logger.Warn("Autorización modificada concurrentemente", zap.Int("id", id), zap.String("estadoEsperado", string(actual.Estado)))
+		if actual, err = s.repo.GetByID(id); err != nil {
+			return err
+		}
+		return solicitudNoEditable(actual.Estado, model.EstadoRecibido, model.EstadoObservado)
+	}
 	if err != nil {
 		s.logger.Error("Error al actualizar autorización", zap.Int("id", id), zap.Error(err))
 		return err
@@ -103,7 +200,10 @@ func (s *autorizacionServiceImpl) UpdateAutorizacion(id int, req model.UpdateAut
 	return nil
 }
 
-func (s *autorizacionServiceImpl) CambiarEstadoAutorizacion(id int, req model.CambioEstadoRequest) (*model.CambioEstadoResponse, error) {
+func (s *autorizacionServiceImpl) CambiarEstadoAutorizacion(id int, req model.CambioEstadoRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoResponse, error) {
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+
 	s.logger.Info("Cambiando estado de autorización",
 		zap.Int("id", id),
 		zap.String("nuevoEstado", string(req.NuevoEstado)),
@@ -118,9 +218,31 @@ func (s *autorizacionServiceImpl) CambiarEstadoAutorizacion(id int, req model.Ca
 		return nil, ErrMotivoRequerido
 	}
 
-	// aca tenemos q agregar las validaciones adicionales q tengamos
+	actual, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener autorización", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if err := validarTransicion(transicionesAutorizacion, actual.Estado, req.NuevoEstado, usuario.Rol); err != nil {
+		s.logger.Warn("Transición de estado no permitida",
+			zap.Int("id", id),
+			zap.String("estadoActual", string(actual.Estado)),
+			zap.String("nuevoEstado", string(req.NuevoEstado)),
+			zap.String("rol", string(usuario.Rol)),
+		)
+		return nil, err
+	}
 
-	detalle, err := s.repo.CambiarEstado(id, req)
+	detalle, err := s.repo.CambiarEstado(id, actual.Estado, req)
+	if errors.Is(err, repository.ErrEstadoModificado) {
+		// Otro usuario la cambió después de validar la transición: se responde con el estado nuevo
+		s.logger.Warn("Cambio de estado concurrente", zap.Int("id", id), zap.String("estadoEsperado", string(actual.Estado)))
+		if actual, err = s.repo.GetByID(id); err != nil {
+			return nil, err
+		}
+		return nil, transicionInvalida(transicionesAutorizacion, actual.Estado, req.NuevoEstado)
+	}
 	if err != nil {
 		s.logger.Error("Error al cambiar estado de autorización", zap.Int("id", id), zap.Error(err))
 		return nil, err
@@ -136,6 +258,32 @@ func (s *autorizacionServiceImpl) CambiarEstadoAutorizacion(id int, req model.Ca
 	return response, nil
 }
 
+// resolverCambioProcedimiento completa el procedimiento nuevo desde el nomenclador y verifica la
+// especialidad y la elegibilidad del afiliado de la autorización actual
+func (s *autorizacionServiceImpl) resolverCambioProcedimiento(actual *model.AutorizacionDetalle, req *model.UpdateAutorizacionRequest) error {
+	procedimiento, err := resolverPrestacion(s.nomenclador, req.ProcedimientoCodigo)
+	if err != nil {
+		return err
+	}
+	if err := verificarEspecialidadPrestacion(s.especialidades, req.EspecialidadCodigo, procedimiento); err != nil {
+		return err
+	}
+
+	afiliado, err := resolverAfiliado(s.afiliados, actual.Afiliado.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := verificarElegible(s.elegibilidad, *afiliado, procedimiento.Especialidad); err != nil {
+		return err
+	}
+
+	req.ProcedimientoCodigo = procedimiento.Codigo
+	req.Procedimiento = procedimiento.Descripcion
+	req.EspecialidadCodigo = procedimiento.EspecialidadCodigo
+	req.Especialidad = procedimiento.Especialidad
+	return nil
+}
+
 // Errores personalizados del servicio
 var (
 	ErrMotivoRequerido = &ServiceError{Message: "El motivo es obligatorio para estados OBSERVADO y RECHAZADO"}
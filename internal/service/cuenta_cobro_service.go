@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"prestadores-api/internal/validacion"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCuentaCobroNoEncontrada = repository.ErrCuentaCobroNoEncontrada
+	ErrCuentaCobroDuplicada    = repository.ErrCuentaCobroDuplicada
+)
+
+type CuentaCobroService interface {
+	GetCuentas(afiliadoID int) ([]model.CuentaCobro, error)
+	CreateCuenta(afiliadoID int, req model.CreateCuentaCobroRequest, usuario model.UsuarioAutenticado) (*model.CuentaCobro, error)
+	// BajaCuenta es lógica: los reintegros que ya la usan siguen mostrándola
+	BajaCuenta(afiliadoID int, cuentaID int, usuario model.UsuarioAutenticado) error
+}
+
+type cuentaCobroServiceImpl struct {
+	repo      repository.CuentaCobroRepository
+	afiliados repository.AfiliadoRepository
+	logger    *zap.Logger
+}
+
+func NewCuentaCobroService(repo repository.CuentaCobroRepository, afiliados repository.AfiliadoRepository, logger *zap.Logger) CuentaCobroService {
+	return &cuentaCobroServiceImpl{
+		repo:      repo,
+		afiliados: afiliados,
+		logger:    logger,
+	}
+}
+
+func (s *cuentaCobroServiceImpl) GetCuentas(afiliadoID int) ([]model.CuentaCobro, error) {
+	s.logger.Info("Obteniendo cuentas de cobro", zap.Int("afiliadoId", afiliadoID))
+
+	if _, err := s.afiliados.GetByID(afiliadoID); err != nil {
+		s.logger.Warn("Afiliado no encontrado", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+	return s.repo.GetByAfiliado(afiliadoID)
+}
+
+func (s *cuentaCobroServiceImpl) CreateCuenta(afiliadoID int, req model.CreateCuentaCobroRequest, usuario model.UsuarioAutenticado) (*model.CuentaCobro, error) {
+	s.logger.Info("Registrando cuenta de cobro",
+		zap.Int("afiliadoId", afiliadoID),
+		zap.String("alias", req.Alias),
+		zap.String("usuario", usuario.Username),
+	)
+
+	if _, err := s.afiliados.GetByID(afiliadoID); err != nil {
+		s.logger.Warn("Afiliado no encontrado", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	cbu, err := validacion.NormalizarCBU(req.CBU)
+	if err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("CBU/CVU inválido: %v", err)}
+	}
+
+	var alias string
+	if strings.TrimSpace(req.Alias) != "" {
+		if alias, err = validacion.NormalizarAlias(req.Alias); err != nil {
+			return nil, &ServiceError{Message: fmt.Sprintf("Alias inválido: %v", err)}
+		}
+	}
+
+	cuit, err := validacion.NormalizarCUIT(req.TitularCUIT)
+	if err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("CUIT/CUIL del titular inválido: %v", err)}
+	}
+
+	nombre := strings.TrimSpace(req.TitularNombre)
+	if nombre == "" {
+		return nil, &ServiceError{Message: "El nombre del titular de la cuenta es obligatorio"}
+	}
+
+	cuenta := model.CuentaCobro{
+		AfiliadoID:    afiliadoID,
+		Tipo:          model.CuentaCBU,
+		CBU:           cbu,
+		Alias:         alias,
+		TitularCUIT:   cuit,
+		TitularNombre: nombre,
+		Usuario:       usuario.Username,
+		FechaAlta:     time.Now().UTC(),
+	}
+	if validacion.EsCVU(cbu) {
+		cuenta.Tipo = model.CuentaCVU
+	}
+
+	creada, err := s.repo.Create(cuenta)
+	if err != nil {
+		s.logger.Error("Error al registrar cuenta de cobro", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+	return creada, nil
+}
+
+func (s *cuentaCobroServiceImpl) BajaCuenta(afiliadoID int, cuentaID int, usuario model.UsuarioAutenticado) error {
+	s.logger.Info("Dando de baja cuenta de cobro",
+		zap.Int("afiliadoId", afiliadoID),
+		zap.Int("cuentaId", cuentaID),
+		zap.String("usuario", usuario.Username),
+	)
+
+	// Una cuenta de otro afiliado se trata como inexistente
+	cuenta, err := s.repo.GetByID(cuentaID)
+	if err != nil {
+		return err
+	}
+	if cuenta.AfiliadoID != afiliadoID {
+		return ErrCuentaCobroNoEncontrada
+	}
+
+	if err := s.repo.Baja(cuentaID, time.Now().UTC()); err != nil {
+		s.logger.Error("Error al dar de baja cuenta de cobro", zap.Int("cuentaId", cuentaID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// resolverCuentaCobro valida el método de pago de un reintegro: TRANSFERENCIA exige una cuenta
+// activa del mismo afiliado y EFECTIVO no admite cuenta. Devuelve el método normalizado.
+func resolverCuentaCobro(repo repository.CuentaCobroRepository, metodo model.MetodoReintegro, cuentaID *int, afiliadoID int) (model.MetodoReintegro, error) {
+	metodo = model.MetodoReintegro(strings.ToUpper(strings.TrimSpace(string(metodo))))
+	if !metodo.Valido() {
+		return "", &ServiceError{Message: fmt.Sprintf("Método de reintegro inválido: %q. Valores posibles: %s, %s",
+			metodo, model.MetodoEfectivo, model.MetodoTransferencia)}
+	}
+
+	if metodo == model.MetodoEfectivo {
+		if cuentaID != nil {
+			return "", &ServiceError{Message: fmt.Sprintf("Un reintegro en %s no lleva cuentaCobroId", model.MetodoEfectivo)}
+		}
+		return metodo, nil
+	}
+
+	if cuentaID == nil {
+		return "", &ServiceError{Message: fmt.Sprintf("Un reintegro por %s requiere cuentaCobroId: registrar la cuenta en /afiliados/%d/cuentas-cobro",
+			model.MetodoTransferencia, afiliadoID)}
+	}
+	cuenta, err := repo.GetByID(*cuentaID)
+	if errors.Is(err, repository.ErrCuentaCobroNoEncontrada) || (err == nil && cuenta.AfiliadoID != afiliadoID) {
+		return "", &ServiceError{Message: fmt.Sprintf("La cuenta de cobro %d no existe o no es del afiliado %d", *cuentaID, afiliadoID)}
+	}
+	if err != nil {
+		return "", err
+	}
+	if !cuenta.Activa() {
+		return "", &ServiceError{Message: fmt.Sprintf("La cuenta de cobro %d está dada de baja", *cuentaID)}
+	}
+	return metodo, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AfiliadoNoElegibleError indica que el afiliado no puede recibir la prestación solicitada
+type AfiliadoNoElegibleError struct {
+	Elegibilidad model.Elegibilidad
+}
+
+func (e *AfiliadoNoElegibleError) Error() string {
+	detalles := make([]string, 0, len(e.Elegibilidad.Motivos))
+	for _, m := range e.Elegibilidad.Motivos {
+		detalles = append(detalles, m.Detalle)
+	}
+	return fmt.Sprintf("El afiliado %d no es elegible para %s: %s",
+		e.Elegibilidad.AfiliadoID, e.Elegibilidad.Prestacion, strings.Join(detalles, "; "))
+}
+
+type ElegibilidadService interface {
+	VerificarElegibilidad(afiliadoID int, prestacion string) (*model.Elegibilidad, error)
+	// EvaluarAfiliado evalúa un afiliado ya resuelto del padrón (lo usan los services de solicitudes)
+	EvaluarAfiliado(afiliado model.Afiliado, prestacion string) (*model.Elegibilidad, error)
+}
+
+type elegibilidadServiceImpl struct {
+	afiliados repository.AfiliadoRepository
+	planes    repository.PlanRepository
+	logger    *zap.Logger
+}
+
+func NewElegibilidadService(afiliados repository.AfiliadoRepository, planes repository.PlanRepository, logger *zap.Logger) ElegibilidadService {
+	return &elegibilidadServiceImpl{
+		afiliados: afiliados,
+		planes:    planes,
+		logger:    logger,
+	}
+}
+
+func (s *elegibilidadServiceImpl) VerificarElegibilidad(afiliadoID int, prestacion string) (*model.Elegibilidad, error) {
+	s.logger.Info("Verificando elegibilidad", zap.Int("afiliadoId", afiliadoID), zap.String("prestacion", prestacion))
+
+	if strings.TrimSpace(prestacion) == "" {
+		return nil, &ServiceError{Message: "El parámetro 'prestacion' es obligatorio"}
+	}
+
+	afiliado, err := s.afiliados.GetByID(afiliadoID)
+	if err != nil {
+		s.logger.Warn("Afiliado no encontrado", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	return s.EvaluarAfiliado(*afiliado, prestacion)
+}
+
+// EvaluarAfiliado junta todos los motivos en lugar de cortar en el primero,
+// así el frontend puede mostrarlos de una vez
+func (s *elegibilidadServiceImpl) EvaluarAfiliado(afiliado model.Afiliado, prestacion string) (*model.Elegibilidad, error) {
+	hoy := time.Now().UTC()
+	prestacion = strings.TrimSpace(prestacion)
+
+	resultado := &model.Elegibilidad{
+		AfiliadoID:    afiliado.ID,
+		Prestacion:    prestacion,
+		PlanMedicoID:  afiliado.PlanMedicoID,
+		Motivos:       []model.MotivoInelegibilidad{},
+		FechaConsulta: hoy,
+	}
+
+	agregar := func(codigo model.CodigoInelegibilidad, detalle string) {
+		resultado.Motivos = append(resultado.Motivos, model.MotivoInelegibilidad{Codigo: codigo, Detalle: detalle})
+	}
+
+	if !afiliado.EstaActivo(hoy) {
+		if afiliado.FechaBaja != nil {
+			agregar(model.InelegibleAfiliadoInactivo, fmt.Sprintf("El afiliado fue dado de baja el %s", afiliado.FechaBaja.Format(time.DateOnly)))
+		} else {
+			agregar(model.InelegibleAfiliadoInactivo, fmt.Sprintf("El afiliado recién se da de alta el %s", afiliado.FechaAlta.Format(time.DateOnly)))
+		}
+	}
+
+	plan, err := s.planes.GetByID(afiliado.PlanMedicoID)
+	switch {
+	case errors.Is(err, repository.ErrPlanNoEncontrado):
+		agregar(model.InelegiblePlanDesconocido, fmt.Sprintf("El plan %d no figura en el catálogo", afiliado.PlanMedicoID))
+	case err != nil:
+		s.logger.Error("Error al obtener el plan", zap.Int("planId", afiliado.PlanMedicoID), zap.Error(err))
+		return nil, err
+	default:
+		resultado.PlanMedico = plan.Nombre
+
+		if !plan.VigenteEn(hoy) {
+			agregar(model.InelegiblePlanNoVigente, fmt.Sprintf("El plan '%s' no está vigente", plan.Nombre))
+		}
+
+		cobertura, err := s.planes.GetCobertura(plan.ID, prestacion)
+		switch {
+		case errors.Is(err, repository.ErrPrestacionNoCubierta):
+			agregar(model.InelegiblePrestacionNoCubierta, fmt.Sprintf("El plan '%s' no cubre %s", plan.Nombre, prestacion))
+		case err != nil:
+			s.logger.Error("Error al obtener la cobertura de la prestación", zap.Int("planId", plan.ID), zap.Error(err))
+			return nil, err
+		default:
+			resultado.Cobertura = cobertura
+
+			finCarencia := afiliado.FechaAlta.AddDate(0, 0, cobertura.CarenciaDias)
+			if hoy.Before(finCarencia) {
+				resultado.CarenciaHasta = &finCarencia
+				agregar(model.InelegibleCarencia, fmt.Sprintf("%s tiene carencia de %d días: se cubre desde el %s",
+					cobertura.Prestacion, cobertura.CarenciaDias, finCarencia.Format(time.DateOnly)))
+			}
+		}
+	}
+
+	resultado.Elegible = len(resultado.Motivos) == 0
+
+	s.logger.Info("Elegibilidad evaluada",
+		zap.Int("afiliadoId", afiliado.ID),
+		zap.String("prestacion", prestacion),
+		zap.Bool("elegible", resultado.Elegible),
+		zap.Int("motivos", len(resultado.Motivos)),
+	)
+
+	return resultado, nil
+}
+
+// verificarElegible devuelve AfiliadoNoElegibleError si el afiliado no puede recibir la prestación;
+// si puede, devuelve la evaluación con la cobertura que aplica
+func verificarElegible(elegibilidad ElegibilidadService, afiliado model.Afiliado, prestacion string) (*model.Elegibilidad, error) {
+	resultado, err := elegibilidad.EvaluarAfiliado(afiliado, prestacion)
+	if err != nil {
+		return nil, err
+	}
+	if !resultado.Elegible {
+		return nil, &AfiliadoNoElegibleError{Elegibilidad: *resultado}
+	}
+	return resultado, nil
+}
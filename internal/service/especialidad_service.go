@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// EspecialidadInvalidaError indica que una solicitud o un turno referencia una especialidad
+// que no está en el catálogo o que no corresponde a la prestación
+type EspecialidadInvalidaError struct {
+	Message string
+}
+
+func (e *EspecialidadInvalidaError) Error() string {
+	return e.Message
+}
+
+// resolverEspecialidad busca el código en el catálogo, devolviendo EspecialidadInvalidaError si no está
+func resolverEspecialidad(repo repository.EspecialidadRepository, codigo string) (*model.Especialidad, error) {
+	especialidad, err := repo.GetByCodigo(codigo)
+	if errors.Is(err, repository.ErrEspecialidadNoEncontrada) {
+		return nil, &EspecialidadInvalidaError{Message: fmt.Sprintf("La especialidad %q no existe en el catálogo", codigo)}
+	}
+	return especialidad, err
+}
+
+// verificarEspecialidadPrestacion controla la especialidad opcional que manda el cliente: si viene,
+// tiene que existir y ser la que el nomenclador asigna a la prestación
+func verificarEspecialidadPrestacion(repo repository.EspecialidadRepository, codigo string, prestacion *model.PrestacionNomenclador) error {
+	if codigo == "" {
+		return nil
+	}
+	especialidad, err := resolverEspecialidad(repo, codigo)
+	if err != nil {
+		return err
+	}
+	if especialidad.Codigo != prestacion.EspecialidadCodigo {
+		return &EspecialidadInvalidaError{Message: fmt.Sprintf(
+			"La especialidad %s no corresponde al código %s (%s)", especialidad.Codigo, prestacion.Codigo, prestacion.EspecialidadCodigo)}
+	}
+	return nil
+}
+
+// validarFiltroEspecialidad devuelve el código normalizado para filtrar listados; un código
+// desconocido es un error del request (400), no una lista vacía
+func validarFiltroEspecialidad(repo repository.EspecialidadRepository, codigo string) (string, error) {
+	if codigo == "" {
+		return "", nil
+	}
+	especialidad, err := repo.GetByCodigo(codigo)
+	if errors.Is(err, repository.ErrEspecialidadNoEncontrada) {
+		return "", &ServiceError{Message: fmt.Sprintf("Especialidad desconocida: %s", codigo)}
+	}
+	if err != nil {
+		return "", err
+	}
+	return especialidad.Codigo, nil
+}
+
+type EspecialidadService interface {
+	GetEspecialidades() ([]model.Especialidad, error)
+}
+
+type especialidadServiceImpl struct {
+	repo   repository.EspecialidadRepository
+	logger *zap.Logger
+}
+
+func NewEspecialidadService(repo repository.EspecialidadRepository, logger *zap.Logger) EspecialidadService {
+	return &especialidadServiceImpl{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *especialidadServiceImpl) GetEspecialidades() ([]model.Especialidad, error) {
+	s.logger.Info("Obteniendo especialidades")
+
+	especialidades, err := s.repo.GetAll()
+	if err != nil {
+		s.logger.Error("Error al obtener especialidades", zap.Error(err))
+		return nil, err
+	}
+	return especialidades, nil
+}
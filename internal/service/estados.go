@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"prestadores-api/internal/model"
+	"slices"
+	"strings"
+)
+
+// Máquina de estados de las solicitudes: para cada estado, a qué estados se puede pasar
+// y qué roles pueden hacer ese cambio (ADMIN puede hacer todos).
+// Los estados sin salidas (APROBADO, RECHAZADO) son finales.
+// Los reintegros usan EstadoAutorizacion y comparten el mismo flujo.
+var transicionesAutorizacion = map[model.EstadoAutorizacion]map[model.EstadoAutorizacion][]model.Rol{
+	model.EstadoRecibido: {
+		model.EstadoEnAnalisis: {model.RolAuditor},
+	},
+	model.EstadoEnAnalisis: {
+		model.EstadoAprobado:  {model.RolAuditor},
+		model.EstadoRechazado: {model.RolAuditor},
+		model.EstadoObservado: {model.RolAuditor},
+	},
+	// El prestador reenvía la solicitud observada una vez corregida
+	model.EstadoObservado: {
+		model.EstadoEnAnalisis: {model.RolPrestador},
+	},
+	model.EstadoAprobado:  {},
+	model.EstadoRechazado: {},
+}
+
+// Las recetas aprobadas siguen su ciclo de vida fuera de la auditoría: las pasa a DISPENSADA o
+// PARCIALMENTE_DISPENSADA la farmacia y a VENCIDA el job de vencimiento. Por eso esas transiciones
+// no tienen roles y a mano solo las puede hacer ADMIN.
+var transicionesReceta = map[model.EstadoReceta]map[model.EstadoReceta][]model.Rol{
+	model.RecetaEstadoRecibido: {
+		model.RecetaEstadoEnAnalisis: {model.RolAuditor},
+	},
+	model.RecetaEstadoEnAnalisis: {
+		model.RecetaEstadoAprobado:  {model.RolAuditor},
+		model.RecetaEstadoRechazado: {model.RolAuditor},
+		model.RecetaEstadoObservado: {model.RolAuditor},
+	},
+	model.RecetaEstadoObservado: {
+		model.RecetaEstadoEnAnalisis: {model.RolPrestador},
+	},
+	model.RecetaEstadoAprobado: {
+		model.RecetaEstadoDispensada:             {},
+		model.RecetaEstadoParcialmenteDispensada: {},
+		model.RecetaEstadoVencida:                {},
+	},
+	model.RecetaEstadoParcialmenteDispensada: {
+		model.RecetaEstadoDispensada: {},
+		model.RecetaEstadoVencida:    {},
+	},
+	model.RecetaEstadoRechazado:  {},
+	model.RecetaEstadoDispensada: {},
+	model.RecetaEstadoVencida:    {},
+}
+
+// TransicionInvalidaError indica un cambio de estado no permitido desde el estado actual
+type TransicionInvalidaError struct {
+	EstadoActual string
+	EstadoNuevo  string
+	Permitidas   []string
+}
+
+func (e *TransicionInvalidaError) Error() string {
+	if len(e.Permitidas) == 0 {
+		return fmt.Sprintf("No se puede cambiar el estado %s: es un estado final", e.EstadoActual)
+	}
+	return fmt.Sprintf("No se puede pasar de %s a %s. Transiciones permitidas: %s",
+		e.EstadoActual, e.EstadoNuevo, strings.Join(e.Permitidas, ", "))
+}
+
+// validarEstadoInicial exige que las solicitudes nuevas arranquen en el estado inicial del flujo
+func validarEstadoInicial[E ~string](estado E, inicial E) error {
+	if estado != "" && estado != inicial {
+		return &ServiceError{Message: fmt.Sprintf("El estado inicial debe ser %s", inicial)}
+	}
+	return nil
+}
+
+// PermisoDenegadoError indica que el rol del usuario no puede hacer el cambio de estado
+type PermisoDenegadoError struct {
+	Rol          model.Rol
+	EstadoActual string
+	EstadoNuevo  string
+}
+
+func (e *PermisoDenegadoError) Error() string {
+	return fmt.Sprintf("El rol %s no puede pasar una solicitud de %s a %s", e.Rol, e.EstadoActual, e.EstadoNuevo)
+}
+
+// validarTransicion verifica que `nuevo` sea un estado conocido, alcanzable desde `actual`
+// y que el rol pueda hacer ese cambio
+func validarTransicion[E ~string](tabla map[E]map[E][]model.Rol, actual E, nuevo E, rol model.Rol) error {
+	if _, ok := tabla[nuevo]; !ok {
+		return &ServiceError{Message: fmt.Sprintf("Estado inválido: %s", nuevo)}
+	}
+
+	roles, ok := tabla[actual][nuevo]
+	if !ok {
+		return transicionInvalida(tabla, actual, nuevo)
+	}
+
+	if rol != model.RolAdmin && !slices.Contains(roles, rol) {
+		return &PermisoDenegadoError{Rol: rol, EstadoActual: string(actual), EstadoNuevo: string(nuevo)}
+	}
+	return nil
+}
+
+// transicionInvalida arma el error con las transiciones que admite el estado actual. También se usa
+// cuando el repositorio detecta que otro usuario cambió el estado después de validar la transición.
+func transicionInvalida[E ~string](tabla map[E]map[E][]model.Rol, actual E, nuevo E) *TransicionInvalidaError {
+	err := &TransicionInvalidaError{
+		EstadoActual: string(actual),
+		EstadoNuevo:  string(nuevo),
+		Permitidas:   make([]string, 0, len(tabla[actual])),
+	}
+	for e := range tabla[actual] {
+		err.Permitidas = append(err.Permitidas, string(e))
+	}
+	slices.Sort(err.Permitidas)
+	return err
+}
+
+// SolicitudNoEditableError indica que la solicitud ya está en auditoría o cerrada y no admite cambios
+type SolicitudNoEditableError struct {
+	Estado    string
+	Editables []string
+}
+
+func (e *SolicitudNoEditableError) Error() string {
+	return fmt.Sprintf("La solicitud está en estado %s y ya no se puede modificar. Solo se editan en: %s",
+		e.Estado, strings.Join(e.Editables, ", "))
+}
+
+// validarEditable exige que la solicitud esté en uno de los estados donde el prestador todavía puede
+// corregirla: antes de que el auditor la tome o mientras está observada
+func validarEditable[E ~string](actual E, editables ...E) error {
+	if slices.Contains(editables, actual) {
+		return nil
+	}
+	return solicitudNoEditable(actual, editables...)
+}
+
+// solicitudNoEditable arma el error con los estados editables. También se usa cuando el repositorio
+// detecta que la solicitud cambió de estado después de validar que era editable.
+func solicitudNoEditable[E ~string](actual E, editables ...E) *SolicitudNoEditableError {
+	err := &SolicitudNoEditableError{Estado: string(actual), Editables: make([]string, 0, len(editables))}
+	for _, e := range editables {
+		err.Editables = append(err.Editables, string(e))
+	}
+	return err
+}
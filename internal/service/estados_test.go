@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// Resultados posibles de validarTransicion
+const (
+	permitida   = "permitida"
+	invalida    = "transición inválida"
+	denegada    = "permiso denegado"
+	desconocido = "estado desconocido"
+)
+
+type casoTransicion[E ~string] struct {
+	actual E
+	nuevo  E
+	rol    model.Rol
+	want   string
+}
+
+func resultadoTransicion(err error) string {
+	var (
+		transicionErr *TransicionInvalidaError
+		permisoErr    *PermisoDenegadoError
+		svcErr        *ServiceError
+	)
+	switch {
+	case err == nil:
+		return permitida
+	case errors.As(err, &transicionErr):
+		return invalida
+	case errors.As(err, &permisoErr):
+		return denegada
+	case errors.As(err, &svcErr):
+		return desconocido
+	default:
+		return err.Error()
+	}
+}
+
+func probarTransiciones[E ~string](t *testing.T, tabla map[E]map[E][]model.Rol, casos []casoTransicion[E]) {
+	t.Helper()
+	for _, tc := range casos {
+		t.Run(string(tc.actual)+"->"+string(tc.nuevo)+"/"+string(tc.rol), func(t *testing.T) {
+			got := resultadoTransicion(validarTransicion(tabla, tc.actual, tc.nuevo, tc.rol))
+			if got != tc.want {
+				t.Errorf("validarTransicion(%s, %s, %s) = %s, se esperaba %s", tc.actual, tc.nuevo, tc.rol, got, tc.want)
+			}
+		})
+	}
+}
+
+// Los reintegros usan la misma tabla que las autorizaciones
+func TestTransicionesAutorizacionYReintegro(t *testing.T) {
+	probarTransiciones(t, transicionesAutorizacion, []casoTransicion[model.EstadoAutorizacion]{
+		{model.EstadoRecibido, model.EstadoEnAnalisis, model.RolAuditor, permitida},
+		{model.EstadoRecibido, model.EstadoEnAnalisis, model.RolAdmin, permitida},
+		{model.EstadoRecibido, model.EstadoEnAnalisis, model.RolPrestador, denegada},
+		{model.EstadoRecibido, model.EstadoEnAnalisis, model.RolFarmacia, denegada},
+		{model.EstadoRecibido, model.EstadoAprobado, model.RolAuditor, invalida},
+		{model.EstadoRecibido, model.EstadoAprobado, model.RolAdmin, invalida},
+		{model.EstadoEnAnalisis, model.EstadoAprobado, model.RolAuditor, permitida},
+		{model.EstadoEnAnalisis, model.EstadoRechazado, model.RolAuditor, permitida},
+		{model.EstadoEnAnalisis, model.EstadoObservado, model.RolAuditor, permitida},
+		{model.EstadoEnAnalisis, model.EstadoAprobado, model.RolPrestador, denegada},
+		{model.EstadoEnAnalisis, model.EstadoEnAnalisis, model.RolAuditor, invalida},
+		{model.EstadoEnAnalisis, model.EstadoRecibido, model.RolAdmin, invalida},
+		{model.EstadoObservado, model.EstadoEnAnalisis, model.RolPrestador, permitida},
+		{model.EstadoObservado, model.EstadoEnAnalisis, model.RolAuditor, denegada},
+		{model.EstadoObservado, model.EstadoAprobado, model.RolAuditor, invalida},
+		// Estados finales: ni ADMIN los reabre
+		{model.EstadoAprobado, model.EstadoEnAnalisis, model.RolAuditor, invalida},
+		{model.EstadoAprobado, model.EstadoRechazado, model.RolAdmin, invalida},
+		{model.EstadoRechazado, model.EstadoRecibido, model.RolAdmin, invalida},
+		{model.EstadoRechazado, model.EstadoObservado, model.RolPrestador, invalida},
+		{model.EstadoRecibido, "CERRADO", model.RolAdmin, desconocido},
+		{model.EstadoEnAnalisis, "", model.RolAuditor, desconocido},
+	})
+}
+
+func TestTransicionesReceta(t *testing.T) {
+	probarTransiciones(t, transicionesReceta, []casoTransicion[model.EstadoReceta]{
+		{model.RecetaEstadoRecibido, model.RecetaEstadoEnAnalisis, model.RolAuditor, permitida},
+		{model.RecetaEstadoRecibido, model.RecetaEstadoEnAnalisis, model.RolPrestador, denegada},
+		{model.RecetaEstadoRecibido, model.RecetaEstadoAprobado, model.RolAuditor, invalida},
+		{model.RecetaEstadoEnAnalisis, model.RecetaEstadoAprobado, model.RolAuditor, permitida},
+		{model.RecetaEstadoEnAnalisis, model.RecetaEstadoRechazado, model.RolAuditor, permitida},
+		{model.RecetaEstadoEnAnalisis, model.RecetaEstadoObservado, model.RolAuditor, permitida},
+		{model.RecetaEstadoEnAnalisis, model.RecetaEstadoDispensada, model.RolAdmin, invalida},
+		{model.RecetaEstadoObservado, model.RecetaEstadoEnAnalisis, model.RolPrestador, permitida},
+		{model.RecetaEstadoObservado, model.RecetaEstadoEnAnalisis, model.RolFarmacia, denegada},
+		// Fuera de la auditoría las mueven la farmacia y el job de vencimiento: a mano, solo ADMIN
+		{model.RecetaEstadoAprobado, model.RecetaEstadoDispensada, model.RolAdmin, permitida},
+		{model.RecetaEstadoAprobado, model.RecetaEstadoDispensada, model.RolFarmacia, denegada},
+		{model.RecetaEstadoAprobado, model.RecetaEstadoParcialmenteDispensada, model.RolAuditor, denegada},
+		{model.RecetaEstadoAprobado, model.RecetaEstadoVencida, model.RolAdmin, permitida},
+		{model.RecetaEstadoAprobado, model.RecetaEstadoEnAnalisis, model.RolAdmin, invalida},
+		{model.RecetaEstadoParcialmenteDispensada, model.RecetaEstadoDispensada, model.RolAdmin, permitida},
+		{model.RecetaEstadoParcialmenteDispensada, model.RecetaEstadoVencida, model.RolAuditor, denegada},
+		{model.RecetaEstadoParcialmenteDispensada, model.RecetaEstadoAprobado, model.RolAdmin, invalida},
+		{model.RecetaEstadoRechazado, model.RecetaEstadoEnAnalisis, model.RolAdmin, invalida},
+		{model.RecetaEstadoDispensada, model.RecetaEstadoVencida, model.RolAdmin, invalida},
+		{model.RecetaEstadoVencida, model.RecetaEstadoAprobado, model.RolAdmin, invalida},
+		{model.RecetaEstadoAprobado, "ANULADA", model.RolAdmin, desconocido},
+	})
+}
+
+func TestTransicionInvalidaError(t *testing.T) {
+	err := transicionInvalida(transicionesAutorizacion, model.EstadoEnAnalisis, model.EstadoRecibido)
+	if want := []string{"APROBADO", "OBSERVADO", "RECHAZADO"}; !slices.Equal(err.Permitidas, want) {
+		t.Errorf("Permitidas = %v, se esperaba %v", err.Permitidas, want)
+	}
+
+	final := transicionInvalida(transicionesReceta, model.RecetaEstadoVencida, model.RecetaEstadoAprobado)
+	if len(final.Permitidas) != 0 {
+		t.Errorf("un estado final no debería tener transiciones permitidas: %v", final.Permitidas)
+	}
+	if want := "No se puede cambiar el estado VENCIDA: es un estado final"; final.Error() != want {
+		t.Errorf("Error() = %q, se esperaba %q", final.Error(), want)
+	}
+}
+
+func TestValidarEditable(t *testing.T) {
+	editables := []model.EstadoAutorizacion{model.EstadoRecibido, model.EstadoObservado}
+	casos := []struct {
+		estado model.EstadoAutorizacion
+		ok     bool
+	}{
+		{model.EstadoRecibido, true},
+		{model.EstadoObservado, true},
+		{model.EstadoEnAnalisis, false},
+		{model.EstadoAprobado, false},
+		{model.EstadoRechazado, false},
+	}
+
+	for _, tc := range casos {
+		t.Run(string(tc.estado), func(t *testing.T) {
+			err := validarEditable(tc.estado, editables...)
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("validarEditable(%s) = %v, se esperaba nil", tc.estado, err)
+				}
+				return
+			}
+			var noEditable *SolicitudNoEditableError
+			if !errors.As(err, &noEditable) {
+				t.Fatalf("validarEditable(%s) = %v, se esperaba SolicitudNoEditableError", tc.estado, err)
+			}
+			if noEditable.Estado != string(tc.estado) || !slices.Equal(noEditable.Editables, []string{"RECIBIDO", "OBSERVADO"}) {
+				t.Errorf("error = %+v", noEditable)
+			}
+		})
+	}
+}
+
+// Los stubs cambian el estado justo antes del CambiarEstado del service, como lo haría otro
+// usuario entre la lectura y la escritura
+
+type autorizacionConCambioPrevio struct {
+	repository.AutorizacionRepository
+	antes model.CambioEstadoRequest
+}
+
+func (r *autorizacionConCambioPrevio) CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.AutorizacionDetalle, error) {
+	if _, err := r.AutorizacionRepository.CambiarEstado(id, estadoActual, r.antes); err != nil {
+		return nil, err
+	}
+	return r.AutorizacionRepository.CambiarEstado(id, estadoActual, req)
+}
+
+type reintegroConCambioPrevio struct {
+	repository.ReintegroRepository
+	antes model.CambioEstadoRequest
+}
+
+func (r *reintegroConCambioPrevio) CambiarEstado(id int, estadoActual model.EstadoAutorizacion, req model.CambioEstadoRequest) (*model.ReintegroDetalle, error) {
+	if _, err := r.ReintegroRepository.CambiarEstado(id, estadoActual, r.antes); err != nil {
+		return nil, err
+	}
+	return r.ReintegroRepository.CambiarEstado(id, estadoActual, req)
+}
+
+type recetaConCambioPrevio struct {
+	repository.RecetaRepository
+	antes model.CambioEstadoRecetaRequest
+}
+
+func (r *recetaConCambioPrevio) CambiarEstado(id int, estadoActual model.EstadoReceta, req model.CambioEstadoRecetaRequest) (*model.RecetaDetalle, error) {
+	if _, err := r.RecetaRepository.CambiarEstado(id, estadoActual, r.antes); err != nil {
+		return nil, err
+	}
+	return r.RecetaRepository.CambiarEstado(id, estadoActual, req)
+}
+
+func TestCambioEstadoConcurrente(t *testing.T) {
+	auditor := model.UsuarioAutenticado{ID: 301, Username: "auditor.301", Rol: model.RolAuditor}
+	otroAuditor := "auditor.302"
+
+	// esperarTransicionInvalida verifica que el service responda con el estado que dejó el otro usuario
+	esperarTransicionInvalida := func(t *testing.T, err error, estado string, permitidas []string) {
+		t.Helper()
+		var transicionErr *TransicionInvalidaError
+		if !errors.As(err, &transicionErr) {
+			t.Fatalf("err = %v, se esperaba TransicionInvalidaError", err)
+		}
+		if transicionErr.EstadoActual != estado || !slices.Equal(transicionErr.Permitidas, permitidas) {
+			t.Errorf("error = %+v, se esperaba estado %s con permitidas %v", transicionErr, estado, permitidas)
+		}
+	}
+
+	t.Run("autorización", func(t *testing.T) {
+		repo := &autorizacionConCambioPrevio{
+			AutorizacionRepository: repository.NewAutorizacionRepository(),
+			antes:                  model.CambioEstadoRequest{NuevoEstado: model.EstadoRechazado, Motivo: "Duplicada", Usuario: otroAuditor, Rol: model.RolAuditor},
+		}
+		svc := NewAutorizacionService(repo, nil, nil, nil, nil, nil, zap.NewNop())
+
+		// 12003 está EN_ANALISIS
+		_, err := svc.CambiarEstadoAutorizacion(12003, model.CambioEstadoRequest{NuevoEstado: model.EstadoAprobado}, auditor)
+		esperarTransicionInvalida(t, err, "RECHAZADO", []string{})
+	})
+
+	t.Run("reintegro", func(t *testing.T) {
+		repo := &reintegroConCambioPrevio{
+			ReintegroRepository: repository.NewReintegroRepository(),
+			antes:               model.CambioEstadoRequest{NuevoEstado: model.EstadoEnAnalisis, Usuario: otroAuditor, Rol: model.RolAuditor},
+		}
+		svc := NewReintegroService(repo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+		// 8803 está RECIBIDO
+		_, err := svc.CambiarEstadoReintegro(8803, model.CambioEstadoRequest{NuevoEstado: model.EstadoEnAnalisis}, auditor)
+		esperarTransicionInvalida(t, err, "EN_ANALISIS", []string{"APROBADO", "OBSERVADO", "RECHAZADO"})
+	})
+
+	t.Run("receta", func(t *testing.T) {
+		repo := &recetaConCambioPrevio{
+			RecetaRepository: repository.NewRecetaRepository(),
+			antes:            model.CambioEstadoRecetaRequest{NuevoEstado: model.RecetaEstadoObservado, Motivo: "Falta diagnóstico", Usuario: otroAuditor, Rol: model.RolAuditor},
+		}
+		svc := NewRecetaService(repo, nil, nil, nil, nil, VigenciaRecetas{}, zap.NewNop())
+
+		// 9352 está EN_ANALISIS
+		_, err := svc.CambiarEstadoReceta(9352, model.CambioEstadoRecetaRequest{NuevoEstado: model.RecetaEstadoRechazado, Motivo: "No corresponde"}, auditor)
+		esperarTransicionInvalida(t, err, "OBSERVADO", []string{"EN_ANALISIS"})
+	})
+}
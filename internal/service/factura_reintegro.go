@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"strings"
+	"time"
+)
+
+// FacturaDuplicadaError indica que la factura ya respalda otro reintegro que no fue rechazado
+type FacturaDuplicadaError struct {
+	Comprobante string
+	ReintegroID int
+}
+
+func (e *FacturaDuplicadaError) Error() string {
+	return fmt.Sprintf("La factura %s ya fue presentada en el reintegro %d", e.Comprobante, e.ReintegroID)
+}
+
+// normalizarFactura valida los datos de la factura y la devuelve con el tipo en mayúsculas y el
+// CUIT y el CAE sin separadores. El monto del reintegro no puede superar el importe facturado.
+func normalizarFactura(f model.Factura, monto model.Monto, hoy time.Time) (*model.Factura, error) {
+	f.Tipo = model.TipoFactura(strings.ToUpper(strings.TrimSpace(string(f.Tipo))))
+	if !f.Tipo.Valida() {
+		return nil, &ServiceError{Message: fmt.Sprintf("Tipo de factura inválido: %q. Valores posibles: %s, %s, %s",
+			f.Tipo, model.FacturaA, model.FacturaB, model.FacturaC)}
+	}
+
+	if f.PuntoVenta < 1 || f.PuntoVenta > 99999 {
+		return nil, &ServiceError{Message: fmt.Sprintf("Punto de venta inválido: %d. Debe estar entre 1 y 99999", f.PuntoVenta)}
+	}
+	if f.Numero < 1 || f.Numero > 99999999 {
+		return nil, &ServiceError{Message: fmt.Sprintf("Número de factura inválido: %d. Debe estar entre 1 y 99999999", f.Numero)}
+	}
+
+	cuit, err := validacion.NormalizarCUIT(f.CUITEmisor)
+	if err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("CUIT del emisor de la factura inválido: %v", err)}
+	}
+	f.CUITEmisor = cuit
+
+	fecha, err := time.Parse("2006-01-02", strings.TrimSpace(f.Fecha))
+	if err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("Fecha de factura inválida: %q. Se espera yyyy-mm-dd", f.Fecha)}
+	}
+	if fecha.After(hoy) {
+		return nil, &ServiceError{Message: fmt.Sprintf("La fecha de la factura (%s) no puede ser posterior a hoy", fecha.Format("2006-01-02"))}
+	}
+	f.Fecha = fecha.Format("2006-01-02")
+
+	if f.CAE, err = validacion.NormalizarCAE(f.CAE); err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("CAE de la factura inválido: %v", err)}
+	}
+
+	if f.Importe <= 0 {
+		return nil, &ServiceError{Message: "El importe de la factura debe ser mayor a 0"}
+	}
+	if err := validarMontoFacturado(monto, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// validarMontoFacturado impide presentar más de lo facturado. Sin factura (reintegros previos) no hay límite.
+func validarMontoFacturado(monto model.Monto, f *model.Factura) error {
+	if f != nil && monto > f.Importe {
+		return &ServiceError{Message: fmt.Sprintf("El monto %s supera el importe de la factura %s (%s)",
+			monto, f.Comprobante(), f.Importe)}
+	}
+	return nil
+}
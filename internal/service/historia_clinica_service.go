@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// ErrTurnoNoEncontrado indica que el turno no existe o es de otro afiliado
+var ErrTurnoNoEncontrado = repository.ErrTurnoNoEncontrado
+
+type HistoriaClinicaService interface {
+	// prestadorID > 0 deja solo las notas de ese prestador; especialidad es un código del catálogo
+	GetHistoriaClinica(afiliadoID int, prestadorID int, especialidad string, page int, size int) (*model.HistoriaClinica, error)
+	CreateTurno(req model.CreateTurnoRequest) (*model.Turno, error)
+	UpdateTurno(afiliadoID int, turnoID int, req model.UpdateTurnoRequest) (*model.Turno, error)
+}
+
+type historiaClinicaServiceImpl struct {
+	turnos         repository.TurnoRepository
+	afiliados      repository.AfiliadoRepository
+	especialidades repository.EspecialidadRepository
+	logger         *zap.Logger
+}
+
+func NewHistoriaClinicaService(turnos repository.TurnoRepository, afiliados repository.AfiliadoRepository, especialidades repository.EspecialidadRepository, logger *zap.Logger) HistoriaClinicaService {
+	return &historiaClinicaServiceImpl{
+		turnos:         turnos,
+		afiliados:      afiliados,
+		especialidades: especialidades,
+		logger:         logger,
+	}
+}
+
+func (s *historiaClinicaServiceImpl) GetHistoriaClinica(afiliadoID int, prestadorID int, especialidad string, page int, size int) (*model.HistoriaClinica, error) {
+	s.logger.Info("Obteniendo historia clínica",
+		zap.Int("afiliadoId", afiliadoID),
+		zap.Int("prestadorId", prestadorID),
+		zap.String("especialidad", especialidad),
+		zap.Int("page", page),
+		zap.Int("size", size),
+	)
+
+	if _, err := s.afiliados.GetByID(afiliadoID); err != nil {
+		s.logger.Warn("Afiliado no encontrado", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	especialidadCodigo, err := validarFiltroEspecialidad(s.especialidades, especialidad)
+	if err != nil {
+		s.logger.Warn("Filtro de especialidad inválido", zap.String("especialidad", especialidad), zap.Error(err))
+		return nil, err
+	}
+
+	turnos, err := s.turnos.GetByAfiliado(afiliadoID, especialidadCodigo)
+	if err != nil {
+		s.logger.Error("Error al obtener turnos", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	if prestadorID > 0 {
+		for i := range turnos {
+			filtradas := make([]model.NotaTurno, 0, len(turnos[i].Notas))
+			for _, n := range turnos[i].Notas {
+				if n.PrestadorID == prestadorID {
+					filtradas = append(filtradas, n)
+				}
+			}
+			turnos[i].Notas = filtradas
+		}
+	}
+
+	total := len(turnos)
+	start := min(page*size, total)
+	end := min(start+size, total)
+
+	return &model.HistoriaClinica{
+		AfiliadoID: afiliadoID,
+		Page:       page,
+		Size:       size,
+		Total:      total,
+		Turnos:     turnos[start:end],
+	}, nil
+}
+
+func (s *historiaClinicaServiceImpl) CreateTurno(req model.CreateTurnoRequest) (*model.Turno, error) {
+	s.logger.Info("Creando turno",
+		zap.Int("afiliadoId", req.AfiliadoID),
+		zap.Time("fecha", req.Fecha),
+		zap.String("especialidadCodigo", req.EspecialidadCodigo),
+	)
+
+	if _, err := s.afiliados.GetByID(req.AfiliadoID); err != nil {
+		s.logger.Warn("Afiliado no encontrado", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	especialidad, err := resolverEspecialidad(s.especialidades, req.EspecialidadCodigo)
+	if err != nil {
+		s.logger.Warn("Especialidad inválida", zap.String("especialidadCodigo", req.EspecialidadCodigo), zap.Error(err))
+		return nil, err
+	}
+	req.EspecialidadCodigo = especialidad.Codigo
+	req.Especialidad = especialidad.Nombre
+
+	turno, err := s.turnos.Create(req)
+	if err != nil {
+		s.logger.Error("Error al crear turno", zap.Error(err))
+		return nil, err
+	}
+	return turno, nil
+}
+
+func (s *historiaClinicaServiceImpl) UpdateTurno(afiliadoID int, turnoID int, req model.UpdateTurnoRequest) (*model.Turno, error) {
+	s.logger.Info("Actualizando turno",
+		zap.Int("afiliadoId", afiliadoID),
+		zap.Int("turnoId", turnoID),
+		zap.String("especialidadCodigo", req.EspecialidadCodigo),
+		zap.String("estado", string(req.Estado)),
+	)
+
+	switch req.Estado {
+	case "", model.EstadoTurnoReservado, model.EstadoTurnoAtendido, model.EstadoTurnoCancelado:
+	default:
+		s.logger.Warn("Estado de turno inválido", zap.String("estado", string(req.Estado)))
+		return nil, &ServiceError{Message: fmt.Sprintf("Estado de turno inválido: %s", req.Estado)}
+	}
+
+	if req.EspecialidadCodigo != "" {
+		especialidad, err := resolverEspecialidad(s.especialidades, req.EspecialidadCodigo)
+		if err != nil {
+			s.logger.Warn("Especialidad inválida", zap.String("especialidadCodigo", req.EspecialidadCodigo), zap.Error(err))
+			return nil, err
+		}
+		req.EspecialidadCodigo = especialidad.Codigo
+		req.Especialidad = especialidad.Nombre
+	}
+
+	turno, err := s.turnos.Update(afiliadoID, turnoID, req)
+	if err != nil {
+		s.logger.Warn("Error al actualizar turno", zap.Int("turnoId", turnoID), zap.Error(err))
+		return nil, err
+	}
+	return turno, nil
+}
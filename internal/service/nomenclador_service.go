@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// ErrCodigoNoEncontrado indica que el código no figura en el nomenclador
+var ErrCodigoNoEncontrado = repository.ErrCodigoNomenclador
+
+// Límite de resultados del typeahead del nomenclador
+const (
+	limiteNomencladorDefault = 20
+	limiteNomencladorMaximo  = 100
+)
+
+// CodigoNomencladorError indica que una solicitud referencia un código que no está en el nomenclador
+type CodigoNomencladorError struct {
+	Codigo string
+}
+
+func (e *CodigoNomencladorError) Error() string {
+	return fmt.Sprintf("El código %q no existe en el nomenclador", e.Codigo)
+}
+
+// resolverPrestacion busca el código en el nomenclador, devolviendo CodigoNomencladorError si no está
+func resolverPrestacion(repo repository.NomencladorRepository, codigo string) (*model.PrestacionNomenclador, error) {
+	prestacion, err := repo.GetByCodigo(codigo)
+	if errors.Is(err, repository.ErrCodigoNomenclador) {
+		return nil, &CodigoNomencladorError{Codigo: codigo}
+	}
+	return prestacion, err
+}
+
+type NomencladorService interface {
+	BuscarPrestaciones(query string, especialidad string, limit int) (*model.NomencladorResponse, error)
+	GetPrestacion(codigo string) (*model.PrestacionNomenclador, error)
+}
+
+type nomencladorServiceImpl struct {
+	repo           repository.NomencladorRepository
+	especialidades repository.EspecialidadRepository
+	logger         *zap.Logger
+}
+
+func NewNomencladorService(repo repository.NomencladorRepository, especialidades repository.EspecialidadRepository, logger *zap.Logger) NomencladorService {
+	return &nomencladorServiceImpl{
+		repo:           repo,
+		especialidades: especialidades,
+		logger:         logger,
+	}
+}
+
+func (s *nomencladorServiceImpl) BuscarPrestaciones(query string, especialidad string, limit int) (*model.NomencladorResponse, error) {
+	if limit <= 0 {
+		limit = limiteNomencladorDefault
+	}
+	if limit > limiteNomencladorMaximo {
+		limit = limiteNomencladorMaximo
+	}
+
+	s.logger.Info("Buscando prestaciones en el nomenclador",
+		zap.String("query", query),
+		zap.String("especialidad", especialidad),
+		zap.Int("limit", limit),
+	)
+
+	especialidadCodigo, err := validarFiltroEspecialidad(s.especialidades, especialidad)
+	if err != nil {
+		s.logger.Warn("Filtro de especialidad inválido", zap.String("especialidad", especialidad), zap.Error(err))
+		return nil, err
+	}
+
+	items, total, err := s.repo.Buscar(query, especialidadCodigo, limit)
+	if err != nil {
+		s.logger.Error("Error al buscar en el nomenclador", zap.Error(err))
+		return nil, err
+	}
+
+	return &model.NomencladorResponse{Total: total, Items: items}, nil
+}
+
+func (s *nomencladorServiceImpl) GetPrestacion(codigo string) (*model.PrestacionNomenclador, error) {
+	s.logger.Info("Obteniendo prestación del nomenclador", zap.String("codigo", codigo))
+
+	prestacion, err := s.repo.GetByCodigo(codigo)
+	if err != nil {
+		s.logger.Warn("Prestación no encontrada en el nomenclador", zap.String("codigo", codigo), zap.Error(err))
+		return nil, err
+	}
+	return prestacion, nil
+}
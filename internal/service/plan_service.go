@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ErrPlanNoEncontrado indica que el plan no existe en el catálogo
+var ErrPlanNoEncontrado = repository.ErrPlanNoEncontrado
+
+var ErrPlanExistente = &ServiceError{Message: "Ya existe un plan con ese nombre"}
+
+// PlanEnUsoError indica que no se puede borrar un plan asignado a afiliados
+type PlanEnUsoError struct {
+	ID        int
+	Afiliados int
+}
+
+func (e *PlanEnUsoError) Error() string {
+	return fmt.Sprintf("El plan %d está asignado a %d afiliado(s) y no se puede eliminar", e.ID, e.Afiliados)
+}
+
+type PlanService interface {
+	GetPlanes() ([]model.PlanMedico, error)
+	GetPlanByID(id int) (*model.PlanMedico, error)
+	CreatePlan(req model.PlanMedicoRequest) (*model.PlanMedico, error)
+	UpdatePlan(id int, req model.PlanMedicoRequest) (*model.PlanMedico, error)
+	DeletePlan(id int) error
+}
+
+type planServiceImpl struct {
+	repo      repository.PlanRepository
+	afiliados repository.AfiliadoRepository
+	logger    *zap.Logger
+}
+
+func NewPlanService(repo repository.PlanRepository, afiliados repository.AfiliadoRepository, logger *zap.Logger) PlanService {
+	return &planServiceImpl{
+		repo:      repo,
+		afiliados: afiliados,
+		logger:    logger,
+	}
+}
+
+func (s *planServiceImpl) GetPlanes() ([]model.PlanMedico, error) {
+	s.logger.Info("Obteniendo planes")
+
+	planes, err := s.repo.GetAll()
+	if err != nil {
+		s.logger.Error("Error al obtener planes", zap.Error(err))
+		return nil, err
+	}
+	return planes, nil
+}
+
+func (s *planServiceImpl) GetPlanByID(id int) (*model.PlanMedico, error) {
+	s.logger.Info("Obteniendo plan por ID", zap.Int("id", id))
+
+	plan, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener plan", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *planServiceImpl) CreatePlan(req model.PlanMedicoRequest) (*model.PlanMedico, error) {
+	s.logger.Info("Creando plan", zap.String("nombre", req.Nombre), zap.Int("coberturas", len(req.Coberturas)))
+
+	if err := validarPlanRequest(req); err != nil {
+		s.logger.Warn("Plan inválido", zap.String("nombre", req.Nombre), zap.Error(err))
+		return nil, err
+	}
+
+	plan, err := s.repo.Create(req)
+	if err != nil {
+		s.logger.Error("Error al crear plan", zap.String("nombre", req.Nombre), zap.Error(err))
+		return nil, traducirErrorPlan(err)
+	}
+	return plan, nil
+}
+
+func (s *planServiceImpl) UpdatePlan(id int, req model.PlanMedicoRequest) (*model.PlanMedico, error) {
+	s.logger.Info("Actualizando plan", zap.Int("id", id), zap.String("nombre", req.Nombre))
+
+	if err := validarPlanRequest(req); err != nil {
+		s.logger.Warn("Plan inválido", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	plan, err := s.repo.Update(id, req)
+	if err != nil {
+		s.logger.Error("Error al actualizar plan", zap.Int("id", id), zap.Error(err))
+		return nil, traducirErrorPlan(err)
+	}
+	return plan, nil
+}
+
+func (s *planServiceImpl) DeletePlan(id int) error {
+	s.logger.Info("Eliminando plan", zap.Int("id", id))
+
+	if _, err := s.repo.GetByID(id); err != nil {
+		s.logger.Warn("Plan no encontrado", zap.Int("id", id), zap.Error(err))
+		return err
+	}
+
+	asignados, err := s.afiliados.ContarPorPlan(id)
+	if err != nil {
+		s.logger.Error("Error al contar afiliados del plan", zap.Int("id", id), zap.Error(err))
+		return err
+	}
+	if asignados > 0 {
+		s.logger.Warn("Plan asignado a afiliados", zap.Int("id", id), zap.Int("afiliados", asignados))
+		return &PlanEnUsoError{ID: id, Afiliados: asignados}
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		s.logger.Error("Error al eliminar plan", zap.Int("id", id), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func validarPlanRequest(req model.PlanMedicoRequest) error {
+	if strings.TrimSpace(req.Nombre) == "" {
+		return &ServiceError{Message: "El nombre del plan es obligatorio"}
+	}
+	if req.VigenciaHasta != nil && !req.VigenciaHasta.After(req.VigenciaDesde) {
+		return &ServiceError{Message: "vigenciaHasta debe ser posterior a vigenciaDesde"}
+	}
+	if len(req.Coberturas) == 0 {
+		return &ServiceError{Message: "El plan debe cubrir al menos una prestación"}
+	}
+
+	for _, c := range req.Coberturas {
+		switch {
+		case strings.TrimSpace(c.Prestacion) == "":
+			return &ServiceError{Message: "Cada cobertura debe indicar la prestación"}
+		case c.PorcentajeCobertura < 0 || c.PorcentajeCobertura > 100:
+			return &ServiceError{Message: fmt.Sprintf("%s: porcentajeCobertura debe estar entre 0 y 100", c.Prestacion)}
+		case c.TopeAnual < 0:
+			return &ServiceError{Message: fmt.Sprintf("%s: topeAnual no puede ser negativo", c.Prestacion)}
+		case c.Copago < 0:
+			return &ServiceError{Message: fmt.Sprintf("%s: copago no puede ser negativo", c.Prestacion)}
+		case c.CarenciaDias < 0:
+			return &ServiceError{Message: fmt.Sprintf("%s: carenciaDias no puede ser negativo", c.Prestacion)}
+		}
+	}
+
+	return nil
+}
+
+func traducirErrorPlan(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrPlanExistente):
+		return ErrPlanExistente
+	case errors.Is(err, repository.ErrCoberturaDuplicada):
+		return &ServiceError{Message: err.Error()}
+	}
+	return err
+}
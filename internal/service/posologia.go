@@ -0,0 +1,219 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/validacion"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Límites de la posología estructurada
+const (
+	frecuenciaHorasMaxima = 720 // una toma por mes
+	duracionDiasMaxima    = 365
+)
+
+// Expresiones para interpretar la dosis en texto libre, sobre el texto en minúsculas y sin tildes
+var (
+	reDosisCantidad  = regexp.MustCompile(`(\d+/\d+|\d+(?:[.,]\d+)?)\s*(comprimidos?|comp|cps|capsulas?|caps?|sobres?|ml|cc|gotas?|gts|disparos?|puffs?|inhalacion(?:es)?|aplicacion(?:es)?|aplic|ampollas?|amp)\b`)
+	reDosisCadaHoras = regexp.MustCompile(`(?:c/|cada)\s*(\d+)\s*(?:h|hs|hrs?|horas?)\b`)
+	reDosisCadaDias  = regexp.MustCompile(`(?:c/|cada)\s*(\d+)\s*(?:d|dias?)\b`)
+	reDosisVecesDia  = regexp.MustCompile(`(\d+|una|dos|tres|cuatro)\s*(?:vez|veces)\s*(?:al|por|x)\s*dia\b`)
+	reDosisDiaria    = regexp.MustCompile(`(?:\b(?:por dia|al dia|diari[oa]s?|c/24\s*h)|/\s*dia)\b`) // "/dia" cubre "comp/dia" y "c/dia"
+	reDosisDuracion  = regexp.MustCompile(`(?:\bx|\bpor|\bdurante)\s*(\d+)\s*(d|dias?|sem|semanas?|mes|meses)\b`)
+	reemplazosDosis  = strings.NewReplacer("½", "1/2", "¼", "1/4", "v.o.", "vo", "v.o", "vo")
+)
+
+var unidadesDosis = map[string]model.UnidadDosis{
+	"comprimido": model.UnidadComprimido, "comprimidos": model.UnidadComprimido, "comp": model.UnidadComprimido,
+	"capsula": model.UnidadCapsula, "capsulas": model.UnidadCapsula, "cap": model.UnidadCapsula, "caps": model.UnidadCapsula, "cps": model.UnidadCapsula,
+	"sobre": model.UnidadSobre, "sobres": model.UnidadSobre,
+	"ml": model.UnidadML, "cc": model.UnidadML,
+	"gota": model.UnidadGota, "gotas": model.UnidadGota, "gts": model.UnidadGota,
+	"disparo": model.UnidadDisparo, "disparos": model.UnidadDisparo, "puff": model.UnidadDisparo, "puffs": model.UnidadDisparo,
+	"inhalacion": model.UnidadDisparo, "inhalaciones": model.UnidadDisparo,
+	"aplicacion": model.UnidadAplicacion, "aplicaciones": model.UnidadAplicacion, "aplic": model.UnidadAplicacion,
+	"ampolla": model.UnidadAmpolla, "ampollas": model.UnidadAmpolla, "amp": model.UnidadAmpolla,
+}
+
+// Palabras que indican la vía; "vo" es la abreviatura de vía oral ("v.o.")
+var viasDosis = map[string]model.ViaAdministracion{
+	"vo":            model.ViaOral,
+	"oral":          model.ViaOral,
+	"sublingual":    model.ViaSublingual,
+	"sl":            model.ViaSublingual,
+	"topica":        model.ViaTopica,
+	"topico":        model.ViaTopica,
+	"oftalmica":     model.ViaOftalmica,
+	"ocular":        model.ViaOftalmica,
+	"otica":         model.ViaOtica,
+	"nasal":         model.ViaNasal,
+	"inhalatoria":   model.ViaInhalatoria,
+	"inhalada":      model.ViaInhalatoria,
+	"rectal":        model.ViaRectal,
+	"vaginal":       model.ViaVaginal,
+	"im":            model.ViaIntramuscular,
+	"intramuscular": model.ViaIntramuscular,
+	"sc":            model.ViaSubcutanea,
+	"subcutanea":    model.ViaSubcutanea,
+	"ev":            model.ViaIntravenosa,
+	"iv":            model.ViaIntravenosa,
+	"intravenosa":   model.ViaIntravenosa,
+	"endovenosa":    model.ViaIntravenosa,
+}
+
+// Vía que se asume cuando el texto no la indica y la unidad no deja dudas
+var viaPorUnidad = map[model.UnidadDosis]model.ViaAdministracion{
+	model.UnidadComprimido: model.ViaOral,
+	model.UnidadCapsula:    model.ViaOral,
+	model.UnidadSobre:      model.ViaOral,
+	model.UnidadDisparo:    model.ViaInhalatoria,
+}
+
+var numerosDosis = map[string]int{"una": 1, "dos": 2, "tres": 3, "cuatro": 4}
+
+// parsearDosis interpreta la dosis en texto libre ("1 cap. c/8h x 7d", "1/2 comp cada 12 horas por 2 semanas").
+// Devuelve false si no encuentra al menos cantidad, unidad y frecuencia; la duración y la vía son opcionales.
+func parsearDosis(texto string) (*model.Posologia, bool) {
+	t := reemplazosDosis.Replace(validacion.NormalizarTexto(texto))
+
+	m := reDosisCantidad.FindStringSubmatch(t)
+	if m == nil {
+		return nil, false
+	}
+	cantidad, ok := parsearCantidadDosis(m[1])
+	if !ok {
+		return nil, false
+	}
+	p := &model.Posologia{Cantidad: cantidad, Unidad: unidadesDosis[m[2]], Envases: 1}
+
+	switch {
+	case reDosisCadaHoras.MatchString(t):
+		p.FrecuenciaHoras, _ = strconv.Atoi(reDosisCadaHoras.FindStringSubmatch(t)[1])
+	case reDosisCadaDias.MatchString(t):
+		dias, _ := strconv.Atoi(reDosisCadaDias.FindStringSubmatch(t)[1])
+		p.FrecuenciaHoras = dias * 24
+	case reDosisVecesDia.MatchString(t):
+		veces := reDosisVecesDia.FindStringSubmatch(t)[1]
+		n, ok := numerosDosis[veces]
+		if !ok {
+			n, _ = strconv.Atoi(veces)
+		}
+		if n <= 0 || 24%n != 0 {
+			return nil, false
+		}
+		p.FrecuenciaHoras = 24 / n
+	case reDosisDiaria.MatchString(t):
+		p.FrecuenciaHoras = 24
+	default:
+		return nil, false
+	}
+
+	if d := reDosisDuracion.FindStringSubmatch(t); d != nil {
+		n, _ := strconv.Atoi(d[1])
+		switch {
+		case strings.HasPrefix(d[2], "sem"):
+			n *= 7
+		case strings.HasPrefix(d[2], "mes"):
+			n *= 30
+		}
+		p.DuracionDias = n
+	}
+
+	for _, palabra := range strings.FieldsFunc(t, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if via, ok := viasDosis[palabra]; ok {
+			p.Via = via
+			break
+		}
+	}
+	if validarPosologia(p) != nil {
+		return nil, false
+	}
+	return p, true
+}
+
+// parsearCantidadDosis acepta enteros, decimales con punto o coma y fracciones ("1/2")
+func parsearCantidadDosis(s string) (float64, bool) {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.Atoi(num)
+		d, err2 := strconv.Atoi(den)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return float64(n) / float64(d), true
+	}
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return v, err == nil
+}
+
+// validarPosologia normaliza unidad y vía y controla los rangos. Sin envases se asume uno y sin vía
+// la que corresponde a la unidad, si es inequívoca.
+func validarPosologia(p *model.Posologia) error {
+	p.Unidad = model.UnidadDosis(strings.ToUpper(strings.TrimSpace(string(p.Unidad))))
+	p.Via = model.ViaAdministracion(strings.ToUpper(strings.TrimSpace(string(p.Via))))
+
+	if p.Cantidad <= 0 {
+		return &ServiceError{Message: "La cantidad por toma debe ser mayor a 0"}
+	}
+	if !p.Unidad.Valida() {
+		return &ServiceError{Message: fmt.Sprintf("Unidad de dosis desconocida: %s", p.Unidad)}
+	}
+	if p.FrecuenciaHoras <= 0 || p.FrecuenciaHoras > frecuenciaHorasMaxima {
+		return &ServiceError{Message: fmt.Sprintf("La frecuencia debe estar entre 1 y %d horas", frecuenciaHorasMaxima)}
+	}
+	if p.DuracionDias < 0 || p.DuracionDias > duracionDiasMaxima {
+		return &ServiceError{Message: fmt.Sprintf("La duración debe estar entre 0 y %d días", duracionDiasMaxima)}
+	}
+	if p.Via == "" {
+		p.Via = viaPorUnidad[p.Unidad]
+	}
+	if p.Via != "" && !p.Via.Valida() {
+		return &ServiceError{Message: fmt.Sprintf("Vía de administración desconocida: %s", p.Via)}
+	}
+	if p.Envases < 0 {
+		return &ServiceError{Message: "La cantidad de envases no puede ser negativa"}
+	}
+	if p.Envases == 0 {
+		p.Envases = 1
+	}
+
+	p.CantidadTotal = 0
+	p.FechaFin = nil
+	return nil
+}
+
+// prepararDosis arma la dosis y la posología de un alta o modificación. Con posología estructurada la
+// dosis en texto se genera a partir de ella; con solo texto se intenta interpretarlo y, si no se puede,
+// la receta queda con la dosis libre y sin posología.
+func prepararDosis(dosis string, posologia *model.Posologia) (string, *model.Posologia, error) {
+	if posologia != nil {
+		if err := validarPosologia(posologia); err != nil {
+			return "", nil, err
+		}
+		return posologia.Texto(), posologia, nil
+	}
+
+	dosis = strings.TrimSpace(dosis)
+	if dosis == "" {
+		return "", nil, &ServiceError{Message: "Se requiere la posología o la dosis"}
+	}
+	p, _ := parsearDosis(dosis)
+	return dosis, p, nil
+}
+
+// calcularTratamiento completa la cantidad total y la fecha de fin, contando desde inicio.
+// Sin duración el tratamiento no tiene fin definido y no se calculan.
+func calcularTratamiento(p *model.Posologia, inicio time.Time) {
+	if p == nil || p.DuracionDias <= 0 || p.FrecuenciaHoras <= 0 {
+		return
+	}
+	tomas := math.Ceil(float64(p.DuracionDias*24) / float64(p.FrecuenciaHoras))
+	p.CantidadTotal = math.Round(p.Cantidad*tomas*100) / 100
+	fin := inicio.AddDate(0, 0, p.DuracionDias)
+	p.FechaFin = &fin
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"prestadores-api/internal/model"
+	"testing"
+)
+
+func TestParsearDosis(t *testing.T) {
+	casos := []struct {
+		texto string
+		want  *model.Posologia // nil = no se puede interpretar
+	}{
+		{"1 cap. c/8h x 7d", &model.Posologia{Cantidad: 1, Unidad: model.UnidadCapsula, FrecuenciaHoras: 8, DuracionDias: 7, Via: model.ViaOral}},
+		{"1/2 comp cada 12 horas por 2 semanas", &model.Posologia{Cantidad: 0.5, Unidad: model.UnidadComprimido, FrecuenciaHoras: 12, DuracionDias: 14, Via: model.ViaOral}},
+		{"½ comp cada 12 horas", &model.Posologia{Cantidad: 0.5, Unidad: model.UnidadComprimido, FrecuenciaHoras: 12, Via: model.ViaOral}},
+		{"1 comp/día x 10 días", &model.Posologia{Cantidad: 1, Unidad: model.UnidadComprimido, FrecuenciaHoras: 24, DuracionDias: 10, Via: model.ViaOral}},
+		{"1 comp / dia", &model.Posologia{Cantidad: 1, Unidad: model.UnidadComprimido, FrecuenciaHoras: 24, Via: model.ViaOral}},
+		{"1 comp c/día durante 1 mes", &model.Posologia{Cantidad: 1, Unidad: model.UnidadComprimido, FrecuenciaHoras: 24, DuracionDias: 30, Via: model.ViaOral}},
+		{"1 comprimido por día", &model.Posologia{Cantidad: 1, Unidad: model.UnidadComprimido, FrecuenciaHoras: 24, Via: model.ViaOral}},
+		{"1 sobre diario", &model.Posologia{Cantidad: 1, Unidad: model.UnidadSobre, FrecuenciaHoras: 24, Via: model.ViaOral}},
+		{"2 gotas 3 veces por día x 5 días vía oftálmica", &model.Posologia{Cantidad: 2, Unidad: model.UnidadGota, FrecuenciaHoras: 8, DuracionDias: 5, Via: model.ViaOftalmica}},
+		{"2 puffs dos veces al dia", &model.Posologia{Cantidad: 2, Unidad: model.UnidadDisparo, FrecuenciaHoras: 12, Via: model.ViaInhalatoria}},
+		{"7,5 ml c/6 hs v.o.", &model.Posologia{Cantidad: 7.5, Unidad: model.UnidadML, FrecuenciaHoras: 6, Via: model.ViaOral}},
+		{"1 amp IM cada 3 días", &model.Posologia{Cantidad: 1, Unidad: model.UnidadAmpolla, FrecuenciaHoras: 72, Via: model.ViaIntramuscular}},
+		{"1 aplicación c/12h tópica", &model.Posologia{Cantidad: 1, Unidad: model.UnidadAplicacion, FrecuenciaHoras: 12, Via: model.ViaTopica}},
+		{"1 inhalación cada 6 horas", &model.Posologia{Cantidad: 1, Unidad: model.UnidadDisparo, FrecuenciaHoras: 6, Via: model.ViaInhalatoria}},
+		{"2 inhalaciones c/12h", &model.Posologia{Cantidad: 2, Unidad: model.UnidadDisparo, FrecuenciaHoras: 12, Via: model.ViaInhalatoria}},
+		{"según indicación médica", nil},
+		{"1 comp", nil},
+		{"tomar c/8h", nil},
+		{"1 comp 5 veces por día", nil},
+		{"1 comp c/8h x 400 días", nil},
+		{"1 comp c/1000h", nil},
+		{"0 comp c/8h", nil},
+		{"1/0 comp c/8h", nil},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.texto, func(t *testing.T) {
+			got, ok := parsearDosis(tc.texto)
+			if tc.want == nil {
+				if ok {
+					t.Fatalf("parsearDosis(%q) = %+v, se esperaba que no se pudiera interpretar", tc.texto, got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("parsearDosis(%q) no se pudo interpretar", tc.texto)
+			}
+			if got.Cantidad != tc.want.Cantidad || got.Unidad != tc.want.Unidad || got.FrecuenciaHoras != tc.want.FrecuenciaHoras ||
+				got.DuracionDias != tc.want.DuracionDias || got.Via != tc.want.Via || got.Envases != 1 {
+				t.Errorf("parsearDosis(%q) = %+v, se esperaba %+v con 1 envase", tc.texto, *got, *tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrCodigoVerificacionNoEncontrado indica que ninguna receta tiene ese código
+var ErrCodigoVerificacionNoEncontrado = repository.ErrCodigoVerificacionNoEncontrado
+
+// Alfabeto del código de verificación: sin 0, 1, I ni O para que se pueda dictar sin confusiones
+const alfabetoCodigoVerificacion = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// RecetaNoAprobadaError indica que se pidió la receta electrónica de una receta que no está APROBADO
+// ni PARCIALMENTE_DISPENSADA
+type RecetaNoAprobadaError struct {
+	Estado model.EstadoReceta
+}
+
+func (e *RecetaNoAprobadaError) Error() string {
+	return fmt.Sprintf("Solo las recetas aprobadas tienen receta electrónica; la receta está %s", e.Estado)
+}
+
+// RecetaNoDispensableError indica que la farmacia no puede dispensar la receta
+type RecetaNoDispensableError struct {
+	Motivo string
+}
+
+func (e *RecetaNoDispensableError) Error() string {
+	return e.Motivo
+}
+
+type RecetaElectronicaService interface {
+	// GenerarPDF devuelve el PDF de una receta aprobada, aunque tenga envases dispensados, con su código de verificación y QR
+	GenerarPDF(id int) ([]byte, *model.RecetaDetalle, error)
+	Verificar(codigo string) (*model.VerificacionReceta, error)
+	Dispensar(codigo string, req model.DispensarRecetaRequest, farmacia model.UsuarioAutenticado) (*model.VerificacionReceta, error)
+}
+
+type recetaElectronicaServiceImpl struct {
+	repo            repository.RecetaRepository
+	urlVerificacion string
+	logger          *zap.Logger
+}
+
+// NewRecetaElectronicaService recibe la URL pública de verificación; el QR apunta a urlVerificacion/<código>
+func NewRecetaElectronicaService(repo repository.RecetaRepository, urlVerificacion string, logger *zap.Logger) RecetaElectronicaService {
+	return &recetaElectronicaServiceImpl{
+		repo:            repo,
+		urlVerificacion: strings.TrimRight(urlVerificacion, "/"),
+		logger:          logger,
+	}
+}
+
+// generarCodigoVerificacion devuelve un código aleatorio de 12 caracteres, p. ej. "7KQ2-M9XD-4HTP"
+func generarCodigoVerificacion() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error al generar código de verificación: %w", err)
+	}
+	for i := range b {
+		b[i] = alfabetoCodigoVerificacion[int(b[i])%len(alfabetoCodigoVerificacion)]
+	}
+	return fmt.Sprintf("%s-%s-%s", b[0:4], b[4:8], b[8:12]), nil
+}
+
+// normalizarCodigoVerificacion acepta el código en minúsculas, sin guiones o con espacios
+func normalizarCodigoVerificacion(codigo string) string {
+	c := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(codigo))
+	if len(c) != 12 {
+		return c
+	}
+	return c[0:4] + "-" + c[4:8] + "-" + c[8:12]
+}
+
+// asignarCodigoVerificacion le da código a una receta aprobada que todavía no lo tiene
+func asignarCodigoVerificacion(repo repository.RecetaRepository, detalle *model.RecetaDetalle) (*model.RecetaDetalle, error) {
+	if detalle.CodigoVerificacion != "" {
+		return detalle, nil
+	}
+	codigo, err := generarCodigoVerificacion()
+	if err != nil {
+		return nil, err
+	}
+	return repo.AsignarCodigoVerificacion(detalle.ID, codigo)
+}
+
+// fechaAprobacion es la fecha del último paso a APROBADO en el historial
+func fechaAprobacion(detalle *model.RecetaDetalle) time.Time {
+	for i := len(detalle.Historial) - 1; i >= 0; i-- {
+		if detalle.Historial[i].Estado == model.RecetaEstadoAprobado {
+			return detalle.Historial[i].FechaCambio
+		}
+	}
+	return detalle.FechaActualizacion
+}
+
+// enmascararDNI deja visibles solo los últimos tres dígitos
+func enmascararDNI(dni string) string {
+	if len(dni) <= 3 {
+		return dni
+	}
+	return strings.Repeat("*", len(dni)-3) + dni[len(dni)-3:]
+}
+
+// envasesPrescriptos son los envases de la posología; las recetas sin posología son de un envase
+func envasesPrescriptos(detalle *model.RecetaDetalle) int {
+	if detalle.Posologia != nil && detalle.Posologia.Envases > 0 {
+		return detalle.Posologia.Envases
+	}
+	return 1
+}
+
+// verificacionReceta arma la respuesta pública; la receta es válida si está vigente a la fecha
+func verificacionReceta(detalle *model.RecetaDetalle, ahora time.Time) *model.VerificacionReceta {
+	detalle = conTratamiento(detalle)
+
+	afiliado := detalle.Afiliado
+	afiliado.DNI = enmascararDNI(afiliado.DNI)
+
+	prescriptos := envasesPrescriptos(detalle)
+	v := &model.VerificacionReceta{
+		Codigo:             detalle.CodigoVerificacion,
+		Estado:             detalle.Estado,
+		FechaEmision:       fechaAprobacion(detalle),
+		FechaVencimiento:   detalle.FechaVencimiento,
+		Afiliado:           afiliado,
+		Prestador:          detalle.Prestador,
+		DrogaGenerica:      detalle.DrogaGenerica,
+		Medicamento:        detalle.Medicamento,
+		MarcaSugerida:      detalle.MarcaSugerida,
+		Dosis:              detalle.Dosis,
+		Posologia:          detalle.Posologia,
+		EnvasesPrescriptos: prescriptos,
+		EnvasesPendientes:  max(prescriptos-detalle.EnvasesDispensados(), 0),
+		Dispensas:          detalle.Dispensas,
+	}
+
+	switch {
+	case detalle.Vigente(ahora):
+		v.Valida = true
+	case detalle.Estado == model.RecetaEstadoDispensada:
+		v.Motivo = "La receta ya fue dispensada"
+	case detalle.FechaVencimiento != nil && !detalle.FechaVencimiento.After(ahora):
+		// VENCIDA, o aprobada con la vigencia cumplida que el job todavía no procesó
+		v.Motivo = fmt.Sprintf("La receta venció el %s", detalle.FechaVencimiento.Format("02/01/2006"))
+	default:
+		v.Motivo = fmt.Sprintf("La receta está %s", detalle.Estado)
+	}
+	if !v.Valida {
+		v.EnvasesPendientes = 0
+	}
+	return v
+}
+
+func (s *recetaElectronicaServiceImpl) GenerarPDF(id int) ([]byte, *model.RecetaDetalle, error) {
+	s.logger.Info("Generando receta electrónica", zap.Int("id", id))
+
+	detalle, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Warn("Receta no encontrada", zap.Int("id", id), zap.Error(err))
+		return nil, nil, err
+	}
+
+	if detalle.Estado != model.RecetaEstadoAprobado && detalle.Estado != model.RecetaEstadoParcialmenteDispensada {
+		s.logger.Warn("Receta no aprobada", zap.Int("id", id), zap.String("estado", string(detalle.Estado)))
+		return nil, nil, &RecetaNoAprobadaError{Estado: detalle.Estado}
+	}
+
+	// Las recetas aprobadas antes de la receta electrónica reciben el código acá
+	detalle, err = asignarCodigoVerificacion(s.repo, detalle)
+	if err != nil {
+		s.logger.Error("Error al asignar código de verificación", zap.Int("id", id), zap.Error(err))
+		return nil, nil, err
+	}
+	detalle = conTratamiento(detalle)
+
+	pdf, err := generarPDFReceta(detalle, fechaAprobacion(detalle), s.urlVerificacion+"/"+detalle.CodigoVerificacion)
+	if err != nil {
+		s.logger.Error("Error al generar PDF de receta", zap.Int("id", id), zap.Error(err))
+		return nil, nil, err
+	}
+	return pdf, detalle, nil
+}
+
+func (s *recetaElectronicaServiceImpl) Verificar(codigo string) (*model.VerificacionReceta, error) {
+	codigo = normalizarCodigoVerificacion(codigo)
+	s.logger.Info("Verificando receta electrónica", zap.String("codigo", codigo))
+
+	detalle, err := s.repo.GetByCodigoVerificacion(codigo)
+	if err != nil {
+		s.logger.Warn("Código de verificación inexistente", zap.String("codigo", codigo), zap.Error(err))
+		return nil, err
+	}
+	return verificacionReceta(detalle, time.Now().UTC()), nil
+}
+
+func (s *recetaElectronicaServiceImpl) Dispensar(codigo string, req model.DispensarRecetaRequest, farmacia model.UsuarioAutenticado) (*model.VerificacionReceta, error) {
+	codigo = normalizarCodigoVerificacion(codigo)
+	s.logger.Info("Dispensando receta electrónica",
+		zap.String("codigo", codigo),
+		zap.String("farmaciaCuit", farmacia.CUIT),
+		zap.String("usuario", farmacia.Username),
+	)
+
+	detalle, err := s.repo.GetByCodigoVerificacion(codigo)
+	if err != nil {
+		s.logger.Warn("Código de verificación inexistente", zap.String("codigo", codigo), zap.Error(err))
+		return nil, err
+	}
+	ahora := time.Now().UTC()
+	v := verificacionReceta(detalle, ahora)
+	if !v.Valida {
+		s.logger.Warn("Receta no dispensable", zap.String("codigo", codigo), zap.String("motivo", v.Motivo))
+		return nil, &RecetaNoDispensableError{Motivo: v.Motivo}
+	}
+
+	// Sin cantidad se entregan todos los envases pendientes
+	envases := req.Envases
+	if envases == 0 {
+		envases = v.EnvasesPendientes
+	}
+	if envases < 0 || envases > v.EnvasesPendientes {
+		s.logger.Warn("Cantidad de envases inválida", zap.String("codigo", codigo), zap.Int("envases", req.Envases))
+		return nil, &ServiceError{Message: fmt.Sprintf("La cantidad de envases debe estar entre 1 y %d", v.EnvasesPendientes)}
+	}
+
+	dispensa := model.DispensaReceta{Fecha: ahora, FarmaciaCUIT: farmacia.CUIT, Envases: envases}
+	detalle, err = s.repo.Dispensar(detalle.ID, dispensa, v.EnvasesPrescriptos)
+	if err != nil {
+		// Otra farmacia la dispensó o el job la venció entre la verificación y la dispensa
+		if errors.Is(err, repository.ErrRecetaNoDispensable) || errors.Is(err, repository.ErrEnvasesExcedidos) {
+			s.logger.Warn("Receta dispensada o vencida en paralelo", zap.String("codigo", codigo), zap.Error(err))
+			return nil, &RecetaNoDispensableError{Motivo: "La receta cambió mientras se dispensaba, volver a verificarla"}
+		}
+		s.logger.Error("Error al dispensar receta", zap.String("codigo", codigo), zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Receta dispensada",
+		zap.Int("id", detalle.ID),
+		zap.String("farmaciaCuit", farmacia.CUIT),
+		zap.Int("envases", envases),
+		zap.String("estado", string(detalle.Estado)),
+	)
+	return verificacionReceta(detalle, ahora), nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"prestadores-api/internal/model"
+	"strconv"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// generarPDFReceta arma el PDF A4 de la receta electrónica con el QR que apunta a urlVerificacion
+func generarPDFReceta(detalle *model.RecetaDetalle, emision time.Time, urlVerificacion string) ([]byte, error) {
+	qr, err := qrcode.Encode(urlVerificacion, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("error al generar QR: %w", err)
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Receta %d", detalle.ID), true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(false, 20)
+	pdf.AddPage()
+
+	// Las fuentes estándar de PDF usan cp1252: las tildes se traducen desde UTF-8
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, tr("Receta electrónica"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("N° %d - Emitida el %s", detalle.ID, emision.Format("02/01/2006"))), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	seccion := func(titulo string) {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.SetFillColor(230, 230, 230)
+		pdf.CellFormat(0, 7, tr(titulo), "", 1, "L", true, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.Ln(1)
+	}
+	campo := func(etiqueta, valor string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(45, 6, tr(etiqueta), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(valor), "", "L", false)
+	}
+
+	seccion("Prestador")
+	campo("Nombre", detalle.Prestador.Nombre)
+	campo("CUIT", detalle.Prestador.CUIT)
+	pdf.Ln(3)
+
+	seccion("Afiliado")
+	campo("Apellido y nombre", detalle.Afiliado.Apellido+", "+detalle.Afiliado.Nombre)
+	campo("DNI", detalle.Afiliado.DNI)
+	campo("N° de afiliado", strconv.Itoa(detalle.Afiliado.ID))
+	pdf.Ln(3)
+
+	seccion("Rp/")
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.MultiCell(0, 7, tr(detalle.Medicamento), "", "L", false)
+	pdf.SetFont("Helvetica", "", 10)
+	if detalle.MarcaSugerida != "" {
+		campo("Marca sugerida", detalle.MarcaSugerida)
+	}
+	campo("Posología", detalle.Dosis)
+	if p := detalle.Posologia; p != nil {
+		campo("Envases", strconv.Itoa(p.Envases))
+		if p.DuracionDias > 0 {
+			campo("Duración", fmt.Sprintf("%d días", p.DuracionDias))
+			campo("Cantidad total", strconv.FormatFloat(p.CantidadTotal, 'f', -1, 64))
+		}
+		if p.FechaFin != nil {
+			campo("Fin del tratamiento", p.FechaFin.Format("02/01/2006"))
+		}
+	}
+	if detalle.FechaVencimiento != nil {
+		campo("Válida hasta", detalle.FechaVencimiento.Format("02/01/2006"))
+	}
+	pdf.Ln(6)
+
+	// Código de verificación con el QR a la izquierda
+	y := pdf.GetY()
+	pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", 20, y, 40, 40, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetXY(65, y+8)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr("Código de verificación"), "", 2, "L", false, 0, "")
+	pdf.SetFont("Courier", "B", 16)
+	pdf.CellFormat(0, 9, detalle.CodigoVerificacion, "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.MultiCell(0, 4, tr("La farmacia puede verificar la receta escaneando el QR o en "+urlVerificacion), "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("error al generar PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
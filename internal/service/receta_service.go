@@ -1,43 +1,82 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/repository"
+	"slices"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type RecetaService interface {
-	GetRecetas(estado string, query string, page int, size int, sort string) (*model.PaginatedRecetasResponse, error)
+	GetRecetas(estado string, vigencia string, query string, page int, size int, sort string) (*model.PaginatedRecetasResponse, error)
 	GetRecetaByID(id int) (*model.RecetaDetalle, error)
-	CreateReceta(req model.CreateRecetaRequest) (*model.CreateRecetaResponse, error)
-	UpdateReceta(id int, req model.UpdateRecetaRequest) error
-	CambiarEstadoReceta(id int, req model.CambioEstadoRecetaRequest) (*model.CambioEstadoRecetaResponse, error)
+	CreateReceta(req model.CreateRecetaRequest, usuario model.UsuarioAutenticado) (*model.CreateRecetaResponse, error)
+	// UpdateReceta devuelve las advertencias no bloqueantes si cambia el medicamento
+	UpdateReceta(id int, req model.UpdateRecetaRequest, usuario model.UsuarioAutenticado) ([]model.AdvertenciaReceta, error)
+	CambiarEstadoReceta(id int, req model.CambioEstadoRecetaRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoRecetaResponse, error)
+	// VencerRecetas pasa a VENCIDA las recetas con la vigencia cumplida y devuelve cuántas venció
+	VencerRecetas(ahora time.Time) (int, error)
 }
 
 type recetaServiceImpl struct {
-	repo   repository.RecetaRepository
-	logger *zap.Logger
+	repo          repository.RecetaRepository
+	afiliados     repository.AfiliadoRepository
+	vademecum     repository.VademecumRepository
+	interacciones repository.InteraccionRepository
+	elegibilidad  ElegibilidadService
+	vigencia      VigenciaRecetas
+	logger        *zap.Logger
 }
 
-func NewRecetaService(repo repository.RecetaRepository, logger *zap.Logger) RecetaService {
+func NewRecetaService(repo repository.RecetaRepository, afiliados repository.AfiliadoRepository, vademecum repository.VademecumRepository, interacciones repository.InteraccionRepository, elegibilidad ElegibilidadService, vigencia VigenciaRecetas, logger *zap.Logger) RecetaService {
 	return &recetaServiceImpl{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		afiliados:     afiliados,
+		vademecum:     vademecum,
+		interacciones: interacciones,
+		elegibilidad:  elegibilidad,
+		vigencia:      vigencia,
+		logger:        logger,
 	}
 }
 
-func (s *recetaServiceImpl) GetRecetas(estado string, query string, page int, size int, sort string) (*model.PaginatedRecetasResponse, error) {
+// prescripcionGenerica arma los datos de la receta a partir del producto del vademécum:
+// se prescribe por droga genérica y la marca va solo si el prescriptor la sugiere
+func prescripcionGenerica(m *model.Medicamento, sugerirMarca bool) (droga, medicamento, marca string) {
+	if sugerirMarca {
+		marca = m.MarcaComercial
+	}
+	return m.DrogaGenerica, m.NombreGenerico(), marca
+}
+
+func (s *recetaServiceImpl) GetRecetas(estado string, vigencia string, query string, page int, size int, sort string) (*model.PaginatedRecetasResponse, error) {
 	s.logger.Info("Obteniendo recetas",
 		zap.String("estado", estado),
+		zap.String("vigencia", vigencia),
 		zap.String("query", query),
 		zap.Int("page", page),
 		zap.Int("size", size),
 		zap.String("sort", sort),
 	)
 
-	items, total, err := s.repo.GetAll(estado, query, page, size, sort)
+	filtroVigencia := model.VigenciaReceta(strings.ToUpper(vigencia))
+	if filtroVigencia != "" && !filtroVigencia.Valida() {
+		s.logger.Warn("Parámetro vigencia inválido", zap.String("vigencia", vigencia))
+		return nil, &ServiceError{Message: fmt.Sprintf("Vigencia inválida: %s. Valores posibles: %s, %s, %s",
+			vigencia, model.VigenciaVigente, model.VigenciaPorVencer, model.VigenciaVencida)}
+	}
+
+	items, total, err := s.repo.GetAll(estado, filtroVigencia, query, page, size, sort)
 	if err != nil {
+		if errors.Is(err, repository.ErrOrdenInvalido) {
+			s.logger.Warn("Parámetro sort inválido", zap.String("sort", sort), zap.Error(err))
+			return nil, &ServiceError{Message: err.Error()}
+		}
 		s.logger.Error("Error al obtener recetas", zap.Error(err))
 		return nil, err
 	}
@@ -61,16 +100,92 @@ func (s *recetaServiceImpl) GetRecetaByID(id int) (*model.RecetaDetalle, error)
 		return nil, err
 	}
 
-	return detalle, nil
+	return conTratamiento(detalle), nil
 }
 
-func (s *recetaServiceImpl) CreateReceta(req model.CreateRecetaRequest) (*model.CreateRecetaResponse, error) {
+// conTratamiento devuelve una copia del detalle con la posología completa: las recetas con dosis en
+// texto libre se interpretan al leerlas y se calculan la cantidad total y la fecha de fin
+func conTratamiento(detalle *model.RecetaDetalle) *model.RecetaDetalle {
+	copia := *detalle
+	if copia.Posologia != nil {
+		p := *copia.Posologia
+		copia.Posologia = &p
+	} else if p, ok := parsearDosis(copia.Dosis); ok {
+		copia.Posologia = p
+	}
+	calcularTratamiento(copia.Posologia, copia.FechaCreacion)
+	return &copia
+}
+
+func (s *recetaServiceImpl) CreateReceta(req model.CreateRecetaRequest, usuario model.UsuarioAutenticado) (*model.CreateRecetaResponse, error) {
+	// El historial registra al prestador autenticado, nunca un dato del body
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+	req.Prestador = model.PrestadorBasico{ID: usuario.ID, CUIT: usuario.CUIT, Nombre: usuario.Nombre}
+
 	s.logger.Info("Creando receta",
 		zap.Int("afiliadoId", req.AfiliadoID),
-		zap.String("medicamento", req.Medicamento),
+		zap.String("medicamentoCodigo", req.MedicamentoCodigo),
 		zap.String("dosis", req.Dosis),
 	)
 
+	if err := validarEstadoInicial(req.EstadoInicial, model.RecetaEstadoRecibido); err != nil {
+		s.logger.Warn("Estado inicial inválido", zap.String("estadoInicial", string(req.EstadoInicial)))
+		return nil, err
+	}
+
+	dosis, posologia, err := prepararDosis(req.Dosis, req.Posologia)
+	if err != nil {
+		s.logger.Warn("Posología inválida", zap.String("dosis", req.Dosis), zap.Error(err))
+		return nil, err
+	}
+	if posologia == nil {
+		s.logger.Warn("Dosis en texto libre sin interpretar", zap.String("dosis", dosis))
+	}
+	req.Dosis, req.Posologia = dosis, posologia
+
+	medicamento, err := resolverMedicamento(s.vademecum, req.MedicamentoCodigo)
+	if err != nil {
+		s.logger.Warn("Código de medicamento inválido", zap.String("medicamentoCodigo", req.MedicamentoCodigo), zap.Error(err))
+		return nil, err
+	}
+	req.MedicamentoCodigo = medicamento.Codigo
+	req.DrogaGenerica, req.Medicamento, req.MarcaSugerida = prescripcionGenerica(medicamento, req.SugerirMarca)
+
+	// Los datos del afiliado se copian del padrón al momento de crear la solicitud
+	afiliado, err := resolverAfiliado(s.afiliados, req.AfiliadoID)
+	if err != nil {
+		s.logger.Warn("Afiliado inexistente", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := verificarElegible(s.elegibilidad, *afiliado, model.PrestacionMedicamentos); err != nil {
+		s.logger.Warn("Afiliado no elegible", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+	req.Afiliado = afiliado.Basico()
+
+	advertencias, err := s.advertenciasAfiliado(afiliado.ID, 0, req.DrogaGenerica)
+	if err != nil {
+		return nil, err
+	}
+	if req.MotivoAlta, err = controlarForzado(advertencias, req.Forzar, req.Justificacion, "Creada"); err != nil {
+		s.logger.Warn("Receta con advertencias bloqueantes",
+			zap.Int("afiliadoId", afiliado.ID),
+			zap.String("drogaGenerica", req.DrogaGenerica),
+			zap.Int("advertencias", len(advertencias)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if req.MotivoAlta != "" {
+		s.logger.Warn("Receta forzada con advertencias",
+			zap.Int("afiliadoId", afiliado.ID),
+			zap.String("drogaGenerica", req.DrogaGenerica),
+			zap.String("usuario", req.Usuario),
+		)
+	}
+
 	detalle, err := s.repo.Create(req)
 	if err != nil {
 		s.logger.Error("Error al crear receta", zap.Error(err))
@@ -82,28 +197,116 @@ func (s *recetaServiceImpl) CreateReceta(req model.CreateRecetaRequest) (*model.
 		Tipo:          detalle.Tipo,
 		Estado:        detalle.Estado,
 		FechaCreacion: detalle.FechaCreacion,
+		Advertencias:  advertencias,
 	}
 
 	return response, nil
 }
 
-func (s *recetaServiceImpl) UpdateReceta(id int, req model.UpdateRecetaRequest) error {
+// advertenciasAfiliado compara la droga con las recetas activas del afiliado y las de los últimos días,
+// sin contar la receta excluirID (la que se está editando)
+func (s *recetaServiceImpl) advertenciasAfiliado(afiliadoID int, excluirID int, droga string) ([]model.AdvertenciaReceta, error) {
+	ahora := time.Now().UTC()
+	activas, err := s.repo.GetActivasByAfiliado(afiliadoID, ahora, ahora.Add(-ventanaRecetasRecientes))
+	if err != nil {
+		s.logger.Error("Error al obtener recetas activas del afiliado", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+	activas = slices.DeleteFunc(activas, func(r model.RecetaListItem) bool { return r.ID == excluirID })
+	return advertenciasReceta(droga, activas, s.interacciones), nil
+}
+
+func (s *recetaServiceImpl) UpdateReceta(id int, req model.UpdateRecetaRequest, usuario model.UsuarioAutenticado) ([]model.AdvertenciaReceta, error) {
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+
 	s.logger.Info("Actualizando receta",
 		zap.Int("id", id),
-		zap.String("medicamento", req.Medicamento),
+		zap.String("medicamentoCodigo", req.MedicamentoCodigo),
 		zap.String("dosis", req.Dosis),
+		zap.String("usuario", req.Usuario),
 	)
 
-	err := s.repo.Update(id, req)
+	actual, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener receta", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+	if err := verificarPropietario(actual.Prestador.ID, usuario); err != nil {
+		s.logger.Warn("Receta de otro prestador", zap.Int("id", id), zap.Int("prestadorId", actual.Prestador.ID), zap.Int("usuarioId", usuario.ID))
+		return nil, err
+	}
+	// Aprobada ya tiene código de verificación: medicamento y envases no pueden cambiar
+	if err := validarEditable(actual.Estado, model.RecetaEstadoRecibido, model.RecetaEstadoObservado); err != nil {
+		s.logger.Warn("Receta no editable", zap.Int("id", id), zap.String("estado", string(actual.Estado)))
+		return nil, err
+	}
+
+	if req.Posologia != nil || req.Dosis != "" {
+		dosis, posologia, err := prepararDosis(req.Dosis, req.Posologia)
+		if err != nil {
+			s.logger.Warn("Posología inválida", zap.String("dosis", req.Dosis), zap.Error(err))
+			return nil, err
+		}
+		if posologia == nil {
+			s.logger.Warn("Dosis en texto libre sin interpretar", zap.String("dosis", dosis))
+		}
+		req.Dosis, req.Posologia = dosis, posologia
+	}
+
+	var advertencias []model.AdvertenciaReceta
+	if req.MedicamentoCodigo != "" {
+		medicamento, err := resolverMedicamento(s.vademecum, req.MedicamentoCodigo)
+		if err != nil {
+			s.logger.Warn("Código de medicamento inválido", zap.String("medicamentoCodigo", req.MedicamentoCodigo), zap.Error(err))
+			return nil, err
+		}
+		req.MedicamentoCodigo = medicamento.Codigo
+		req.DrogaGenerica, req.Medicamento, req.MarcaSugerida = prescripcionGenerica(medicamento, req.SugerirMarca)
+
+		// El cambio de medicamento pasa por los mismos controles que el alta
+		if advertencias, err = s.advertenciasAfiliado(actual.Afiliado.ID, id, req.DrogaGenerica); err != nil {
+			return nil, err
+		}
+		if req.MotivoForzado, err = controlarForzado(advertencias, req.Forzar, req.Justificacion, "Modificada"); err != nil {
+			s.logger.Warn("Receta con advertencias bloqueantes",
+				zap.Int("id", id),
+				zap.String("drogaGenerica", req.DrogaGenerica),
+				zap.Int("advertencias", len(advertencias)),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		if req.MotivoForzado != "" {
+			s.logger.Warn("Receta forzada con advertencias",
+				zap.Int("id", id),
+				zap.String("drogaGenerica", req.DrogaGenerica),
+				zap.String("usuario", req.Usuario),
+			)
+		}
+	}
+
+	err = s.repo.Update(id, req)
+	if errors.Is(err, repository.ErrEstadoModificado) {
+		// El auditor la tomó después de validar que era editable: se responde con el estado nuevo
+		s.logger.Warn("Receta modificada concurrentemente", zap.Int("id", id), zap.String("estadoEsperado", string(actual.Estado)))
+		if actual, err = s.repo.GetByID(id); err != nil {
+			return nil, err
+		}
+		return nil, solicitudNoEditable(actual.Estado, model.RecetaEstadoRecibido, model.RecetaEstadoObservado)
+	}
 	if err != nil {
 		s.logger.Error("Error al actualizar receta", zap.Int("id", id), zap.Error(err))
-		return err
+		return nil, err
 	}
 
-	return nil
+	return advertencias, nil
 }
 
-func (s *recetaServiceImpl) CambiarEstadoReceta(id int, req model.CambioEstadoRecetaRequest) (*model.CambioEstadoRecetaResponse, error) {
+func (s *recetaServiceImpl) CambiarEstadoReceta(id int, req model.CambioEstadoRecetaRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoRecetaResponse, error) {
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+
 	s.logger.Info("Cambiando estado de receta",
 		zap.Int("id", id),
 		zap.String("nuevoEstado", string(req.NuevoEstado)),
@@ -118,20 +321,84 @@ func (s *recetaServiceImpl) CambiarEstadoReceta(id int, req model.CambioEstadoRe
 		return nil, ErrMotivoRequerido
 	}
 
-	// aca tenemos q agregar las validaciones adicionales q tengamos
+	actual, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener receta", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if err := validarTransicion(transicionesReceta, actual.Estado, req.NuevoEstado, usuario.Rol); err != nil {
+		s.logger.Warn("Transición de estado no permitida",
+			zap.Int("id", id),
+			zap.String("estadoActual", string(actual.Estado)),
+			zap.String("nuevoEstado", string(req.NuevoEstado)),
+			zap.String("rol", string(usuario.Rol)),
+		)
+		return nil, err
+	}
+
+	if req.NuevoEstado == model.RecetaEstadoAprobado {
+		vencimiento := s.fechaVencimiento(actual, time.Now().UTC())
+		req.FechaVencimiento = &vencimiento
+	}
 
-	detalle, err := s.repo.CambiarEstado(id, req)
+	detalle, err := s.repo.CambiarEstado(id, actual.Estado, req)
+	if errors.Is(err, repository.ErrEstadoModificado) {
+		// Otro usuario la cambió después de validar la transición: se responde con el estado nuevo
+		s.logger.Warn("Cambio de estado concurrente", zap.Int("id", id), zap.String("estadoEsperado", string(actual.Estado)))
+		if actual, err = s.repo.GetByID(id); err != nil {
+			return nil, err
+		}
+		return nil, transicionInvalida(transicionesReceta, actual.Estado, req.NuevoEstado)
+	}
 	if err != nil {
 		s.logger.Error("Error al cambiar estado de receta", zap.Int("id", id), zap.Error(err))
 		return nil, err
 	}
 
+	// Al aprobarla se emite la receta electrónica. Si falla, el código se asigna al pedir el PDF.
+	if detalle.Estado == model.RecetaEstadoAprobado {
+		if conCodigo, err := asignarCodigoVerificacion(s.repo, detalle); err != nil {
+			s.logger.Error("Error al asignar código de verificación", zap.Int("id", id), zap.Error(err))
+		} else {
+			detalle = conCodigo
+		}
+	}
+
 	response := &model.CambioEstadoRecetaResponse{
 		ID:                 detalle.ID,
 		Tipo:               detalle.Tipo,
 		Estado:             detalle.Estado,
 		FechaActualizacion: detalle.FechaActualizacion,
+		CodigoVerificacion: detalle.CodigoVerificacion,
+		FechaVencimiento:   detalle.FechaVencimiento,
 	}
 
 	return response, nil
 }
+
+// fechaVencimiento calcula el fin de la vigencia de una receta que se aprueba, según la condición
+// de venta del medicamento. Si el medicamento ya no está en el vademécum se usa la vigencia general.
+func (s *recetaServiceImpl) fechaVencimiento(receta *model.RecetaDetalle, aprobacion time.Time) time.Time {
+	dias := s.vigencia.Dias
+	if medicamento, err := s.vademecum.GetByCodigo(receta.MedicamentoCodigo); err == nil {
+		dias = s.vigencia.DiasPara(medicamento.CondicionVenta)
+	} else {
+		s.logger.Warn("Medicamento de la receta fuera del vademécum, se usa la vigencia general",
+			zap.Int("id", receta.ID),
+			zap.String("medicamentoCodigo", receta.MedicamentoCodigo),
+		)
+	}
+	return aprobacion.AddDate(0, 0, dias)
+}
+
+func (s *recetaServiceImpl) VencerRecetas(ahora time.Time) (int, error) {
+	vencidas, err := s.repo.Vencer(ahora)
+	if err != nil {
+		return 0, err
+	}
+	if len(vencidas) > 0 {
+		s.logger.Info("Recetas vencidas", zap.Ints("ids", vencidas))
+	}
+	return len(vencidas), nil
+}
@@ -1,43 +1,85 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"prestadores-api/internal/model"
 	"prestadores-api/internal/repository"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type ReintegroService interface {
-	GetReintegros(estado string, query string, page int, size int, sort string) (*model.PaginatedReintegrosResponse, error)
+	GetReintegros(estado string, especialidad string, query string, page int, size int, sort string) (*model.PaginatedReintegrosResponse, error)
 	GetReintegroByID(id int) (*model.ReintegroDetalle, error)
-	CreateReintegro(req model.CreateReintegroRequest) (*model.CreateReintegroResponse, error)
+	CreateReintegro(req model.CreateReintegroRequest, usuario model.UsuarioAutenticado) (*model.CreateReintegroResponse, error)
 	UpdateReintegro(id int, req model.UpdateReintegroRequest) error
-	CambiarEstadoReintegro(id int, req model.CambioEstadoRequest) (*model.CambioEstadoResponse, error)
+	CambiarEstadoReintegro(id int, req model.CambioEstadoRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoResponse, error)
 }
 
+// AutorizacionPreviaError indica que la prestación exige una autorización aprobada del
+// mismo afiliado y el reintegro no la trae o referencia una que no sirve
+type AutorizacionPreviaError struct {
+	Message string
+}
+
+func (e *AutorizacionPreviaError) Error() string {
+	return e.Message
+}
+
+// factorMontoMaximo es cuántas veces el valor del nomenclador se admite como monto presentado
+const factorMontoMaximo = 10
+
 type reintegroServiceImpl struct {
-	repo   repository.ReintegroRepository
-	logger *zap.Logger
+	repo           repository.ReintegroRepository
+	afiliados      repository.AfiliadoRepository
+	nomenclador    repository.NomencladorRepository
+	especialidades repository.EspecialidadRepository
+	autorizaciones repository.AutorizacionRepository
+	elegibilidad   ElegibilidadService
+	adjuntos       repository.AdjuntoRepository
+	cuentas        repository.CuentaCobroRepository
+	logger         *zap.Logger
 }
 
-func NewReintegroService(repo repository.ReintegroRepository, logger *zap.Logger) ReintegroService {
+func NewReintegroService(repo repository.ReintegroRepository, afiliados repository.AfiliadoRepository, nomenclador repository.NomencladorRepository, especialidades repository.EspecialidadRepository, autorizaciones repository.AutorizacionRepository, elegibilidad ElegibilidadService, adjuntos repository.AdjuntoRepository, cuentas repository.CuentaCobroRepository, logger *zap.Logger) ReintegroService {
 	return &reintegroServiceImpl{
-		repo:   repo,
-		logger: logger,
+		repo:           repo,
+		afiliados:      afiliados,
+		nomenclador:    nomenclador,
+		especialidades: especialidades,
+		autorizaciones: autorizaciones,
+		elegibilidad:   elegibilidad,
+		adjuntos:       adjuntos,
+		cuentas:        cuentas,
+		logger:         logger,
 	}
 }
 
-func (s *reintegroServiceImpl) GetReintegros(estado string, query string, page int, size int, sort string) (*model.PaginatedReintegrosResponse, error) {
+func (s *reintegroServiceImpl) GetReintegros(estado string, especialidad string, query string, page int, size int, sort string) (*model.PaginatedReintegrosResponse, error) {
 	s.logger.Info("Obteniendo reintegros",
 		zap.String("estado", estado),
+		zap.String("especialidad", especialidad),
 		zap.String("query", query),
 		zap.Int("page", page),
 		zap.Int("size", size),
 		zap.String("sort", sort),
 	)
 
-	items, total, err := s.repo.GetAll(estado, query, page, size, sort)
+	especialidadCodigo, err := validarFiltroEspecialidad(s.especialidades, especialidad)
+	if err != nil {
+		s.logger.Warn("Filtro de especialidad inválido", zap.String("especialidad", especialidad), zap.Error(err))
+		return nil, err
+	}
+
+	items, total, err := s.repo.GetAll(estado, especialidadCodigo, query, page, size, sort)
 	if err != nil {
+		if errors.Is(err, repository.ErrOrdenInvalido) {
+			s.logger.Warn("Parámetro sort inválido", zap.String("sort", sort), zap.Error(err))
+			return nil, &ServiceError{Message: err.Error()}
+		}
 		s.logger.Error("Error al obtener reintegros", zap.Error(err))
 		return nil, err
 	}
@@ -61,18 +103,134 @@ func (s *reintegroServiceImpl) GetReintegroByID(id int) (*model.ReintegroDetalle
 		return nil, err
 	}
 
-	return detalle, nil
+	// Copia para no tocar el detalle que guarda el repositorio en memoria
+	completo := *detalle
+	completo.Adjuntos, err = s.adjuntos.GetBySolicitud(model.TipoReintegro, id)
+	if err != nil {
+		s.logger.Error("Error al obtener adjuntos", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if detalle.CuentaCobroID != nil {
+		completo.CuentaCobro, err = s.cuentas.GetByID(*detalle.CuentaCobroID)
+		if err != nil {
+			s.logger.Error("Error al obtener cuenta de cobro", zap.Int("id", id), zap.Int("cuentaCobroId", *detalle.CuentaCobroID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return &completo, nil
 }
 
-func (s *reintegroServiceImpl) CreateReintegro(req model.CreateReintegroRequest) (*model.CreateReintegroResponse, error) {
+func (s *reintegroServiceImpl) CreateReintegro(req model.CreateReintegroRequest, usuario model.UsuarioAutenticado) (*model.CreateReintegroResponse, error) {
+	// El historial registra al prestador autenticado, nunca un dato del body
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+	req.Prestador = model.PrestadorBasico{ID: usuario.ID, CUIT: usuario.CUIT, Nombre: usuario.Nombre}
+
 	s.logger.Info("Creando reintegro",
 		zap.Int("afiliadoId", req.AfiliadoID),
-		zap.String("prestacion", req.Prestacion),
-		zap.String("metodo", req.Metodo),
-		zap.Float64("monto", req.Monto),
+		zap.String("prestacionCodigo", req.PrestacionCodigo),
+		zap.String("metodo", string(req.Metodo)),
+		zap.Any("cuentaCobroId", req.CuentaCobroID),
+		zap.Stringer("monto", req.Monto),
 	)
 
+	if err := validarEstadoInicial(req.EstadoInicial, model.EstadoRecibido); err != nil {
+		s.logger.Warn("Estado inicial inválido", zap.String("estadoInicial", string(req.EstadoInicial)))
+		return nil, err
+	}
+
+	if req.Moneda == "" {
+		req.Moneda = model.MonedaARS
+	}
+	if req.Moneda != model.MonedaARS {
+		s.logger.Warn("Moneda no admitida", zap.String("moneda", string(req.Moneda)))
+		return nil, &ServiceError{Message: fmt.Sprintf("Moneda no admitida: %s. Los reintegros son en %s", req.Moneda, model.MonedaARS)}
+	}
+
+	prestacion, err := resolverPrestacion(s.nomenclador, req.PrestacionCodigo)
+	if err != nil {
+		s.logger.Warn("Código de prestación inválido", zap.String("prestacionCodigo", req.PrestacionCodigo), zap.Error(err))
+		return nil, err
+	}
+
+	if err := validarMonto(req.Monto, prestacion, false); err != nil {
+		s.logger.Warn("Monto inválido", zap.Stringer("monto", req.Monto), zap.Error(err))
+		return nil, err
+	}
+
+	req.Factura, err = normalizarFactura(*req.Factura, req.Monto, time.Now().UTC())
+	if err != nil {
+		s.logger.Warn("Factura inválida", zap.Error(err))
+		return nil, err
+	}
+	usadoEn, err := s.repo.FacturaEnUso(*req.Factura)
+	if err != nil {
+		s.logger.Error("Error al verificar la factura", zap.Error(err))
+		return nil, err
+	}
+	if usadoEn != 0 {
+		s.logger.Warn("Factura ya presentada",
+			zap.String("comprobante", req.Factura.Comprobante()),
+			zap.String("cuitEmisor", req.Factura.CUITEmisor),
+			zap.Int("reintegroId", usadoEn),
+		)
+		return nil, &FacturaDuplicadaError{Comprobante: req.Factura.Comprobante(), ReintegroID: usadoEn}
+	}
+	req.PrestacionCodigo = prestacion.Codigo
+	req.Prestacion = prestacion.Descripcion
+	req.EspecialidadCodigo = prestacion.EspecialidadCodigo
+
+	// Los datos del afiliado se copian del padrón al momento de crear la solicitud
+	afiliado, err := resolverAfiliado(s.afiliados, req.AfiliadoID)
+	if err != nil {
+		s.logger.Warn("Afiliado inexistente", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	elegibilidad, err := verificarElegible(s.elegibilidad, *afiliado, prestacion.Especialidad)
+	if err != nil {
+		s.logger.Warn("Afiliado no elegible", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+	req.Afiliado = afiliado.Basico()
+
+	req.Metodo, err = resolverCuentaCobro(s.cuentas, req.Metodo, req.CuentaCobroID, afiliado.ID)
+	if err != nil {
+		s.logger.Warn("Método de reintegro inválido", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+
+	// Se guarda la especialidad tal como figura en el plan para que el tope anual sume siempre sobre la misma
+	cobertura := *elegibilidad.Cobertura
+	req.Especialidad = cobertura.Prestacion
+
+	if cobertura.RequiereAutorizacion || req.AutorizacionID != nil {
+		if err := s.validarAutorizacionPrevia(req.AutorizacionID, afiliado.ID, prestacion); err != nil {
+			s.logger.Warn("Autorización previa inválida", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	liquidacion, err := s.liquidar(afiliado.ID, cobertura, prestacion, req.Monto, time.Now().UTC().Year(), 0)
+	if err != nil {
+		s.logger.Error("Error al calcular monto reconocido", zap.Int("afiliadoId", req.AfiliadoID), zap.Error(err))
+		return nil, err
+	}
+	req.Liquidacion = *liquidacion
+
 	detalle, err := s.repo.Create(req)
+	var enUso *repository.FacturaEnUsoError
+	if errors.As(err, &enUso) {
+		// Otro alta simultáneo presentó la misma factura después del control anterior
+		s.logger.Warn("Factura ya presentada",
+			zap.String("comprobante", req.Factura.Comprobante()),
+			zap.String("cuitEmisor", req.Factura.CUITEmisor),
+			zap.Int("reintegroId", enUso.ReintegroID),
+		)
+		return nil, &FacturaDuplicadaError{Comprobante: req.Factura.Comprobante(), ReintegroID: enUso.ReintegroID}
+	}
 	if err != nil {
 		s.logger.Error("Error al crear reintegro", zap.Error(err))
 		return nil, err
@@ -91,19 +249,58 @@ func (s *reintegroServiceImpl) CreateReintegro(req model.CreateReintegroRequest)
 func (s *reintegroServiceImpl) UpdateReintegro(id int, req model.UpdateReintegroRequest) error {
 	s.logger.Info("Actualizando reintegro",
 		zap.Int("id", id),
-		zap.String("prestacion", req.Prestacion),
-		zap.String("metodo", req.Metodo),
-		zap.Float64("monto", req.Monto),
+		zap.String("prestacionCodigo", req.PrestacionCodigo),
+		zap.String("metodo", string(req.Metodo)),
+		zap.Any("cuentaCobroId", req.CuentaCobroID),
+		zap.Any("monto", req.Monto),
 	)
 
-	if err := s.repo.Update(id, req); err != nil {
+	// Monto, prestación y forma de pago solo se corrigen antes de la auditoría o con la solicitud observada
+	actual, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener reintegro", zap.Int("id", id), zap.Error(err))
+		return err
+	}
+	if err := validarEditable(actual.Estado, model.EstadoRecibido, model.EstadoObservado); err != nil {
+		s.logger.Warn("Reintegro no editable", zap.Int("id", id), zap.String("estado", string(actual.Estado)))
+		return err
+	}
+
+	if req.Metodo != "" || req.CuentaCobroID != nil {
+		if err := s.cambiarMetodo(actual, &req); err != nil {
+			s.logger.Warn("Método de reintegro inválido", zap.Int("id", id), zap.Error(err))
+			return err
+		}
+	}
+
+	// Un cambio de prestación o monto vuelve a pasar por la cobertura del plan
+	if req.PrestacionCodigo != "" || req.Monto != nil {
+		if err := s.recalcularCobertura(actual, &req); err != nil {
+			s.logger.Warn("No se pudo recalcular la cobertura", zap.Int("id", id), zap.Error(err))
+			return err
+		}
+	}
+
+	err = s.repo.Update(id, req)
+	if errors.Is(err, repository.ErrEstadoModificado) {
+		// El auditor lo tomó después de validar que era editable: se responde con el estado nuevo
+		s.logger.Warn("Reintegro modificado concurrentemente", zap.Int("id", id), zap.String("estadoEsperado", string(actual.Estado)))
+		if actual, err = s.repo.GetByID(id); err != nil {
+			return err
+		}
+		return solicitudNoEditable(actual.Estado, model.EstadoRecibido, model.EstadoObservado)
+	}
+	if err != nil {
 		s.logger.Error("Error al actualizar reintegro", zap.Int("id", id), zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func (s *reintegroServiceImpl) CambiarEstadoReintegro(id int, req model.CambioEstadoRequest) (*model.CambioEstadoResponse, error) {
+func (s *reintegroServiceImpl) CambiarEstadoReintegro(id int, req model.CambioEstadoRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoResponse, error) {
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+
 	s.logger.Info("Cambiando estado de reintegro",
 		zap.Int("id", id),
 		zap.String("nuevoEstado", string(req.NuevoEstado)),
@@ -119,9 +316,31 @@ func (s *reintegroServiceImpl) CambiarEstadoReintegro(id int, req model.CambioEs
 		return nil, ErrMotivoRequerido
 	}
 
-	// TODO: agregar validaciones adicionales del workflow si corresponde
+	actual, err := s.repo.GetByID(id)
+	if err != nil {
+		s.logger.Error("Error al obtener reintegro", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if err := validarTransicion(transicionesAutorizacion, actual.Estado, req.NuevoEstado, usuario.Rol); err != nil {
+		s.logger.Warn("Transición de estado no permitida",
+			zap.Int("id", id),
+			zap.String("estadoActual", string(actual.Estado)),
+			zap.String("nuevoEstado", string(req.NuevoEstado)),
+			zap.String("rol", string(usuario.Rol)),
+		)
+		return nil, err
+	}
 
-	detalle, err := s.repo.CambiarEstado(id, req)
+	detalle, err := s.repo.CambiarEstado(id, actual.Estado, req)
+	if errors.Is(err, repository.ErrEstadoModificado) {
+		// Otro usuario la cambió después de validar la transición: se responde con el estado nuevo
+		s.logger.Warn("Cambio de estado concurrente", zap.Int("id", id), zap.String("estadoEsperado", string(actual.Estado)))
+		if actual, err = s.repo.GetByID(id); err != nil {
+			return nil, err
+		}
+		return nil, transicionInvalida(transicionesAutorizacion, actual.Estado, req.NuevoEstado)
+	}
 	if err != nil {
 		s.logger.Error("Error al cambiar estado de reintegro", zap.Int("id", id), zap.Error(err))
 		return nil, err
@@ -135,3 +354,161 @@ func (s *reintegroServiceImpl) CambiarEstadoReintegro(id int, req model.CambioEs
 	}
 	return resp, nil
 }
+
+// recalcularCobertura vuelve a liquidar el reintegro actual con la prestación o el monto nuevos de req
+func (s *reintegroServiceImpl) recalcularCobertura(actual *model.ReintegroDetalle, req *model.UpdateReintegroRequest) error {
+	descripcion, especialidad := actual.Prestacion, actual.Especialidad
+	// Si el código actual salió del nomenclador no hay valor de referencia para el máximo
+	prestacion, _ := s.nomenclador.GetByCodigo(actual.PrestacionCodigo)
+	if req.PrestacionCodigo != "" {
+		nueva, err := resolverPrestacion(s.nomenclador, req.PrestacionCodigo)
+		if err != nil {
+			return err
+		}
+		prestacion = nueva
+		req.PrestacionCodigo = prestacion.Codigo
+		req.Prestacion = prestacion.Descripcion
+		req.EspecialidadCodigo = prestacion.EspecialidadCodigo
+		descripcion, especialidad = prestacion.Descripcion, prestacion.Especialidad
+	}
+	monto := actual.Monto
+	if req.Monto != nil {
+		monto = *req.Monto
+	}
+
+	// Al corregir se admite llevar el monto a cero
+	if err := validarMonto(monto, prestacion, true); err != nil {
+		return err
+	}
+	if err := validarMontoFacturado(monto, actual.Factura); err != nil {
+		return err
+	}
+
+	afiliado, err := resolverAfiliado(s.afiliados, actual.Afiliado.ID)
+	if err != nil {
+		return err
+	}
+
+	elegibilidad, err := verificarElegible(s.elegibilidad, *afiliado, especialidad)
+	if err != nil {
+		return err
+	}
+
+	cobertura := *elegibilidad.Cobertura
+	if req.PrestacionCodigo != "" {
+		req.Especialidad = cobertura.Prestacion
+	}
+
+	if cobertura.RequiereAutorizacion {
+		autorizada := prestacion
+		if autorizada == nil {
+			autorizada = &model.PrestacionNomenclador{Codigo: actual.PrestacionCodigo, Descripcion: descripcion}
+		}
+		if err := s.validarAutorizacionPrevia(actual.AutorizacionID, afiliado.ID, autorizada); err != nil {
+			return err
+		}
+	}
+
+	req.Liquidacion, err = s.liquidar(afiliado.ID, cobertura, prestacion, monto, actual.FechaCreacion.Year(), actual.ID)
+	return err
+}
+
+// cambiarMetodo deja en req el método y la cuenta con que queda el reintegro. Pasar a TRANSFERENCIA
+// sin indicar cuenta conserva la que ya tenía.
+func (s *reintegroServiceImpl) cambiarMetodo(actual *model.ReintegroDetalle, req *model.UpdateReintegroRequest) error {
+	metodo := model.MetodoReintegro(strings.ToUpper(strings.TrimSpace(string(req.Metodo))))
+	if metodo == "" {
+		metodo = actual.Metodo
+	}
+	if req.CuentaCobroID == nil && metodo == model.MetodoTransferencia {
+		req.CuentaCobroID = actual.CuentaCobroID
+	}
+
+	var err error
+	req.Metodo, err = resolverCuentaCobro(s.cuentas, metodo, req.CuentaCobroID, actual.Afiliado.ID)
+	return err
+}
+
+// validarAutorizacionPrevia exige que la autorización exista, sea del afiliado, esté APROBADA y que lo
+// aprobado sea la misma prestación del reintegro. Se compara con el código guardado en la aprobación.
+func (s *reintegroServiceImpl) validarAutorizacionPrevia(autorizacionID *int, afiliadoID int, prestacion *model.PrestacionNomenclador) error {
+	if autorizacionID == nil {
+		return &AutorizacionPreviaError{Message: fmt.Sprintf(
+			"%s requiere una autorización previa aprobada: enviar autorizacionId", prestacion.Descripcion)}
+	}
+
+	aut, err := s.autorizaciones.GetByID(*autorizacionID)
+	if err != nil {
+		s.logger.Warn("Autorización previa no encontrada", zap.Int("autorizacionId", *autorizacionID), zap.Error(err))
+		return &AutorizacionPreviaError{Message: fmt.Sprintf("La autorización %d no existe", *autorizacionID)}
+	}
+
+	if aut.Afiliado.ID != afiliadoID {
+		return &AutorizacionPreviaError{Message: fmt.Sprintf(
+			"La autorización %d no corresponde al afiliado %d", *autorizacionID, afiliadoID)}
+	}
+
+	if aut.Estado != model.EstadoAprobado {
+		return &AutorizacionPreviaError{Message: fmt.Sprintf(
+			"La autorización %d está en estado %s: debe estar %s", *autorizacionID, aut.Estado, model.EstadoAprobado)}
+	}
+
+	aprobado, ok := aut.ProcedimientoAprobado()
+	if !ok || aprobado != prestacion.Codigo {
+		return &AutorizacionPreviaError{Message: fmt.Sprintf(
+			"La autorización %d no aprobó la prestación %s (%s)", *autorizacionID, prestacion.Codigo, prestacion.Descripcion)}
+	}
+
+	return nil
+}
+
+// liquidar calcula el monto reconocido: lo presentado hasta el valor del nomenclador, por el porcentaje de
+// cobertura, menos el coseguro y limitado a lo que queda del tope anual de la especialidad en el año
+func (s *reintegroServiceImpl) liquidar(afiliadoID int, cobertura model.CoberturaPrestacion, prestacion *model.PrestacionNomenclador, monto model.Monto, anio int, excluirID int) (*model.LiquidacionReintegro, error) {
+	liq := &model.LiquidacionReintegro{
+		Fecha:               time.Now().UTC(),
+		MontoPresentado:     monto,
+		MontoReconocible:    monto,
+		PorcentajeCobertura: cobertura.PorcentajeCobertura,
+		TopeAnual:           cobertura.TopeAnual,
+	}
+	if prestacion != nil && prestacion.ValorUnitario > 0 {
+		liq.ValorReferencia = model.MontoDesdePesos(prestacion.ValorUnitario)
+		liq.MontoReconocible = min(monto, liq.ValorReferencia)
+	}
+
+	cubierto := liq.MontoReconocible.Porcentaje(cobertura.PorcentajeCobertura)
+	liq.Coseguro = min(cobertura.Copago, cubierto)
+	liq.MontoReconocido = cubierto - liq.Coseguro
+
+	if liq.TopeAnual > 0 {
+		consumido, err := s.repo.MontoReconocidoAnual(afiliadoID, cobertura.Prestacion, anio, excluirID)
+		if err != nil {
+			return nil, err
+		}
+		liq.ConsumidoAnual = consumido
+		liq.TopeAplicado = max(liq.MontoReconocido-max(liq.TopeAnual-consumido, 0), 0)
+		liq.MontoReconocido -= liq.TopeAplicado
+	}
+
+	return liq, nil
+}
+
+// validarMonto exige un monto positivo (o cero al corregir) que no supere factorMontoMaximo veces el valor
+// de la prestación en el nomenclador, para frenar errores de tipeo. Sin valor de referencia no hay máximo.
+func validarMonto(monto model.Monto, prestacion *model.PrestacionNomenclador, permitirCero bool) error {
+	if monto < 0 {
+		return &ServiceError{Message: "El monto no puede ser negativo"}
+	}
+	if monto == 0 && !permitirCero {
+		return &ServiceError{Message: "El monto debe ser mayor a 0"}
+	}
+	if prestacion != nil && prestacion.ValorUnitario > 0 {
+		maximo := model.MontoDesdePesos(prestacion.ValorUnitario) * factorMontoMaximo
+		if monto > maximo {
+			return &ServiceError{Message: fmt.Sprintf("El monto %s supera el máximo de %s para %s (%d veces el valor del nomenclador)",
+				monto, maximo, prestacion.Descripcion, factorMontoMaximo)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"testing"
+)
+
+// consumoAnualFijo responde siempre el mismo consumo anual y registra con qué se lo consultó
+type consumoAnualFijo struct {
+	repository.ReintegroRepository
+	consumido model.Monto
+	consultas int
+	excluido  int
+}
+
+func (r *consumoAnualFijo) MontoReconocidoAnual(afiliadoID int, especialidad string, anio int, excluirID int) (model.Monto, error) {
+	r.consultas++
+	r.excluido = excluirID
+	return r.consumido, nil
+}
+
+func TestLiquidar(t *testing.T) {
+	kinesiologia := &model.PrestacionNomenclador{Codigo: "250101", Especialidad: "Kinesiología", ValorUnitario: 6000.5}
+
+	casos := []struct {
+		nombre     string
+		cobertura  model.CoberturaPrestacion
+		prestacion *model.PrestacionNomenclador
+		monto      model.Monto
+		consumido  model.Monto
+		want       model.LiquidacionReintegro
+	}{
+		{
+			nombre:    "sin tope ni copago",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 80},
+			monto:     10000_00,
+			want:      model.LiquidacionReintegro{MontoReconocible: 10000_00, MontoReconocido: 8000_00},
+		},
+		{
+			nombre:     "lo presentado se limita al valor del nomenclador",
+			cobertura:  model.CoberturaPrestacion{PorcentajeCobertura: 100, Copago: 500_00},
+			prestacion: kinesiologia,
+			monto:      10000_00,
+			want: model.LiquidacionReintegro{ValorReferencia: 6000_50, MontoReconocible: 6000_50, Coseguro: 500_00,
+				MontoReconocido: 5500_50},
+		},
+		{
+			nombre:    "el copago se descuenta de lo cubierto",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 70, Copago: 2000_00},
+			monto:     10000_00,
+			want:      model.LiquidacionReintegro{MontoReconocible: 10000_00, Coseguro: 2000_00, MontoReconocido: 5000_00},
+		},
+		{
+			nombre:    "copago mayor que lo cubierto",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 50, Copago: 2000_00},
+			monto:     1000_00,
+			want:      model.LiquidacionReintegro{MontoReconocible: 1000_00, Coseguro: 500_00, MontoReconocido: 0},
+		},
+		{
+			nombre:    "tope sin alcanzar",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 100, TopeAnual: 150000_00},
+			monto:     10000_00,
+			consumido: 100000_00,
+			want: model.LiquidacionReintegro{MontoReconocible: 10000_00, TopeAnual: 150000_00, ConsumidoAnual: 100000_00,
+				MontoReconocido: 10000_00},
+		},
+		{
+			nombre:    "tope parcialmente consumido",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 100, TopeAnual: 150000_00},
+			monto:     10000_00,
+			consumido: 145000_00,
+			want: model.LiquidacionReintegro{MontoReconocible: 10000_00, TopeAnual: 150000_00, ConsumidoAnual: 145000_00,
+				TopeAplicado: 5000_00, MontoReconocido: 5000_00},
+		},
+		{
+			nombre:    "tope agotado",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 70, TopeAnual: 150000_00, Copago: 2000_00},
+			monto:     10000_00,
+			consumido: 150000_00,
+			want: model.LiquidacionReintegro{MontoReconocible: 10000_00, Coseguro: 2000_00, TopeAnual: 150000_00,
+				ConsumidoAnual: 150000_00, TopeAplicado: 5000_00, MontoReconocido: 0},
+		},
+		{
+			// El plan bajó el tope después de reconocer más de lo que ahora permite
+			nombre:    "consumo por encima del tope",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 100, TopeAnual: 100000_00},
+			monto:     10000_00,
+			consumido: 120000_00,
+			want: model.LiquidacionReintegro{MontoReconocible: 10000_00, TopeAnual: 100000_00, ConsumidoAnual: 120000_00,
+				TopeAplicado: 10000_00, MontoReconocido: 0},
+		},
+		{
+			nombre:    "copago mayor que lo cubierto con tope",
+			cobertura: model.CoberturaPrestacion{PorcentajeCobertura: 50, TopeAnual: 150000_00, Copago: 2000_00},
+			monto:     1000_00,
+			consumido: 149000_00,
+			want: model.LiquidacionReintegro{MontoReconocible: 1000_00, Coseguro: 500_00, TopeAnual: 150000_00,
+				ConsumidoAnual: 149000_00, MontoReconocido: 0},
+		},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nombre, func(t *testing.T) {
+			repo := &consumoAnualFijo{consumido: tc.consumido}
+			s := &reintegroServiceImpl{repo: repo}
+
+			tc.cobertura.Prestacion = "Kinesiología"
+			liq, err := s.liquidar(45, tc.cobertura, tc.prestacion, tc.monto, 2025, 8801)
+			if err != nil {
+				t.Fatalf("liquidar: %v", err)
+			}
+
+			want := tc.want
+			want.Fecha = liq.Fecha
+			want.MontoPresentado = tc.monto
+			want.PorcentajeCobertura = tc.cobertura.PorcentajeCobertura
+			if *liq != want {
+				t.Errorf("liquidar =\n  %+v\nse esperaba\n  %+v", *liq, want)
+			}
+
+			// Sin tope no hace falta consultar lo consumido; con tope se excluye el reintegro que se recalcula
+			switch {
+			case tc.cobertura.TopeAnual == 0 && repo.consultas != 0:
+				t.Errorf("sin tope anual no debería consultar el consumo (%d consultas)", repo.consultas)
+			case tc.cobertura.TopeAnual > 0 && (repo.consultas != 1 || repo.excluido != 8801):
+				t.Errorf("consultas = %d excluyendo %d, se esperaba 1 excluyendo 8801", repo.consultas, repo.excluido)
+			}
+		})
+	}
+}
@@ -11,21 +11,23 @@ import (
 type SituacionService interface {
 	// scope: "" (titular) | "grupo"
 	GetSituaciones(afiliadoID int, scope string) (interface{}, error)
-	CreateSituacion(req model.CreateSituacionRequest) (*model.CreateSituacionResponse, error)
+	CreateSituacion(req model.CreateSituacionRequest, usuario model.UsuarioAutenticado) (*model.CreateSituacionResponse, error)
 	PatchSituacion(situacionID int, req model.PatchSituacionRequest) error
-	CambiarEstadoSituacion(situacionID int, req model.CambioEstadoSituacionRequest) (*model.CambioEstadoSituacionResponse, error)
+	CambiarEstadoSituacion(situacionID int, req model.CambioEstadoSituacionRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoSituacionResponse, error)
 	DeleteSituacion(situacionID int) error
 }
 
 type situacionServiceImpl struct {
-	repo   repository.SituacionRepository
-	logger *zap.Logger
+	repo      repository.SituacionRepository
+	afiliados repository.AfiliadoRepository
+	logger    *zap.Logger
 }
 
-func NewSituacionService(repo repository.SituacionRepository, logger *zap.Logger) SituacionService {
+func NewSituacionService(repo repository.SituacionRepository, afiliados repository.AfiliadoRepository, logger *zap.Logger) SituacionService {
 	return &situacionServiceImpl{
-		repo:   repo,
-		logger: logger,
+		repo:      repo,
+		afiliados: afiliados,
+		logger:    logger,
 	}
 }
 
@@ -38,8 +40,13 @@ func (s *situacionServiceImpl) GetSituaciones(afiliadoID int, scope string) (int
 		zap.String("scope", scope),
 	)
 
+	if _, err := s.afiliados.GetByID(afiliadoID); err != nil {
+		s.logger.Warn("Afiliado no encontrado", zap.Int("afiliadoId", afiliadoID), zap.Error(err))
+		return nil, err
+	}
+
 	if scope == "grupo" {
-		integrantes, err := s.repo.GetByAfiliadoGrupo(afiliadoID)
+		integrantes, err := s.situacionesGrupo(afiliadoID)
 		if err != nil {
 			s.logger.Error("Error al obtener situaciones de grupo", zap.Error(err))
 			return nil, err
@@ -63,7 +70,11 @@ func (s *situacionServiceImpl) GetSituaciones(afiliadoID int, scope string) (int
 	return resp, nil
 }
 
-func (s *situacionServiceImpl) CreateSituacion(req model.CreateSituacionRequest) (*model.CreateSituacionResponse, error) {
+func (s *situacionServiceImpl) CreateSituacion(req model.CreateSituacionRequest, usuario model.UsuarioAutenticado) (*model.CreateSituacionResponse, error) {
+	// El historial registra al prestador autenticado, nunca un dato del body
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+
 	s.logger.Info("Creando situación terapéutica",
 		zap.Int("afiliadoId", req.AfiliadoID),
 		zap.Any("miembroId", req.MiembroID),
@@ -77,6 +88,15 @@ func (s *situacionServiceImpl) CreateSituacion(req model.CreateSituacionRequest)
 		return nil, fmt.Errorf("request inválido: afiliadoId, descripcion y fechaInicio son obligatorios")
 	}
 
+	if err := s.validarAfiliadoSituacion(req.AfiliadoID, req.MiembroID); err != nil {
+		s.logger.Warn("Afiliado inválido para la situación",
+			zap.Int("afiliadoId", req.AfiliadoID),
+			zap.Any("miembroId", req.MiembroID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
 	detalle, err := s.repo.Create(req)
 	if err != nil {
 		s.logger.Error("Error al crear situación", zap.Error(err))
@@ -110,7 +130,10 @@ func (s *situacionServiceImpl) PatchSituacion(situacionID int, req model.PatchSi
 	return nil
 }
 
-func (s *situacionServiceImpl) CambiarEstadoSituacion(situacionID int, req model.CambioEstadoSituacionRequest) (*model.CambioEstadoSituacionResponse, error) {
+func (s *situacionServiceImpl) CambiarEstadoSituacion(situacionID int, req model.CambioEstadoSituacionRequest, usuario model.UsuarioAutenticado) (*model.CambioEstadoSituacionResponse, error) {
+	req.Usuario = usuario.Username
+	req.Rol = usuario.Rol
+
 	s.logger.Info("Cambiando estado de situación",
 		zap.Int("situacionId", situacionID),
 		zap.String("nuevoEstado", string(req.Estado)),
@@ -120,9 +143,6 @@ func (s *situacionServiceImpl) CambiarEstadoSituacion(situacionID int, req model
 	if situacionID <= 0 {
 		return nil, fmt.Errorf("situacionId inválido")
 	}
-	if req.Usuario == "" {
-		return nil, fmt.Errorf("usuario es obligatorio")
-	}
 
 	if req.Estado == model.EstadoSituacionBaja && req.Motivo == "" {
 		return nil, fmt.Errorf("el motivo es obligatorio para BAJA")
@@ -153,3 +173,78 @@ func (s *situacionServiceImpl) DeleteSituacion(situacionID int) error {
 	}
 	return nil
 }
+
+// validarAfiliadoSituacion exige que afiliadoId sea un titular del padrón y, si viene
+// miembroId, que sea un integrante de su grupo familiar
+func (s *situacionServiceImpl) validarAfiliadoSituacion(afiliadoID int, miembroID *int) error {
+	afiliado, err := resolverAfiliado(s.afiliados, afiliadoID)
+	if err != nil {
+		return err
+	}
+
+	if !afiliado.EsTitular() {
+		return &ServiceError{Message: fmt.Sprintf(
+			"El afiliado %d no es titular: la situación se carga sobre el titular %d con miembroId %d",
+			afiliadoID, *afiliado.TitularID, afiliadoID,
+		)}
+	}
+
+	if miembroID == nil {
+		return nil
+	}
+
+	miembro, err := resolverAfiliado(s.afiliados, *miembroID)
+	if err != nil {
+		return err
+	}
+
+	if miembro.TitularID == nil || *miembro.TitularID != afiliadoID {
+		return &ServiceError{Message: fmt.Sprintf("El afiliado %d no integra el grupo familiar del titular %d", *miembroID, afiliadoID)}
+	}
+
+	return nil
+}
+
+// situacionesGrupo arma la vista por integrante a partir del grupo familiar del padrón
+func (s *situacionServiceImpl) situacionesGrupo(afiliadoID int) ([]model.IntegranteSituaciones, error) {
+	titular, err := s.afiliados.GetByID(afiliadoID)
+	if err != nil {
+		return nil, err
+	}
+
+	miembros, err := s.afiliados.GetIntegrantes(afiliadoID)
+	if err != nil {
+		return nil, err
+	}
+
+	situaciones, err := s.repo.GetByAfiliado(afiliadoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// nil (titular) se agrupa bajo el ID del titular
+	porMiembro := make(map[int][]model.Situacion)
+	for _, sit := range situaciones {
+		miembroID := afiliadoID
+		if sit.MiembroID != nil {
+			miembroID = *sit.MiembroID
+		}
+		porMiembro[miembroID] = append(porMiembro[miembroID], sit)
+	}
+
+	integrantes := make([]model.IntegranteSituaciones, 0, len(miembros)+1)
+	for _, a := range append([]model.Afiliado{*titular}, miembros...) {
+		items := porMiembro[a.ID]
+		if items == nil {
+			items = []model.Situacion{}
+		}
+		integrantes = append(integrantes, model.IntegranteSituaciones{
+			MiembroID:   a.ID,
+			Nombre:      a.Nombre + " " + a.Apellido,
+			Parentesco:  a.Parentesco,
+			Situaciones: items,
+		})
+	}
+
+	return integrantes, nil
+}
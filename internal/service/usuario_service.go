@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"prestadores-api/internal/auth"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"prestadores-api/internal/validacion"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Largo mínimo de contraseña para usuarios nuevos
+const minLargoPassword = 8
+
+type UsuarioService interface {
+	RegistrarUsuario(req model.RegistrarUsuarioRequest) (*model.Usuario, error)
+}
+
+type usuarioServiceImpl struct {
+	repo   repository.UsuarioRepository
+	logger *zap.Logger
+}
+
+func NewUsuarioService(repo repository.UsuarioRepository, logger *zap.Logger) UsuarioService {
+	return &usuarioServiceImpl{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+var ErrUsuarioExistente = &ServiceError{Message: "Ya existe un usuario con ese CUIT o username"}
+
+func (s *usuarioServiceImpl) RegistrarUsuario(req model.RegistrarUsuarioRequest) (*model.Usuario, error) {
+	s.logger.Info("Registrando usuario",
+		zap.String("cuit", req.CUIT),
+		zap.String("username", req.Username),
+		zap.String("rol", string(req.Rol)),
+	)
+
+	cuit, err := validacion.NormalizarCUIT(req.CUIT)
+	if err != nil {
+		s.logger.Warn("CUIT inválido al registrar usuario", zap.String("cuit", req.CUIT), zap.Error(err))
+		return nil, &ServiceError{Message: err.Error()}
+	}
+
+	rol := req.Rol
+	if rol == "" {
+		rol = model.RolPrestador
+	}
+	if rol != model.RolPrestador && rol != model.RolAuditor && rol != model.RolAdmin && rol != model.RolFarmacia {
+		return nil, &ServiceError{Message: "Rol inválido: " + string(rol) + ". Roles válidos: PRESTADOR, AUDITOR, ADMIN, FARMACIA"}
+	}
+
+	if strings.TrimSpace(req.Nombre) == "" {
+		return nil, &ServiceError{Message: "El nombre es obligatorio"}
+	}
+	if len(req.Password) < minLargoPassword {
+		return nil, &ServiceError{Message: "La contraseña debe tener al menos 8 caracteres"}
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		s.logger.Error("Error al generar hash de contraseña", zap.Error(err))
+		return nil, err
+	}
+
+	usuario, err := s.repo.Create(model.Usuario{
+		CUIT:         cuit,
+		Username:     strings.TrimSpace(req.Username),
+		Nombre:       strings.TrimSpace(req.Nombre),
+		Rol:          rol,
+		PasswordHash: hash,
+		Activo:       true,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrUsuarioExistente) {
+			s.logger.Warn("Usuario duplicado", zap.String("cuit", cuit), zap.String("username", req.Username))
+			return nil, ErrUsuarioExistente
+		}
+		s.logger.Error("Error al registrar usuario", zap.Error(err))
+		return nil, err
+	}
+
+	return usuario, nil
+}
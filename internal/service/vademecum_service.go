@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"prestadores-api/internal/model"
+	"prestadores-api/internal/repository"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ErrMedicamentoNoEncontrado indica que el código no figura en el vademécum
+var ErrMedicamentoNoEncontrado = repository.ErrMedicamentoNoEncontrado
+
+// Límite de resultados de la búsqueda en el vademécum
+const (
+	limiteVademecumDefault = 20
+	limiteVademecumMaximo  = 100
+)
+
+// MedicamentoInexistenteError indica que una receta referencia un código que no está en el vademécum
+type MedicamentoInexistenteError struct {
+	Codigo string
+}
+
+func (e *MedicamentoInexistenteError) Error() string {
+	return fmt.Sprintf("El código %q no existe en el vademécum", e.Codigo)
+}
+
+// resolverMedicamento busca el código en el vademécum, devolviendo MedicamentoInexistenteError si no está
+func resolverMedicamento(repo repository.VademecumRepository, codigo string) (*model.Medicamento, error) {
+	medicamento, err := repo.GetByCodigo(codigo)
+	if errors.Is(err, repository.ErrMedicamentoNoEncontrado) {
+		return nil, &MedicamentoInexistenteError{Codigo: codigo}
+	}
+	return medicamento, err
+}
+
+type VademecumService interface {
+	BuscarMedicamentos(query string, condicionVenta string, limit int) (*model.VademecumResponse, error)
+	GetMedicamento(codigo string) (*model.Medicamento, error)
+	// ImportarCSV valida el archivo completo y recién entonces reemplaza el vademécum
+	ImportarCSV(r io.Reader) (*model.ImportacionVademecumResponse, error)
+}
+
+type vademecumServiceImpl struct {
+	repo   repository.VademecumRepository
+	logger *zap.Logger
+}
+
+func NewVademecumService(repo repository.VademecumRepository, logger *zap.Logger) VademecumService {
+	return &vademecumServiceImpl{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *vademecumServiceImpl) BuscarMedicamentos(query string, condicionVenta string, limit int) (*model.VademecumResponse, error) {
+	if limit <= 0 {
+		limit = limiteVademecumDefault
+	}
+	if limit > limiteVademecumMaximo {
+		limit = limiteVademecumMaximo
+	}
+
+	s.logger.Info("Buscando medicamentos en el vademécum",
+		zap.String("query", query),
+		zap.String("condicionVenta", condicionVenta),
+		zap.Int("limit", limit),
+	)
+
+	condicion := model.CondicionVenta(strings.ToUpper(condicionVenta))
+	switch condicion {
+	case "", model.CondicionVentaLibre, model.CondicionVentaBajoReceta, model.CondicionVentaBajoRecetaArchivada:
+	default:
+		s.logger.Warn("Condición de venta inválida", zap.String("condicionVenta", condicionVenta))
+		return nil, &ServiceError{Message: fmt.Sprintf("Condición de venta desconocida: %s", condicionVenta)}
+	}
+
+	items, total, err := s.repo.Buscar(query, condicion, limit)
+	if err != nil {
+		s.logger.Error("Error al buscar en el vademécum", zap.Error(err))
+		return nil, err
+	}
+
+	return &model.VademecumResponse{Total: total, Items: items}, nil
+}
+
+func (s *vademecumServiceImpl) GetMedicamento(codigo string) (*model.Medicamento, error) {
+	s.logger.Info("Obteniendo medicamento del vademécum", zap.String("codigo", codigo))
+
+	medicamento, err := s.repo.GetByCodigo(codigo)
+	if err != nil {
+		s.logger.Warn("Medicamento no encontrado en el vademécum", zap.String("codigo", codigo), zap.Error(err))
+		return nil, err
+	}
+	return medicamento, nil
+}
+
+func (s *vademecumServiceImpl) ImportarCSV(r io.Reader) (*model.ImportacionVademecumResponse, error) {
+	s.logger.Info("Importando vademécum desde CSV")
+
+	medicamentos, err := repository.CargarVademecum(r)
+	if err != nil {
+		s.logger.Warn("CSV de vademécum inválido", zap.Error(err))
+		return nil, &ServiceError{Message: err.Error()}
+	}
+
+	if err := s.repo.Reemplazar(medicamentos); err != nil {
+		s.logger.Error("Error al reemplazar el vademécum", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Vademécum importado", zap.Int("medicamentos", len(medicamentos)))
+	return &model.ImportacionVademecumResponse{Medicamentos: len(medicamentos)}, nil
+}
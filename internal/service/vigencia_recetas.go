@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"prestadores-api/internal/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// VigenciaRecetas define cuántos días se puede dispensar una receta desde que se aprueba
+type VigenciaRecetas struct {
+	Dias             int
+	DiasPorCondicion map[model.CondicionVenta]int // por condición de venta del vademécum; pisa a Dias
+
+	// IntervaloVencimiento es cada cuánto corre el job que pasa a VENCIDA las recetas
+	IntervaloVencimiento time.Duration
+}
+
+// VigenciaRecetasFromEnv lee RECETA_VIGENCIA_DIAS, RECETA_VIGENCIA_POR_CONDICION
+// (p. ej. "BAJO_RECETA_ARCHIVADA=10,VENTA_LIBRE=90") y RECETA_VENCIMIENTO_INTERVALO
+func VigenciaRecetasFromEnv() (VigenciaRecetas, error) {
+	cfg := VigenciaRecetas{
+		Dias:                 30,
+		DiasPorCondicion:     map[model.CondicionVenta]int{},
+		IntervaloVencimiento: time.Hour,
+	}
+
+	if v := os.Getenv("RECETA_VIGENCIA_DIAS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return cfg, fmt.Errorf("RECETA_VIGENCIA_DIAS inválido: %q", v)
+		}
+		cfg.Dias = n
+	}
+
+	if v := os.Getenv("RECETA_VIGENCIA_POR_CONDICION"); v != "" {
+		for _, par := range strings.Split(v, ",") {
+			condicion, dias, ok := strings.Cut(strings.TrimSpace(par), "=")
+			n, err := strconv.Atoi(strings.TrimSpace(dias))
+			if !ok || err != nil || n <= 0 {
+				return cfg, fmt.Errorf("RECETA_VIGENCIA_POR_CONDICION inválido: %q", par)
+			}
+			cfg.DiasPorCondicion[model.CondicionVenta(strings.ToUpper(strings.TrimSpace(condicion)))] = n
+		}
+	}
+
+	if v := os.Getenv("RECETA_VENCIMIENTO_INTERVALO"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("RECETA_VENCIMIENTO_INTERVALO inválido: %q", v)
+		}
+		cfg.IntervaloVencimiento = d
+	}
+
+	return cfg, nil
+}
+
+// DiasPara devuelve la vigencia de un medicamento según su condición de venta
+func (v VigenciaRecetas) DiasPara(condicion model.CondicionVenta) int {
+	if dias, ok := v.DiasPorCondicion[condicion]; ok {
+		return dias
+	}
+	return v.Dias
+}
+
+// IniciarVencimientoRecetas corre VencerRecetas al arrancar y después cada intervalo, hasta que se cancele ctx
+func IniciarVencimientoRecetas(ctx context.Context, s RecetaService, intervalo time.Duration, logger *zap.Logger) {
+	vencer := func() {
+		if _, err := s.VencerRecetas(time.Now().UTC()); err != nil {
+			logger.Error("Error en el vencimiento de recetas", zap.Error(err))
+		}
+	}
+
+	go func() {
+		vencer()
+		ticker := time.NewTicker(intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				vencer()
+			}
+		}
+	}()
+}
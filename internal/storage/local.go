@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+)
+
+type localStorage struct {
+	root *os.Root
+}
+
+// NewLocalStorage guarda los archivos bajo dir, que se crea si no existe. Las claves no pueden
+// salir de ese directorio.
+func NewLocalStorage(dir string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio de adjuntos %s: %w", dir, err)
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir el directorio de adjuntos %s: %w", dir, err)
+	}
+	return &localStorage{root: root}, nil
+}
+
+func (l *localStorage) Guardar(clave string, contenido io.Reader, tamanio int64, contentType string) error {
+	if err := l.root.MkdirAll(path.Dir(clave), 0o750); err != nil {
+		return fmt.Errorf("error al crear el directorio del adjunto: %w", err)
+	}
+
+	// Se escribe a un temporal y se renombra para que nunca quede un archivo a medias con la clave final
+	sufijo := make([]byte, 8)
+	rand.Read(sufijo)
+	temporal := clave + ".tmp-" + hex.EncodeToString(sufijo)
+
+	f, err := l.root.OpenFile(temporal, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("error al crear el adjunto: %w", err)
+	}
+	escritos, err := io.Copy(f, contenido)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && escritos != tamanio {
+		err = fmt.Errorf("se escribieron %d bytes de %d", escritos, tamanio)
+	}
+	if err != nil {
+		l.root.Remove(temporal)
+		return fmt.Errorf("error al guardar el adjunto: %w", err)
+	}
+
+	if err := l.root.Rename(temporal, clave); err != nil {
+		l.root.Remove(temporal)
+		return fmt.Errorf("error al guardar el adjunto: %w", err)
+	}
+	return nil
+}
+
+func (l *localStorage) Abrir(clave string) (io.ReadCloser, error) {
+	f, err := l.root.Open(clave)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoEncontrado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir el adjunto: %w", err)
+	}
+	return f, nil
+}
+
+func (l *localStorage) Eliminar(clave string) error {
+	if err := l.root.Remove(clave); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error al eliminar el adjunto: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Config apunta a un bucket de S3 o de un servicio compatible (MinIO, R2, etc.)
+type S3Config struct {
+	Endpoint  string // p. ej. "https://s3.us-east-1.amazonaws.com" o "http://localhost:9000"
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// s3Storage habla la API REST de S3 con direccionamiento path-style (endpoint/bucket/clave),
+// que aceptan AWS y los compatibles, firmando con Signature V4.
+type s3Storage struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// el cuerpo no se firma: se guarda tal cual lo lee el service, que ya calculó su SHA-256
+const payloadSinFirmar = "UNSIGNED-PAYLOAD"
+
+// NewS3Storage valida la configuración; no verifica que el bucket exista
+func NewS3Storage(cfg S3Config) (Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY y S3_SECRET_KEY son obligatorios para el driver %q", DriverS3)
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("S3_ENDPOINT inválido: %q", cfg.Endpoint)
+	}
+	return &s3Storage{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (s *s3Storage) Guardar(clave string, contenido io.Reader, tamanio int64, contentType string) error {
+	req, err := s.nuevoRequest(http.MethodPut, clave, contenido)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = tamanio
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al subir el adjunto a S3: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorS3("subir", resp)
+	}
+	return nil
+}
+
+func (s *s3Storage) Abrir(clave string) (io.ReadCloser, error) {
+	req, err := s.nuevoRequest(http.MethodGet, clave, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al descargar el adjunto de S3: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNoEncontrado
+	default:
+		defer resp.Body.Close()
+		return nil, errorS3("descargar", resp)
+	}
+}
+
+func (s *s3Storage) Eliminar(clave string) error {
+	req, err := s.nuevoRequest(http.MethodDelete, clave, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al eliminar el adjunto de S3: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return errorS3("eliminar", resp)
+	}
+	return nil
+}
+
+func (s *s3Storage) nuevoRequest(metodo string, clave string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.cfg.Bucket + "/" + clave
+	u.RawPath = u.Path
+	if escapado := uriEncode(u.Path, false); escapado != u.Path {
+		u.RawPath = escapado
+	}
+
+	req, err := http.NewRequest(metodo, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("error al armar el request a S3: %w", err)
+	}
+	req.Header.Set("x-amz-content-sha256", payloadSinFirmar)
+	s.firmar(req, time.Now().UTC())
+	return req, nil
+}
+
+// firmar agrega el header Authorization de Signature V4. Se firman host y los headers x-amz-*.
+func (s *s3Storage) firmar(req *http.Request, ahora time.Time) {
+	fecha := ahora.Format("20060102T150405Z")
+	dia := fecha[:8]
+	req.Header.Set("x-amz-date", fecha)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for nombre, valores := range req.Header {
+		if n := strings.ToLower(nombre); strings.HasPrefix(n, "x-amz-") {
+			headers[n] = strings.TrimSpace(strings.Join(valores, ","))
+		}
+	}
+	nombres := make([]string, 0, len(headers))
+	for n := range headers {
+		nombres = append(nombres, n)
+	}
+	slices.Sort(nombres)
+
+	var canonicos strings.Builder
+	for _, n := range nombres {
+		canonicos.WriteString(n + ":" + headers[n] + "\n")
+	}
+	firmados := strings.Join(nombres, ";")
+
+	requestCanonico := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		queryCanonica(req.URL.Query()),
+		canonicos.String(),
+		firmados,
+		req.Header.Get("x-amz-content-sha256"),
+	}, "\n")
+
+	alcance := dia + "/" + s.cfg.Region + "/s3/aws4_request"
+	hashRequest := sha256.Sum256([]byte(requestCanonico))
+	aFirmar := "AWS4-HMAC-SHA256\n" + fecha + "\n" + alcance + "\n" + hex.EncodeToString(hashRequest[:])
+
+	clave := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), dia)
+	clave = hmacSHA256(clave, s.cfg.Region)
+	clave = hmacSHA256(clave, "s3")
+	clave = hmacSHA256(clave, "aws4_request")
+	firma := hex.EncodeToString(hmacSHA256(clave, aFirmar))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, alcance, firmados, firma))
+}
+
+func hmacSHA256(clave []byte, dato string) []byte {
+	h := hmac.New(sha256.New, clave)
+	h.Write([]byte(dato))
+	return h.Sum(nil)
+}
+
+// uriEncode codifica como pide SigV4: todo salvo A-Z, a-z, 0-9, "-", "_", "." y "~"; la "/" solo
+// si se indica (en los parámetros de la query sí, en el path no)
+func uriEncode(s string, codificarBarra bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !codificarBarra:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func queryCanonica(valores url.Values) string {
+	var pares []string
+	for k, vs := range valores {
+		for _, v := range vs {
+			pares = append(pares, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	slices.Sort(pares)
+	return strings.Join(pares, "&")
+}
+
+func errorS3(operacion string, resp *http.Response) error {
+	cuerpo, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("error al %s el adjunto en S3: %s %s", operacion, resp.Status, strings.TrimSpace(string(cuerpo)))
+}
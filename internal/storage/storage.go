@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Storage guarda el contenido de los archivos adjuntos. Las claves son rutas relativas separadas
+// por "/" (p. ej. "reintegro/8801/<sha256>") y las arma quien llama.
+type Storage interface {
+	Guardar(clave string, contenido io.Reader, tamanio int64, contentType string) error
+	// Abrir devuelve ErrNoEncontrado si la clave no existe
+	Abrir(clave string) (io.ReadCloser, error)
+	// Eliminar no falla si la clave no existe
+	Eliminar(clave string) error
+}
+
+var ErrNoEncontrado = errors.New("archivo inexistente en el almacenamiento")
+
+// Drivers de almacenamiento soportados
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Config representa la configuración del almacenamiento de adjuntos
+type Config struct {
+	Driver string
+	Dir    string // driver local
+	S3     S3Config
+}
+
+// ConfigFromEnv lee STORAGE_DRIVER (local por defecto), STORAGE_DIR y, para s3, S3_ENDPOINT,
+// S3_REGION, S3_BUCKET, S3_ACCESS_KEY y S3_SECRET_KEY
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Driver: strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_DRIVER"))),
+		Dir:    os.Getenv("STORAGE_DIR"),
+		S3: S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		},
+	}
+
+	if cfg.Driver == "" {
+		cfg.Driver = DriverLocal
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = "adjuntos"
+	}
+	if cfg.S3.Region == "" {
+		cfg.S3.Region = "us-east-1"
+	}
+
+	return cfg
+}
+
+// New crea el Storage del driver configurado
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal:
+		return NewLocalStorage(cfg.Dir)
+	case DriverS3:
+		return NewS3Storage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("driver de almacenamiento no soportado: %q", cfg.Driver)
+	}
+}
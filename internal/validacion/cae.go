@@ -0,0 +1,28 @@
+package validacion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCAEFormato indica un CAE que no tiene la forma de los que emite AFIP
+var ErrCAEFormato = errors.New("formato de CAE inválido")
+
+// NormalizarCAE valida que el Código de Autorización Electrónico tenga 14 dígitos y lo devuelve sin
+// espacios. No consulta a AFIP: solo descarta errores de tipeo evidentes.
+func NormalizarCAE(valor string) (string, error) {
+	cae := strings.ReplaceAll(strings.TrimSpace(valor), " ", "")
+	if len(cae) != 14 {
+		return "", fmt.Errorf("%w: debe tener 14 dígitos y tiene %d", ErrCAEFormato, len(cae))
+	}
+	for _, r := range cae {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: %q contiene caracteres que no son dígitos", ErrCAEFormato, valor)
+		}
+	}
+	if strings.Trim(cae, "0") == "" {
+		return "", fmt.Errorf("%w: no puede ser todo ceros", ErrCAEFormato)
+	}
+	return cae, nil
+}
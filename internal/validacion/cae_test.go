@@ -0,0 +1,36 @@
+package validacion
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizarCAE(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		valor   string
+		want    string
+		wantErr error
+	}{
+		{"válido", "75341234567890", "75341234567890", nil},
+		{"con espacios", " 7534 1234 5678 90 ", "75341234567890", nil},
+		{"vacío", "", "", ErrCAEFormato},
+		{"corto", "7534123456789", "", ErrCAEFormato},
+		{"largo", "753412345678901", "", ErrCAEFormato},
+		{"con letras", "7534123456789A", "", ErrCAEFormato},
+		{"con guiones", "7534-123456789", "", ErrCAEFormato},
+		{"todo ceros", "00000000000000", "", ErrCAEFormato},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nombre, func(t *testing.T) {
+			got, err := NormalizarCAE(tc.valor)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("NormalizarCAE(%q) error = %v, se esperaba %v", tc.valor, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizarCAE(%q) = %q, se esperaba %q", tc.valor, got, tc.want)
+			}
+		})
+	}
+}
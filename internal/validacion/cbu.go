@@ -0,0 +1,92 @@
+package validacion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Motivos por los que un CBU/CVU o un alias pueden ser inválidos
+var (
+	ErrCBUVacio             = errors.New("el CBU/CVU es obligatorio")
+	ErrCBUFormato           = errors.New("formato de CBU/CVU inválido")
+	ErrCBULongitud          = errors.New("longitud de CBU/CVU inválida")
+	ErrCBUDigitoVerificador = errors.New("dígito verificador de CBU/CVU inválido")
+	ErrAliasFormato         = errors.New("formato de alias inválido")
+)
+
+// Pesos del BCRA para cada bloque: entidad y sucursal (7 dígitos), y número de cuenta (13 dígitos)
+var (
+	pesosBloqueEntidadCBU = []int{7, 1, 3, 9, 7, 1, 3}
+	pesosBloqueCuentaCBU  = []int{3, 9, 7, 1, 3, 9, 7, 1, 3, 9, 7, 1, 3}
+)
+
+// Reglas del BCRA para alias: de 6 a 20 caracteres, letras sin acentos ni ñ, números, punto y guion
+const (
+	largoMinimoAlias = 6
+	largoMaximoAlias = 20
+	caracteresAlias  = "abcdefghijklmnopqrstuvwxyz0123456789.-"
+)
+
+// NormalizarCBU valida un CBU o CVU y lo devuelve como 22 dígitos. Acepta espacios y guiones
+// entre los bloques. El error envuelve uno de los ErrCBU*.
+func NormalizarCBU(valor string) (string, error) {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return "", ErrCBUVacio
+	}
+
+	cbu := strings.NewReplacer(" ", "", "-", "").Replace(valor)
+	for _, r := range cbu {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: %q contiene caracteres que no son dígitos", ErrCBUFormato, valor)
+		}
+	}
+
+	if len(cbu) != 22 {
+		return "", fmt.Errorf("%w: debe tener 22 dígitos y tiene %d", ErrCBULongitud, len(cbu))
+	}
+	if strings.Trim(cbu, "0") == "" {
+		return "", fmt.Errorf("%w: no puede ser todo ceros", ErrCBUFormato)
+	}
+
+	if dv := digitoVerificadorCBU(cbu[:7], pesosBloqueEntidadCBU); int(cbu[7]-'0') != dv {
+		return "", fmt.Errorf("%w: el primer bloque termina en %c y debería terminar en %d", ErrCBUDigitoVerificador, cbu[7], dv)
+	}
+	if dv := digitoVerificadorCBU(cbu[8:21], pesosBloqueCuentaCBU); int(cbu[21]-'0') != dv {
+		return "", fmt.Errorf("%w: el segundo bloque termina en %c y debería terminar en %d", ErrCBUDigitoVerificador, cbu[21], dv)
+	}
+
+	return cbu, nil
+}
+
+// EsCVU indica si un CBU normalizado es de una cuenta virtual (billetera): las entidades
+// bancarias nunca tienen código 000
+func EsCVU(cbu string) bool {
+	return strings.HasPrefix(cbu, "000")
+}
+
+// NormalizarAlias valida un alias de CBU/CVU y lo devuelve en minúsculas, que es como lo
+// resuelven los bancos. El error envuelve ErrAliasFormato.
+func NormalizarAlias(valor string) (string, error) {
+	alias := strings.ToLower(strings.TrimSpace(valor))
+	if n := len([]rune(alias)); n < largoMinimoAlias || n > largoMaximoAlias {
+		return "", fmt.Errorf("%w: debe tener entre %d y %d caracteres y tiene %d",
+			ErrAliasFormato, largoMinimoAlias, largoMaximoAlias, n)
+	}
+	for _, r := range alias {
+		if !strings.ContainsRune(caracteresAlias, r) {
+			return "", fmt.Errorf("%w: no admite %q, solo letras sin acentos, números, punto y guion", ErrAliasFormato, r)
+		}
+	}
+	return alias, nil
+}
+
+// digitoVerificadorCBU calcula el dígito verificador módulo 10 de un bloque
+func digitoVerificadorCBU(base string, pesos []int) int {
+	suma := 0
+	for i, peso := range pesos {
+		suma += int(base[i]-'0') * peso
+	}
+	return (10 - suma%10) % 10
+}
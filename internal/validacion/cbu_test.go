@@ -0,0 +1,85 @@
+package validacion
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizarCBU(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		valor   string
+		want    string
+		wantErr error
+	}{
+		{"CBU bancario", "2850590994009004181356", "2850590994009004181356", nil},
+		{"CVU", "0000003110000000000007", "0000003110000000000007", nil},
+		{"con espacios y guiones", " 28505909-9400900418135 6", "2850590994009004181356", nil},
+		{"vacío", "", "", ErrCBUVacio},
+		{"letras", "285059099400900418135A", "", ErrCBUFormato},
+		{"todo ceros", "0000000000000000000000", "", ErrCBUFormato},
+		{"corto", "285059099400900418135", "", ErrCBULongitud},
+		{"largo", "28505909940090041813560", "", ErrCBULongitud},
+		{"primer bloque incorrecto", "2850590894009004181356", "", ErrCBUDigitoVerificador},
+		{"segundo bloque incorrecto", "2850590994009004181355", "", ErrCBUDigitoVerificador},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nombre, func(t *testing.T) {
+			got, err := NormalizarCBU(tc.valor)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("NormalizarCBU(%q) error = %v, se esperaba %v", tc.valor, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizarCBU(%q) = %q, se esperaba %q", tc.valor, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEsCVU(t *testing.T) {
+	casos := []struct {
+		cbu  string
+		want bool
+	}{
+		{"0000003110000000000007", true},
+		{"2850590994009004181356", false},
+	}
+
+	for _, tc := range casos {
+		if got := EsCVU(tc.cbu); got != tc.want {
+			t.Errorf("EsCVU(%q) = %v, se esperaba %v", tc.cbu, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizarAlias(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		valor   string
+		want    string
+		wantErr error
+	}{
+		{"minúsculas", "dani.mp", "dani.mp", nil},
+		{"pasa a minúsculas", " Casa.Perro-Sol ", "casa.perro-sol", nil},
+		{"largo mínimo", "abc123", "abc123", nil},
+		{"largo máximo", "abcdefghij0123456789", "abcdefghij0123456789", nil},
+		{"corto", "abc12", "", ErrAliasFormato},
+		{"largo", "abcdefghij0123456789x", "", ErrAliasFormato},
+		{"con tilde", "canción.uno", "", ErrAliasFormato},
+		{"con eñe", "montaña.sol", "", ErrAliasFormato},
+		{"con espacio", "casa perro", "", ErrAliasFormato},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nombre, func(t *testing.T) {
+			got, err := NormalizarAlias(tc.valor)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("NormalizarAlias(%q) error = %v, se esperaba %v", tc.valor, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizarAlias(%q) = %q, se esperaba %q", tc.valor, got, tc.want)
+			}
+		})
+	}
+}
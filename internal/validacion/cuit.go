@@ -0,0 +1,91 @@
+package validacion
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Motivos por los que un CUIT/CUIL puede ser inválido
+var (
+	ErrCUITVacio             = errors.New("el CUIT es obligatorio")
+	ErrCUITFormato           = errors.New("formato de CUIT inválido")
+	ErrCUITLongitud          = errors.New("longitud de CUIT inválida")
+	ErrCUITPrefijo           = errors.New("tipo de CUIT inválido")
+	ErrCUITDigitoVerificador = errors.New("dígito verificador de CUIT inválido")
+)
+
+// Prefijos de tipo admitidos: personas humanas (20, 23, 24, 27) y jurídicas (30, 33, 34)
+var prefijosCUIT = []string{"20", "23", "24", "27", "30", "33", "34"}
+
+var pesosCUIT = [10]int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+
+// NormalizarCUIT valida un CUIT/CUIL y lo devuelve como 11 dígitos sin guiones.
+// Acepta "20251234567" y "20-25123456-7". El error envuelve uno de los ErrCUIT*.
+func NormalizarCUIT(valor string) (string, error) {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return "", ErrCUITVacio
+	}
+
+	cuit := valor
+	if strings.Contains(valor, "-") {
+		partes := strings.Split(valor, "-")
+		if len(partes) != 3 || len(partes[0]) != 2 || len(partes[1]) != 8 || len(partes[2]) != 1 {
+			return "", fmt.Errorf("%w: %q, se espera 11 dígitos o el formato XX-XXXXXXXX-X", ErrCUITFormato, valor)
+		}
+		cuit = strings.Join(partes, "")
+	}
+
+	for _, r := range cuit {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: %q contiene caracteres que no son dígitos", ErrCUITFormato, valor)
+		}
+	}
+
+	if len(cuit) != 11 {
+		return "", fmt.Errorf("%w: debe tener 11 dígitos y tiene %d", ErrCUITLongitud, len(cuit))
+	}
+
+	if !slices.Contains(prefijosCUIT, cuit[:2]) {
+		return "", fmt.Errorf("%w: el prefijo %s no existe, los válidos son %s",
+			ErrCUITPrefijo, cuit[:2], strings.Join(prefijosCUIT, ", "))
+	}
+
+	esperado, ok := digitoVerificadorCUIT(cuit[:10])
+	if !ok {
+		return "", fmt.Errorf("%w: el número %s no tiene dígito verificador posible con el prefijo %s", ErrCUITDigitoVerificador, cuit[2:10], cuit[:2])
+	}
+	if int(cuit[10]-'0') != esperado {
+		return "", fmt.Errorf("%w: termina en %c y debería terminar en %d", ErrCUITDigitoVerificador, cuit[10], esperado)
+	}
+
+	return cuit, nil
+}
+
+// FormatearCUIT devuelve un CUIT normalizado con guiones: 20-25123456-7
+func FormatearCUIT(cuit string) string {
+	if len(cuit) != 11 {
+		return cuit
+	}
+	return cuit[:2] + "-" + cuit[2:10] + "-" + cuit[10:]
+}
+
+// digitoVerificadorCUIT calcula el dígito verificador módulo 11 de los primeros 10 dígitos.
+// Devuelve false cuando el resultado es 10: AFIP no asigna esos números con ese prefijo.
+func digitoVerificadorCUIT(base string) (int, bool) {
+	suma := 0
+	for i, peso := range pesosCUIT {
+		suma += int(base[i]-'0') * peso
+	}
+
+	switch dv := 11 - suma%11; dv {
+	case 11:
+		return 0, true
+	case 10:
+		return 0, false
+	default:
+		return dv, true
+	}
+}
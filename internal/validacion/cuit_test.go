@@ -0,0 +1,58 @@
+package validacion
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizarCUIT(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		valor   string
+		want    string
+		wantErr error
+	}{
+		{"sin guiones", "20251234567", "20251234567", nil},
+		{"con guiones", "20-25123456-7", "20251234567", nil},
+		{"con espacios alrededor", "  30708889993 ", "30708889993", nil},
+		{"persona jurídica", "30-71234567-1", "30712345671", nil},
+		{"dígito verificador 0", "27000000006", "27000000006", nil},
+		{"vacío", "", "", ErrCUITVacio},
+		{"solo espacios", "   ", "", ErrCUITVacio},
+		{"guiones mal ubicados", "202-5123456-7", "", ErrCUITFormato},
+		{"letras", "20A51234567", "", ErrCUITFormato},
+		{"corto", "2025123456", "", ErrCUITLongitud},
+		{"largo", "202512345678", "", ErrCUITLongitud},
+		{"prefijo inexistente", "21251234567", "", ErrCUITPrefijo},
+		{"dígito verificador incorrecto", "20251234568", "", ErrCUITDigitoVerificador},
+		{"número sin dígito posible", "20100000050", "", ErrCUITDigitoVerificador},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nombre, func(t *testing.T) {
+			got, err := NormalizarCUIT(tc.valor)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("NormalizarCUIT(%q) error = %v, se esperaba %v", tc.valor, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizarCUIT(%q) = %q, se esperaba %q", tc.valor, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatearCUIT(t *testing.T) {
+	casos := []struct {
+		cuit string
+		want string
+	}{
+		{"20251234567", "20-25123456-7"},
+		{"123", "123"},
+	}
+
+	for _, tc := range casos {
+		if got := FormatearCUIT(tc.cuit); got != tc.want {
+			t.Errorf("FormatearCUIT(%q) = %q, se esperaba %q", tc.cuit, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package validacion
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizarTexto pasa a minúsculas y quita tildes y diacríticos ("García" -> "garcia") para comparar
+// y buscar sin distinguirlos. No recorta espacios.
+func NormalizarTexto(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		out = s
+	}
+	return strings.ToLower(out)
+}